@@ -0,0 +1,733 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	cloudBaseURLTemplate = "https://%s.secretservercloud.%s/"
+	apiPathURI           = "api/v1"
+	tokenPathURI         = "oauth2/token"
+
+	platformTokenPathURI  = "identity/api/oauth2/token/xpmplatform"
+	platformVaultsPathURI = "vaultbroker/api/vaults"
+	platformDomainSuffix  = ".delinea.app"
+)
+
+// apiError is returned by apiClient when Secret Server responds with a non-2xx status
+type apiError struct {
+	StatusCode int
+	Status     string
+	Body       string
+	// RetryAfter is the delay requested by a Retry-After header, if any
+	RetryAfter time.Duration
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Body)
+}
+
+// isNotFound reports whether err is an apiError with a 404 status
+func isNotFound(err error) bool {
+	if apiErr, ok := err.(*apiError); ok {
+		return apiErr.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// explainAuthError adds a hint to a 401 response when the provider authenticates with a
+// pre-issued access token, since those cannot be renewed and expiry is the usual cause
+func explainAuthError(err error, tokenAuth bool) error {
+	if err == nil || !tokenAuth || errorStatusCode(err) != http.StatusUnauthorized {
+		return err
+	}
+	return fmt.Errorf("%w (the access token configured with token or TSS_TOKEN was rejected, most likely because it has expired; "+
+		"issue a new token and update the provider configuration)", err)
+}
+
+// apiClient calls the Secret Server REST endpoints that the SDK does not wrap.
+// It authenticates with the same configuration as the SDK server client.
+type apiClient struct {
+	config     server.Configuration
+	httpClient *http.Client
+	// transport carries the provider configuration's TLS, proxy and connect
+	// timeout settings; nil uses the default transport
+	transport *providerTransport
+
+	// clientID and clientSecret are the credentials of an SDK client account,
+	// used with the client credentials grant instead of the user's password
+	clientID     string
+	clientSecret string
+
+	// platform is set for Delinea Platform tenants, where tokens are issued by the
+	// platform and the API is served by the tenant's default vault
+	platform bool
+
+	// oidc supplies a CI job's ID token to exchange for an access token
+	oidc *oidcTokenSource
+	// defaults are the retry and timeout policy of calls whose context carries
+	// none. The timeout bounds each attempt, including fetching a token; the
+	// zero value makes a single attempt and waits indefinitely.
+	defaults callOptions
+	// limiter is shared with the SDK calls; nil does not limit
+	limiter *requestLimiter
+	// health gates the first write on a health check; nil does not check
+	health *healthGate
+	// pageSize is the number of records requested per page of a list; zero
+	// uses defaultPageSize
+	pageSize int
+	// offline is set while planning with an unknown provider configuration; the
+	// client then makes no requests
+	offline bool
+	// audit records every call in the file set with audit_log_path; nil
+	// records nothing
+	audit *auditLog
+	// budget caps the calls of the run; nil does not
+	budget *callBudget
+	// tracer records a span for every call; nil records none
+	tracer trace.Tracer
+	// domains are tried in order by the password grant until one accepts the
+	// credentials; empty uses the configured domain
+	domains []string
+	// impersonation makes reads act as another user; nil reads as the
+	// authenticated account
+	impersonation *impersonation
+	// historyLengths caches the field history retention of each template read
+	historyLengths *lookupCache[map[int]int]
+	// folderPaths caches the path of each folder read
+	folderPaths *lookupCache[string]
+
+	// authSession caches the access token; clients with the same endpoint and
+	// credentials share it
+	*authSession
+}
+
+// newAPIClient returns an apiClient sharing the configuration of the given SDK server
+func newAPIClient(s *server.Server) *apiClient {
+	return &apiClient{
+		config:         s.Configuration,
+		httpClient:     &http.Client{},
+		authSession:    &authSession{},
+		historyLengths: &lookupCache[map[int]int]{},
+		folderPaths:    &lookupCache[string]{},
+	}
+}
+
+// useTransport makes the client send its requests through transport
+func (c *apiClient) useTransport(transport *providerTransport) {
+	c.transport = transport
+	c.httpClient = &http.Client{Transport: transport}
+}
+
+// baseURL returns the Secret Server base URL without a trailing slash
+func (c *apiClient) baseURL() string {
+	if c.config.ServerURL != "" {
+		return strings.TrimRight(c.config.ServerURL, "/")
+	}
+	tld := c.config.TLD
+	if tld == "" {
+		tld = "com"
+	}
+	return strings.TrimRight(fmt.Sprintf(cloudBaseURLTemplate, c.config.Tenant, tld), "/")
+}
+
+// isPlatformURL reports whether serverURL is a Delinea Platform tenant URL
+func isPlatformURL(serverURL string) bool {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(u.Hostname()), platformDomainSuffix)
+}
+
+// usePlatform makes the client authenticate with the Delinea Platform
+func (c *apiClient) usePlatform() {
+	c.platform = true
+}
+
+// useOIDC makes the client authenticate by exchanging a CI job's OIDC ID token.
+// The ID token is requested from the CI system rather than Secret Server, so
+// not through the configuration's transport and its TLS settings.
+func (c *apiClient) useOIDC(tokenFile, audience string) {
+	c.oidc = &oidcTokenSource{tokenFile: tokenFile, audience: audience, httpClient: &http.Client{Transport: baseTransport}}
+}
+
+// sdkServer returns the SDK server to call, authenticated with this client's cached
+// token so that SDK calls do not each request their own. s is copied rather than
+// changed, since it is shared.
+func (c *apiClient) sdkServer(ctx context.Context, s *server.Server, method string) (*server.Server, error) {
+	if c.offline {
+		return nil, errOffline
+	}
+	if c.config.Credentials.Token != "" && !c.impersonates(method) {
+		return s, nil
+	}
+
+	token, err := c.callToken(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	copied := *s
+	copied.Credentials.Token = token
+	if c.platform {
+		if copied.ServerURL, err = c.apiBaseURL(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return &copied, nil
+}
+
+// apiBaseURL returns the base URL the REST API is served from. On the Delinea
+// Platform that is the tenant's default vault.
+func (c *apiClient) apiBaseURL(ctx context.Context) (string, error) {
+	if !c.platform {
+		return c.baseURL(), nil
+	}
+
+	c.mu.Lock()
+	vaultURL := c.vaultURL
+	c.mu.Unlock()
+	if vaultURL != "" {
+		return vaultURL, nil
+	}
+
+	accessToken, err := c.token(ctx)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL()+"/"+platformVaultsPathURI, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	data, err := c.send(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to list platform vaults: %w", err)
+	}
+
+	var vaults struct {
+		Vaults []struct {
+			IsDefault  bool
+			IsActive   bool
+			Connection struct {
+				URL string `json:"url"`
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &vaults); err != nil {
+		return "", fmt.Errorf("failed to parse platform vaults: %w", err)
+	}
+	for _, vault := range vaults.Vaults {
+		if vault.IsDefault && vault.IsActive {
+			vaultURL = strings.TrimRight(vault.Connection.URL, "/")
+			break
+		}
+	}
+	if vaultURL == "" {
+		return "", fmt.Errorf("the platform tenant has no active default vault")
+	}
+	// The SDK calls the vault too, through the same transport
+	if err := transports.route(vaultURL, c.transport); err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.vaultURL = vaultURL
+	c.mu.Unlock()
+	return vaultURL, nil
+}
+
+// useClientCredentials makes the client authenticate as an SDK client account
+func (c *apiClient) useClientCredentials(clientID, clientSecret string) {
+	c.clientID = clientID
+	c.clientSecret = clientSecret
+}
+
+// usesClientCredentials reports whether the client authenticates as an SDK client account
+func (c *apiClient) usesClientCredentials() bool {
+	return c.clientID != ""
+}
+
+// token returns an access token. The token is shared by every call the provider
+// makes: it is renewed in the background once most of its lifetime has passed,
+// and only requested while the caller waits when none is cached or it has expired.
+func (c *apiClient) token(ctx context.Context) (string, error) {
+	if c.config.Credentials.Token != "" {
+		return c.config.Credentials.Token, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.accessToken != "" && now.Before(c.expiresAt) {
+		if now.After(c.refreshAt) && !c.refreshing {
+			c.refreshing = true
+			go c.refreshToken(context.WithoutCancel(ctx))
+		}
+		return c.accessToken, nil
+	}
+
+	accessToken, lifetime, err := c.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.storeToken(accessToken, lifetime)
+	return c.accessToken, nil
+}
+
+// refreshToken replaces the cached token with a new one. A failure is only
+// logged, since the cached token stays valid for a while and is requested again
+// once it has expired.
+func (c *apiClient) refreshToken(ctx context.Context) {
+	accessToken, lifetime, err := c.requestToken(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshing = false
+	if err != nil {
+		tflog.Warn(ctx, "Failed to renew the access token", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.storeToken(accessToken, lifetime)
+	tflog.Debug(ctx, "Renewed the access token")
+}
+
+// storeToken caches an access token that is valid for lifetime. c.mu must be held.
+func (c *apiClient) storeToken(accessToken string, lifetime time.Duration) {
+	now := time.Now()
+	c.accessToken = accessToken
+	// Stop using the token a little early so it never expires mid-request, and
+	// renew it well before then
+	c.expiresAt = now.Add(lifetime * 9 / 10)
+	c.refreshAt = now.Add(lifetime * 3 / 4)
+}
+
+// requestToken requests a new access token with the password, client credentials
+// or token exchange grant, returning it with its lifetime
+func (c *apiClient) requestToken(ctx context.Context) (string, time.Duration, error) {
+	values := url.Values{
+		"username":   {c.config.Credentials.Username},
+		"password":   {c.config.Credentials.Password},
+		"grant_type": {"password"},
+	}
+	if c.config.Credentials.Domain != "" {
+		values.Set("domain", c.config.Credentials.Domain)
+	}
+	tokenURL := c.baseURL() + "/" + tokenPathURI
+	if c.oidc != nil {
+		idToken, err := c.oidc.idToken(ctx)
+		if err != nil {
+			return "", 0, err
+		}
+		values = url.Values{
+			"grant_type":         {tokenExchangeGrantType},
+			"subject_token":      {idToken},
+			"subject_token_type": {idTokenType},
+		}
+	} else if c.usesClientCredentials() {
+		values = url.Values{
+			"client_id":     {c.clientID},
+			"client_secret": {c.clientSecret},
+			"grant_type":    {"client_credentials"},
+		}
+	} else if c.platform {
+		// Platform service users authenticate with their username and password
+		// as client credentials
+		values = url.Values{
+			"client_id":     {c.config.Credentials.Username},
+			"client_secret": {c.config.Credentials.Password},
+			"grant_type":    {"client_credentials"},
+			"scope":         {"xpmheadless"},
+		}
+		tokenURL = c.baseURL() + "/" + platformTokenPathURI
+	} else {
+		return c.passwordGrant(ctx, tokenURL, values)
+	}
+
+	return c.grant(ctx, tokenURL, values)
+}
+
+// grant posts a token request to tokenURL, returning the access token with its lifetime
+func (c *apiClient) grant(ctx context.Context, tokenURL string, values url.Values) (string, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	data, err := c.send(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to obtain access token: %w", err)
+	}
+
+	grant := struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}{}
+	if err := json.Unmarshal(data, &grant); err != nil {
+		return "", 0, fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	return grant.AccessToken, time.Duration(grant.ExpiresIn) * time.Second, nil
+}
+
+// defaultPageSize is the number of records requested per page when api_page_size is not set
+const defaultPageSize = 100
+
+// take returns the number of records to request per page of a list
+func (c *apiClient) take() int {
+	if c.pageSize > 0 {
+		return c.pageSize
+	}
+	return defaultPageSize
+}
+
+// do calls the API at path (relative to /api/v1) and decodes the JSON response into output when it is not nil.
+// An output of type *[]byte receives the response body as it is, e.g. the content of a file attachment.
+func (c *apiClient) do(ctx context.Context, method, path string, input, output interface{}) error {
+	if c.offline {
+		return errOffline
+	}
+	if method != http.MethodGet {
+		if err := c.ensureHealthy(ctx); err != nil {
+			return err
+		}
+	}
+
+	var payload []byte
+	if input != nil {
+		var err error
+		payload, err = json.Marshal(input)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	// The timeout is applied to each attempt through its context, which unlike
+	// the SDK's calls these take, so an attempt that times out is cancelled
+	opts := callOptionsFrom(ctx, c.defaults)
+	ctx, span := c.startSpan(ctx, method, path)
+	attempts := 0
+	data, err := withRetry(ctx, method, callOptions{Retry: opts.Retry}, func() ([]byte, error) {
+		attempts++
+		ctx := ctx
+		if opts.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+			defer cancel()
+		}
+		accessToken, err := c.callToken(ctx, method)
+		if err != nil {
+			return nil, err
+		}
+
+		baseURL, err := c.apiBaseURL(ctx)
+		if err != nil {
+			return nil, err
+		}
+		endpoint := fmt.Sprintf("%s/%s/%s", baseURL, apiPathURI, strings.TrimLeft(path, "/"))
+
+		var body io.Reader
+		if payload != nil {
+			body = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		if err := c.budget.spend(); err != nil {
+			return nil, err
+		}
+		release, err := c.limiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		tflog.Trace(ctx, "Calling Secret Server API", map[string]interface{}{
+			"method": method,
+			"path":   path,
+		})
+
+		start := time.Now()
+		data, err := c.send(req)
+		c.audit.record(c.baseURL(), method, path, payload, start, err)
+		return data, err
+	})
+	endSpan(span, attempts, err)
+	if err != nil {
+		return explainAuthError(err, c.config.Credentials.Token != "")
+	}
+
+	if output == nil || len(data) == 0 {
+		return nil
+	}
+	if raw, ok := output.(*[]byte); ok {
+		*raw = data
+		return nil
+	}
+	if err := json.Unmarshal(data, output); err != nil {
+		return fmt.Errorf("failed to parse response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// send performs the request and returns the body of a 2xx response
+func (c *apiClient) send(req *http.Request) ([]byte, error) {
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		// Keep error messages readable when the server returns an HTML page
+		if len(data) > 255 {
+			data = append(data[:255], []byte("...")...)
+		}
+		return nil, &apiError{
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			Body:       string(data),
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	return data, nil
+}
+
+// registerSDKClient registers clientID as an SDK client account under an onboarding
+// rule and returns the client secret issued for it
+func (c *apiClient) registerSDKClient(ctx context.Context, clientID, ruleName, onboardingKey string) (string, error) {
+	body := map[string]interface{}{
+		"clientId":    clientID,
+		"name":        clientID,
+		"description": "Registered by the Terraform provider",
+		"ruleName":    ruleName,
+	}
+	if onboardingKey != "" {
+		body["onboardingKey"] = onboardingKey
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/sdk-client-accounts", c.baseURL(), apiPathURI)
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	// Registration is anonymous; the onboarding rule and key authorize it
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	data, err = c.send(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to register SDK client: %w", err)
+	}
+
+	var account struct {
+		ClientSecret string
+	}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return "", fmt.Errorf("failed to parse SDK client registration response: %w", err)
+	}
+	if account.ClientSecret == "" {
+		return "", fmt.Errorf("SDK client registration did not return a client secret")
+	}
+	return account.ClientSecret, nil
+}
+
+// folderPath returns the full path of a folder, e.g. \IT\Prod\DBs. Secrets
+// outside of any folder have folder ID -1, which is the root. Each folder is
+// read once per client.
+func (c *apiClient) folderPath(ctx context.Context, folderID int) (string, error) {
+	if folderID <= 0 {
+		return "\\", nil
+	}
+
+	return c.folderPaths.get(folderID, func() (string, error) {
+		var folder struct {
+			FolderPath string
+		}
+		if err := c.do(ctx, "GET", fmt.Sprintf("folders/%d", folderID), nil, &folder); err != nil {
+			return "", err
+		}
+		return folder.FolderPath, nil
+	})
+}
+
+// folderPathValue returns the path of a folder for a folderpath attribute, or
+// null when it cannot be read, as the path is informational
+func (c *apiClient) folderPathValue(ctx context.Context, folderID int) types.String {
+	folderPath, err := c.folderPath(ctx, folderID)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to resolve folder path", map[string]interface{}{
+			"folder_id": folderID,
+			"error":     err.Error(),
+		})
+		return types.StringNull()
+	}
+	return types.StringValue(folderPath)
+}
+
+// templateHistoryLengths returns the number of previous values each field of a
+// template retains, by field ID. Each template is read once per client.
+func (c *apiClient) templateHistoryLengths(ctx context.Context, templateID int) (map[int]int, error) {
+	return c.historyLengths.get(templateID, func() (map[int]int, error) {
+		var template struct {
+			Fields []struct {
+				SecretTemplateFieldID int
+				HistoryLength         int
+			}
+		}
+		if err := c.do(ctx, "GET", fmt.Sprintf("secret-templates/%d", templateID), nil, &template); err != nil {
+			return nil, err
+		}
+		historyLengths := make(map[int]int, len(template.Fields))
+		for _, f := range template.Fields {
+			historyLengths[f.SecretTemplateFieldID] = f.HistoryLength
+		}
+		return historyLengths, nil
+	})
+}
+
+// secretStatus is the status information returned by the secret summary endpoint
+type secretStatus struct {
+	FolderID                  int
+	LastPasswordChangeAttempt string
+	OutOfSync                 bool
+	OutOfSyncReason           string
+	CheckOutEnabled           bool
+	RequiresComment           bool
+	RequiresApproval          bool
+	Active                    bool
+}
+
+// secretStatus returns the password change status and access requirements of a secret
+func (c *apiClient) secretStatus(ctx context.Context, secretID int) (*secretStatus, error) {
+	status := new(secretStatus)
+	if err := c.do(ctx, "GET", fmt.Sprintf("secrets/%d/summary", secretID), nil, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// errNeverChanged reports a secret whose password has never been changed by Secret Server
+var errNeverChanged = errors.New("the password has never been changed")
+
+// lastSuccessfulChange returns when the password was last changed successfully. The
+// summary only dates the latest attempt, which was successful unless the secret is
+// out of sync; after a failed attempt the date of the last success is not known.
+func (s *secretStatus) lastSuccessfulChange() (time.Time, error) {
+	if s.LastPasswordChangeAttempt == "" {
+		return time.Time{}, errNeverChanged
+	}
+	if s.OutOfSync {
+		reason := s.OutOfSyncReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		return time.Time{}, fmt.Errorf("the last password change attempt at %s failed (%s)", s.LastPasswordChangeAttempt, reason)
+	}
+	return parseServerTime(s.LastPasswordChangeAttempt)
+}
+
+// secretSearchFilter narrows a secret search
+type secretSearchFilter struct {
+	FolderID          int
+	IncludeSubFolders bool
+	TemplateID        int
+	SearchText        string
+	// IncludeInactive also returns deactivated secrets, which are otherwise left out
+	IncludeInactive bool
+}
+
+// secretSummary is a secret as returned by the search endpoint
+type secretSummary struct {
+	ID               int
+	Name             string
+	FolderID         int
+	SecretTemplateID int
+	Active           bool
+	CheckedOut       bool
+}
+
+// searchSecrets returns every secret matching filter, following pagination
+func (c *apiClient) searchSecrets(ctx context.Context, filter secretSearchFilter) ([]secretSummary, error) {
+	pageSize := c.take()
+
+	query := url.Values{}
+	if filter.FolderID != 0 {
+		query.Set("filter.folderId", strconv.Itoa(filter.FolderID))
+		query.Set("filter.includeSubFolders", strconv.FormatBool(filter.IncludeSubFolders))
+	}
+	if filter.TemplateID != 0 {
+		query.Set("filter.secretTemplateId", strconv.Itoa(filter.TemplateID))
+	}
+	if filter.SearchText != "" {
+		query.Set("filter.searchText", filter.SearchText)
+	}
+	if filter.IncludeInactive {
+		query.Set("filter.includeInactive", "true")
+	}
+	query.Set("take", strconv.Itoa(pageSize))
+
+	var secrets []secretSummary
+	for skip := 0; ; skip += pageSize {
+		query.Set("skip", strconv.Itoa(skip))
+
+		var page struct {
+			Records []secretSummary
+			HasNext bool
+		}
+		if err := c.do(ctx, "GET", "secrets?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, page.Records...)
+
+		if !page.HasNext || len(page.Records) == 0 {
+			return secrets, nil
+		}
+	}
+}
+
+// parseServerTime parses a timestamp returned by Secret Server. Timestamps
+// without a zone are in UTC.
+func parseServerTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", value)
+}
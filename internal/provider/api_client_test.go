@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+)
+
+// newTestAPIClient returns an apiClient calling serverURL with a fixed token
+func newTestAPIClient(serverURL string) *apiClient {
+	return newAPIClient(&server.Server{Configuration: server.Configuration{
+		ServerURL:   serverURL,
+		Credentials: server.UserCredential{Token: "token"},
+	}})
+}
+
+// slowFirstServer delays its first response by delay and answers the others at once
+func slowFirstServer(delay time.Duration, calls *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+			}
+		}
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+}
+
+func TestDoAppliesTimeoutPerAttempt(t *testing.T) {
+	var calls atomic.Int32
+	ts := slowFirstServer(time.Second, &calls)
+	defer ts.Close()
+
+	c := newTestAPIClient(ts.URL)
+	c.defaults = callOptions{Retry: retryConfig{MaxAttempts: 2}, Timeout: 100 * time.Millisecond}
+
+	var out struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(context.Background(), http.MethodGet, "secrets/1", nil, &out); err != nil {
+		t.Fatalf("the second attempt did not get its own timeout: %s", err)
+	}
+	if out.ID != 1 || calls.Load() != 2 {
+		t.Errorf("got id %d after %d calls, want id 1 after 2", out.ID, calls.Load())
+	}
+}
+
+func TestDoAppliesCallOptionsOfContext(t *testing.T) {
+	var calls atomic.Int32
+	ts := slowFirstServer(200*time.Millisecond, &calls)
+	defer ts.Close()
+
+	c := newTestAPIClient(ts.URL)
+	c.defaults = callOptions{Retry: retryConfig{MaxAttempts: 1}, Timeout: 50 * time.Millisecond}
+
+	// The resource's timeout replaces the provider's
+	ctx := withCallOptions(context.Background(), callOptions{Retry: retryConfig{MaxAttempts: 1}, Timeout: 5 * time.Second})
+	if err := c.do(ctx, http.MethodGet, "secrets/1", nil, nil); err != nil {
+		t.Errorf("the call did not take the timeout of its context: %s", err)
+	}
+
+	// A POST that timed out is not repeated
+	calls.Store(0)
+	ctx = withCallOptions(context.Background(), callOptions{Retry: retryConfig{MaxAttempts: 3}, Timeout: 50 * time.Millisecond})
+	if err := c.do(ctx, http.MethodPost, "users", map[string]string{"userName": "test"}, nil); err == nil {
+		t.Error("a POST that timed out succeeded")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("a POST that timed out was sent %d times", calls.Load())
+	}
+}
+
+func TestFolderPathIsReadOncePerClient(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`{"FolderPath":"\\IT\\Prod"}`))
+	}))
+	defer ts.Close()
+
+	c := newTestAPIClient(ts.URL)
+	for i := 0; i < 3; i++ {
+		if path := c.folderPathValue(context.Background(), 12); path.ValueString() != `\IT\Prod` {
+			t.Errorf("folder path is %s", path)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("the folder was read %d times", calls.Load())
+	}
+}
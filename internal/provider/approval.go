@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultApprovalTimeout      = 30 * time.Minute
+	defaultApprovalPollInterval = 30 * time.Second
+)
+
+// AwaitApprovalModel is the await_approval block of the secret resource
+type AwaitApprovalModel struct {
+	Timeout      types.String `tfsdk:"timeout"`
+	PollInterval types.String `tfsdk:"poll_interval"`
+}
+
+// awaitApprovalBlock returns the await_approval block schema
+func awaitApprovalBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Waits for a pending approval request when a write requires approval, then repeats the write. " +
+			"Without this block, a write that requires approval fails with the approval request ID.",
+		Attributes: map[string]schema.Attribute{
+			"timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long to wait for the request to be approved, e.g. \"1h\". Defaults to \"30m\".",
+			},
+			"poll_interval": schema.StringAttribute{
+				Optional:    true,
+				Description: "How often to check the status of the request, e.g. \"1m\". Defaults to \"30s\".",
+			},
+		},
+	}
+}
+
+// approvalRequiredError is returned when Secret Server queues a write for approval
+type approvalRequiredError struct {
+	RequestID int
+	Message   string
+}
+
+func (e *approvalRequiredError) Error() string {
+	if e.RequestID == 0 {
+		return fmt.Sprintf("approval required: %s", e.Message)
+	}
+	return fmt.Sprintf("approval request %d is pending: %s", e.RequestID, e.Message)
+}
+
+// approvalResponse is the body of a write that Secret Server queued for
+// approval, e.g. {"message":"... requires approval.","requestId":42}
+type approvalResponse struct {
+	Message   string `json:"message"`
+	RequestID int    `json:"requestId"`
+}
+
+// asApprovalRequired reports whether err means the write is awaiting approval
+func asApprovalRequired(err error) (*approvalRequiredError, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var approvalErr *approvalRequiredError
+	if errors.As(err, &approvalErr) {
+		return approvalErr, true
+	}
+
+	code := errorStatusCode(err)
+	if code != 400 && code != 403 {
+		return nil, false
+	}
+	message := err.Error()
+	if !strings.Contains(strings.ToLower(message), "approval") {
+		return nil, false
+	}
+
+	approvalErr = &approvalRequiredError{Message: message}
+	var response approvalResponse
+	if json.Unmarshal([]byte(errorBody(err)), &response) == nil {
+		approvalErr.RequestID = response.RequestID
+	}
+	return approvalErr, true
+}
+
+// errorBody returns the response body of a failed Secret Server call. The SDK
+// only returns it in the error message, after the status.
+func errorBody(err error) string {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Body
+	}
+	if _, body, ok := strings.Cut(err.Error(), ": "); ok {
+		return body
+	}
+	return ""
+}
+
+// approvalRequiredDiagnostic is reported when a write requires approval and
+// await_approval is not set, or the approval did not arrive in time
+func approvalRequiredDiagnostic(action string, err *approvalRequiredError) diag.Diagnostic {
+	request := "An approval request"
+	if err.RequestID != 0 {
+		request = fmt.Sprintf("Approval request %d", err.RequestID)
+	}
+	return diag.NewErrorDiagnostic("Secret Approval Required",
+		fmt.Sprintf("Secret Server requires approval to %s the secret. %s must be approved before the change can be applied. "+
+			"Apply again once it is approved, or add an await_approval block to wait for it.\n\nServer response: %s", action, request, err.Message))
+}
+
+// approvalWait is the parsed await_approval block
+type approvalWait struct {
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// parseAwaitApproval validates the await_approval block. It returns nil when the block is absent.
+func parseAwaitApproval(model *AwaitApprovalModel) (*approvalWait, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if model == nil {
+		return nil, diags
+	}
+
+	wait := &approvalWait{Timeout: defaultApprovalTimeout, PollInterval: defaultApprovalPollInterval}
+	parse := func(name string, value types.String, dst *time.Duration) {
+		if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+			return
+		}
+		d, err := time.ParseDuration(value.ValueString())
+		if err != nil || d <= 0 {
+			diags.AddAttributeError(path.Root("await_approval").AtName(name), "Invalid Duration",
+				fmt.Sprintf("%q is not a valid duration such as \"30s\" or \"1h\".", value.ValueString()))
+			return
+		}
+		*dst = d
+	}
+	parse("timeout", model.Timeout, &wait.Timeout)
+	parse("poll_interval", model.PollInterval, &wait.PollInterval)
+
+	return wait, diags
+}
+
+// accessRequestStatus returns the status of an approval request, e.g. "Pending" or "Approved"
+func (c *apiClient) accessRequestStatus(ctx context.Context, requestID int) (string, error) {
+	var request struct {
+		Status string
+	}
+	if err := c.do(ctx, "GET", fmt.Sprintf("secret-access-requests/%d", requestID), nil, &request); err != nil {
+		return "", err
+	}
+	return request.Status, nil
+}
+
+// writeWithApproval runs a secret write. When the write requires approval and wait
+// is set, it polls the approval request and repeats the write once it is approved.
+// Otherwise the approval is returned as an *approvalRequiredError.
+func writeWithApproval[T any](ctx context.Context, api *apiClient, wait *approvalWait, op func() (T, error)) (T, error) {
+	result, err := op()
+	approvalErr, ok := asApprovalRequired(err)
+	if !ok {
+		return result, err
+	}
+
+	var zero T
+	if wait == nil || approvalErr.RequestID == 0 || api == nil {
+		return zero, approvalErr
+	}
+
+	tflog.Info(ctx, "Waiting for approval request", map[string]interface{}{
+		"request_id": approvalErr.RequestID,
+		"timeout":    wait.Timeout.String(),
+	})
+
+	deadline := time.Now().Add(wait.Timeout)
+	for {
+		status, err := api.accessRequestStatus(ctx, approvalErr.RequestID)
+		if err != nil {
+			return zero, fmt.Errorf("failed to check approval request %d: %w", approvalErr.RequestID, err)
+		}
+
+		switch strings.ToLower(status) {
+		case "approved":
+			tflog.Info(ctx, "Approval request approved, repeating write", map[string]interface{}{
+				"request_id": approvalErr.RequestID,
+			})
+			return op()
+		case "pending", "":
+		default:
+			return zero, fmt.Errorf("approval request %d was not approved, its status is %s", approvalErr.RequestID, status)
+		}
+
+		if time.Now().Add(wait.PollInterval).After(deadline) {
+			return zero, approvalErr
+		}
+
+		tflog.Debug(ctx, "Approval request still pending", map[string]interface{}{
+			"request_id": approvalErr.RequestID,
+		})
+
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-time.After(wait.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAsApprovalRequired(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err       error
+		approval  bool
+		requestID int
+	}{
+		"sdk error": {
+			err:       errors.New(`400 Bad Request: {"message":"Changing this secret requires approval.","requestId":42}`),
+			approval:  true,
+			requestID: 42,
+		},
+		"api error": {
+			err:       fmt.Errorf("update: %w", &apiError{StatusCode: 403, Status: "403 Forbidden", Body: `{"requestId":7,"message":"Approval required"}`}),
+			approval:  true,
+			requestID: 7,
+		},
+		// Numbers in the message are not taken for the request ID
+		"no request id": {
+			err:      errors.New(`400 Bad Request: {"message":"Request 3 of 5 requires approval."}`),
+			approval: true,
+		},
+		"not json": {
+			err:      errors.New("403 Forbidden: approval required"),
+			approval: true,
+		},
+		"other error": {
+			err: errors.New(`400 Bad Request: {"message":"Invalid field","requestId":42}`),
+		},
+	} {
+		approvalErr, ok := asApprovalRequired(tc.err)
+		if ok != tc.approval {
+			t.Errorf("%s: approval required = %t, want %t", name, ok, tc.approval)
+			continue
+		}
+		if ok && approvalErr.RequestID != tc.requestID {
+			t.Errorf("%s: request ID = %d, want %d", name, approvalErr.RequestID, tc.requestID)
+		}
+	}
+}
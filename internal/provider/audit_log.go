@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditRedacted replaces redacted values in audit records
+const auditRedacted = "[redacted]"
+
+// auditSecretPath matches the paths of calls that act on a single secret
+var auditSecretPath = regexp.MustCompile(`^/?secrets/(\d+)`)
+
+// auditLog writes a JSON line for every Secret Server call to the file set with
+// audit_log_path. Request bodies are recorded with the values of redacted keys,
+// such as itemValue, replaced, and response bodies are never recorded.
+type auditLog struct {
+	mu         sync.Mutex
+	file       *os.File
+	redactKeys map[string]bool
+}
+
+// auditRecord is a line of the audit log
+type auditRecord struct {
+	Time       string      `json:"time"`
+	Server     string      `json:"server"`
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	SecretID   int         `json:"secret_id,omitempty"`
+	Status     int         `json:"status"`
+	DurationMS int64       `json:"duration_ms"`
+	Error      string      `json:"error,omitempty"`
+	Request    interface{} `json:"request,omitempty"`
+}
+
+// auditLogs holds the audit logs open in this process by path, so provider
+// configurations writing to the same file share it
+var auditLogs = struct {
+	sync.Mutex
+	logs map[string]*auditLog
+}{logs: map[string]*auditLog{}}
+
+// openAuditLog opens the audit log at path for appending, creating it readable
+// only by the current user
+func openAuditLog(path string, redactFields []string) (*auditLog, error) {
+	auditLogs.Lock()
+	defer auditLogs.Unlock()
+	if log, ok := auditLogs.logs[path]; ok {
+		return log, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	log := &auditLog{file: file, redactKeys: map[string]bool{}}
+	for _, key := range redactFields {
+		log.redactKeys[strings.ToLower(key)] = true
+	}
+	auditLogs.logs[path] = log
+	return log, nil
+}
+
+// record writes a record of a call that started at start. payload is the JSON
+// request body, if any. A nil log records nothing.
+func (l *auditLog) record(server, method, path string, payload []byte, start time.Time, err error) {
+	if l == nil {
+		return
+	}
+
+	rec := auditRecord{
+		Time:       start.UTC().Format(time.RFC3339Nano),
+		Server:     server,
+		Method:     method,
+		Path:       path,
+		Status:     auditStatus(err),
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if m := auditSecretPath.FindStringSubmatch(path); m != nil {
+		rec.SecretID, _ = strconv.Atoi(m[1])
+	}
+	if err != nil {
+		rec.Error = l.redactError(err)
+	}
+	if len(payload) > 0 {
+		var body interface{}
+		if json.Unmarshal(payload, &body) == nil {
+			rec.Request = l.redact(body)
+		}
+	}
+
+	line, marshalErr := json.Marshal(rec)
+	if marshalErr != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// An audit log that cannot be written must not fail the call it records
+	_, _ = l.file.Write(append(line, '\n'))
+}
+
+// redact returns body with the values of redacted keys replaced, at any depth
+func (l *auditLog) redact(body interface{}) interface{} {
+	switch v := body.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if l.redactKeys[strings.ToLower(key)] {
+				v[key] = auditRedacted
+				continue
+			}
+			v[key] = l.redact(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = l.redact(value)
+		}
+	}
+	return body
+}
+
+// redactError returns the message of err. API error bodies may echo the request,
+// so only their status is kept.
+func (l *auditLog) redactError(err error) string {
+	if apiErr, ok := err.(*apiError); ok {
+		return apiErr.Status
+	}
+	return err.Error()
+}
+
+// auditStatus returns the HTTP status of a call: 200 when it succeeded, the
+// status of the error when there is one, and 0 when no response was received
+func auditStatus(err error) int {
+	if err == nil {
+		return 200
+	}
+	return errorStatusCode(err)
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// AuthAliasModel is a set of credentials, besides the provider's own, that
+// resources and data sources select with their auth_alias attribute
+type AuthAliasModel struct {
+	Name         types.String `tfsdk:"name"`
+	Username     types.String `tfsdk:"username"`
+	Password     types.String `tfsdk:"password"`
+	Domain       types.String `tfsdk:"domain"`
+	Token        types.String `tfsdk:"token"`
+	ClientID     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+}
+
+// providerIdentity is the server client and api client of one set of credentials
+type providerIdentity struct {
+	server *server.Server
+	api    *apiClient
+}
+
+// providerAuthAliasBlock is the schema of the provider's auth_alias blocks
+func providerAuthAliasBlock() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		Description: "Alternate credentials for the same Secret Server, which resources and data sources use when their auth_alias names the block, " +
+			"e.g. to read with a low privilege account and write with a more privileged one. " +
+			"The connection, retry and rate limit settings of the provider apply to every alias.",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Required:    true,
+					Description: "The name auth_alias refers to the credentials by.",
+				},
+				"username": schema.StringAttribute{
+					Optional:    true,
+					Description: "The username of the Secret Server user.",
+				},
+				"password": schema.StringAttribute{
+					Optional:    true,
+					Sensitive:   true,
+					Description: "The password of the Secret Server user.",
+				},
+				"domain": schema.StringAttribute{
+					Optional:    true,
+					Description: "The domain of the Secret Server user.",
+				},
+				"token": schema.StringAttribute{
+					Optional:    true,
+					Sensitive:   true,
+					Description: "A pre-issued access token, used instead of a username and password.",
+				},
+				"client_id": schema.StringAttribute{
+					Optional:    true,
+					Description: "The client ID of an SDK client account, used with client_secret instead of a username and password.",
+				},
+				"client_secret": schema.StringAttribute{
+					Optional:    true,
+					Sensitive:   true,
+					Description: "The client secret of the SDK client account.",
+				},
+			},
+		},
+	}
+}
+
+// authAliasIdentities builds the identities of the provider's auth_alias
+// blocks. Each shares the connection settings, limits and call budget of api.
+func authAliasIdentities(aliases []AuthAliasModel, serverConfig server.Configuration, api *apiClient) (map[string]providerIdentity, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	identities := map[string]providerIdentity{}
+	for i, alias := range aliases {
+		name := alias.Name.ValueString()
+		if _, ok := identities[name]; ok {
+			diags.AddAttributeError(path.Root("auth_alias").AtListIndex(i).AtName("name"), "Duplicate Auth Alias",
+				fmt.Sprintf("More than one auth_alias block is named %q.", name))
+			continue
+		}
+		if alias.Username.ValueString() == "" && alias.Token.ValueString() == "" && alias.ClientID.ValueString() == "" {
+			diags.AddAttributeError(path.Root("auth_alias").AtListIndex(i), "Missing Auth Alias Credentials",
+				fmt.Sprintf("auth_alias %q needs a username and password, a token, or a client_id and client_secret.", name))
+			continue
+		}
+
+		config := serverConfig
+		config.Credentials = server.UserCredential{
+			Username: alias.Username.ValueString(),
+			Password: alias.Password.ValueString(),
+			Domain:   alias.Domain.ValueString(),
+			Token:    alias.Token.ValueString(),
+		}
+		s, err := server.New(config)
+		if err != nil {
+			diags.AddAttributeError(path.Root("auth_alias").AtListIndex(i), "Unable to create TSS API Client",
+				fmt.Sprintf("Failed to create the client of auth_alias %q: %s", name, err))
+			continue
+		}
+
+		aliasAPI := api.withServer(s)
+		if alias.ClientID.ValueString() != "" && alias.Token.ValueString() == "" {
+			aliasAPI.useClientCredentials(alias.ClientID.ValueString(), alias.ClientSecret.ValueString())
+		}
+		aliasAPI.shareSession()
+		identities[name] = providerIdentity{server: s, api: aliasAPI}
+	}
+	return identities, diags
+}
+
+// withServer returns a client authenticating with the credentials of s, with
+// the connection settings, limits and call budget of c
+func (c *apiClient) withServer(s *server.Server) *apiClient {
+	copied := newAPIClient(s)
+	if c.transport != nil {
+		copied.useTransport(c.transport)
+	}
+	copied.defaults = c.defaults
+	copied.limiter = c.limiter
+	copied.health = c.health
+	copied.pageSize = c.pageSize
+	copied.offline = c.offline
+	copied.audit = c.audit
+	copied.budget = c.budget
+	copied.tracer = c.tracer
+	copied.platform = c.platform
+	return copied
+}
+
+// selectIdentity returns the server client and api client of the auth_alias
+// named by alias, or s and api when alias is not set
+func selectIdentity(identities map[string]providerIdentity, alias types.String, s *server.Server, api *apiClient) (*server.Server, *apiClient, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if alias.IsNull() || alias.IsUnknown() || alias.ValueString() == "" || api == nil || api.offline {
+		return s, api, diags
+	}
+
+	identity, ok := identities[alias.ValueString()]
+	if !ok {
+		diags.AddAttributeError(path.Root("auth_alias"), "Unknown Auth Alias",
+			fmt.Sprintf("The provider has no auth_alias block named %q.", alias.ValueString()))
+		return s, api, diags
+	}
+	return identity.server, identity.api, diags
+}
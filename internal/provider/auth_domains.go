@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// parseDomains splits the comma separated TSS_DOMAINS value
+func parseDomains(value string) []string {
+	var domains []string
+	for _, domain := range strings.Split(value, ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// authDomains returns the domains to try, domain first, without duplicates
+func authDomains(domain string, domains []string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, d := range append([]string{domain}, domains...) {
+		key := strings.ToLower(d)
+		if d == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, d)
+	}
+	return result
+}
+
+// domainOrder returns the domains to try, starting with the one that last
+// accepted the credentials so that a renewal does not fail over again
+func (c *apiClient) domainOrder() []string {
+	last := c.domain.Load()
+	if last == nil {
+		return c.domains
+	}
+	order := []string{*last}
+	for _, d := range c.domains {
+		if d != *last {
+			order = append(order, d)
+		}
+	}
+	return order
+}
+
+// passwordGrant requests a token with the password grant. With domains set,
+// they are tried in order until one accepts the credentials; any other error
+// ends the attempt.
+func (c *apiClient) passwordGrant(ctx context.Context, tokenURL string, values url.Values) (string, time.Duration, error) {
+	if len(c.domains) == 0 {
+		return c.grant(ctx, tokenURL, values)
+	}
+
+	var err error
+	for _, domain := range c.domainOrder() {
+		values.Set("domain", domain)
+		var accessToken string
+		var lifetime time.Duration
+		accessToken, lifetime, err = c.grant(ctx, tokenURL, values)
+		if err == nil {
+			c.domain.Store(&domain)
+			tflog.Debug(ctx, "Authenticated with domain", map[string]interface{}{
+				"domain": domain,
+			})
+			return accessToken, lifetime, nil
+		}
+		if !isCredentialError(err) {
+			return "", 0, err
+		}
+		tflog.Debug(ctx, "Domain rejected the credentials, trying the next", map[string]interface{}{
+			"domain": domain,
+			"error":  err.Error(),
+		})
+	}
+	return "", 0, err
+}
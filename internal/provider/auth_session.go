@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// authSession is the access token cache of an apiClient
+type authSession struct {
+	mu          sync.Mutex
+	accessToken string
+	// refreshAt is when a new token is requested in the background, while the
+	// cached one is still used until expiresAt
+	refreshAt  time.Time
+	expiresAt  time.Time
+	refreshing bool
+	vaultURL   string
+	// domain is the domain of apiClient.domains that last accepted the credentials
+	domain atomic.Pointer[string]
+}
+
+// authSessions holds the sessions shared by the provider configurations in this
+// process, such as aliases, keyed by endpoint and credentials
+var authSessions = struct {
+	sync.Mutex
+	sessions map[string]*authSession
+}{sessions: map[string]*authSession{}}
+
+// sessionKey identifies the endpoint and credentials the client authenticates
+// with. It is a hash, so the credentials are not kept in the key.
+func (c *apiClient) sessionKey() string {
+	h := sha256.New()
+	for _, part := range []string{
+		c.baseURL(),
+		c.config.Credentials.Username,
+		c.config.Credentials.Password,
+		c.config.Credentials.Domain,
+		c.config.Credentials.Token,
+		strings.Join(c.domains, ","),
+		c.clientID,
+		c.clientSecret,
+		strconv.FormatBool(c.platform),
+		strconv.FormatBool(c.oidc != nil),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	if c.oidc != nil {
+		h.Write([]byte(c.oidc.tokenFile + "\x00" + c.oidc.audience))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// shareSession makes the client use the session of any other client in the
+// process with the same endpoint and credentials, so they share one token. It
+// must be called once authentication is fully configured, before any request.
+func (c *apiClient) shareSession() {
+	key := c.sessionKey()
+
+	authSessions.Lock()
+	defer authSessions.Unlock()
+	if session, ok := authSessions.sessions[key]; ok {
+		c.authSession = session
+		return
+	}
+	authSessions.sessions[key] = c.authSession
+}
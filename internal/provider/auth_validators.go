@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ provider.ProviderWithConfigValidators = &TssProvider{}
+
+// ConfigValidators rejects ambiguous authentication settings when the configuration
+// is validated, rather than letting one method silently win and fail on apply
+func (p *TssProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		exclusiveAuthValidator{},
+		authDependencyValidator{},
+	}
+}
+
+// isConfigured reports whether a string attribute is set to a non-empty known value
+func isConfigured(value types.String) bool {
+	return !value.IsNull() && !value.IsUnknown() && value.ValueString() != ""
+}
+
+// exclusiveAuthValidator allows at most one authentication method in the
+// provider configuration and in each auth_alias block, and requires a password
+// with a username
+type exclusiveAuthValidator struct{}
+
+func (v exclusiveAuthValidator) Description(ctx context.Context) string {
+	return "Only one of username and password, token, client_id and use_oidc or oidc_token_file may be configured, in the provider and in each auth_alias block."
+}
+
+func (v exclusiveAuthValidator) MarkdownDescription(ctx context.Context) string {
+	return "Only one of `username` and `password`, `token`, `client_id` and `use_oidc` or `oidc_token_file` may be configured, in the provider and in each `auth_alias` block."
+}
+
+func (v exclusiveAuthValidator) ValidateProvider(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var data TssProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The environment may still supply the provider's password, but not an alias's
+	credentials := authCredentials{
+		username: data.Username,
+		password: data.Password,
+		token:    data.Token,
+		clientID: data.ClientID,
+		oidc:     (!data.UseOIDC.IsUnknown() && data.UseOIDC.ValueBool()) || isConfigured(data.OIDCTokenFile),
+	}
+	v.validate(credentials, path.Empty(), getenv("TSS_PASSWORD", "SS_PASSWORD") != "", resp)
+	for i, alias := range data.AuthAliases {
+		credentials := authCredentials{
+			username: alias.Username,
+			password: alias.Password,
+			token:    alias.Token,
+			clientID: alias.ClientID,
+		}
+		v.validate(credentials, path.Root("auth_alias").AtListIndex(i), false, resp)
+	}
+}
+
+// validate checks the credentials configured at base. envPassword reports
+// whether the environment supplies a password for a username.
+func (v exclusiveAuthValidator) validate(credentials authCredentials, base path.Path, envPassword bool, resp *provider.ValidateConfigResponse) {
+	methods := credentials.methods()
+	if len(methods) > 1 {
+		resp.Diagnostics.AddAttributeError(
+			base.AtName(methods[1]),
+			"Conflicting Authentication Methods",
+			fmt.Sprintf("The configuration sets %s, but only one authentication method may be configured. "+
+				"Remove all but one: username and password authenticate with the password grant, token uses a pre-issued access token, "+
+				"client_id authenticates as an SDK client account, and use_oidc exchanges the CI job's ID token.",
+				strings.Join(methods, " and ")),
+		)
+		return
+	}
+
+	if isConfigured(credentials.username) && credentials.password.IsNull() && !envPassword {
+		resp.Diagnostics.AddAttributeError(
+			base.AtName("password"),
+			"Missing Password Configuration",
+			"username is set without password. Set the user's password, or remove username to authenticate another way.",
+		)
+	}
+}
+
+// authCredentials are the credentials configured in the provider or in an auth_alias block
+type authCredentials struct {
+	username types.String
+	password types.String
+	token    types.String
+	clientID types.String
+	oidc     bool
+}
+
+// methods returns the authentication methods the credentials configure, each
+// by the attribute that configures it
+func (c authCredentials) methods() []string {
+	var methods []string
+	switch {
+	case isConfigured(c.password):
+		methods = append(methods, "password")
+	case isConfigured(c.username):
+		methods = append(methods, "username")
+	}
+	if isConfigured(c.token) {
+		methods = append(methods, "token")
+	}
+	if isConfigured(c.clientID) {
+		methods = append(methods, "client_id")
+	}
+	if c.oidc {
+		methods = append(methods, "use_oidc")
+	}
+	return methods
+}
+
+// authDependencyValidator rejects authentication settings that have no effect
+// without the method they belong to
+type authDependencyValidator struct{}
+
+func (v authDependencyValidator) Description(ctx context.Context) string {
+	return "Authentication settings may only be configured with the method they belong to."
+}
+
+func (v authDependencyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v authDependencyValidator) ValidateProvider(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var data TssProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The environment may still supply client_id, so only settings that
+	// contradict the configuration are rejected
+	if isConfigured(data.Token) {
+		settings := []struct {
+			attr  string
+			value types.String
+		}{
+			{"client_secret", data.ClientSecret},
+			{"onboarding_rule", data.OnboardingRule},
+			{"oidc_audience", data.OIDCAudience},
+		}
+		for _, setting := range settings {
+			if isConfigured(setting.value) {
+				resp.Diagnostics.AddAttributeError(path.Root(setting.attr), "Conflicting Authentication Settings",
+					fmt.Sprintf("%s has no effect when token is set, since the provider authenticates with the access token. Remove one of them.", setting.attr))
+			}
+		}
+	}
+
+	if isConfigured(data.ClientSecret) && isConfigured(data.OnboardingRule) {
+		resp.Diagnostics.AddAttributeError(path.Root("onboarding_rule"), "Conflicting Authentication Settings",
+			"onboarding_rule registers client_id to obtain a client secret, but client_secret is already set. "+
+				"Remove onboarding_rule to authenticate with the existing secret, or client_secret to register the client.")
+	}
+	if isConfigured(data.OIDCAudience) && isConfigured(data.OIDCTokenFile) {
+		resp.Diagnostics.AddAttributeError(path.Root("oidc_audience"), "Conflicting Authentication Settings",
+			"oidc_audience only applies to ID tokens requested from GitHub Actions, but oidc_token_file supplies the token. "+
+				"Set the audience where the token file is issued, and remove oidc_audience.")
+	}
+}
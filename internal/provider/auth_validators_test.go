@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// nullObject returns a value of objectType with the given attributes set and the others null
+func nullObject(objectType tftypes.Object, values map[string]string) tftypes.Value {
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = tftypes.NewValue(tftypes.String, value)
+		}
+	}
+	return tftypes.NewValue(objectType, attributes)
+}
+
+func TestAuthValidators(t *testing.T) {
+	t.Setenv("TSS_PASSWORD", "")
+	t.Setenv("SS_PASSWORD", "")
+
+	ctx := context.Background()
+	var schemaResp provider.SchemaResponse
+	(&TssProvider{}).Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	aliasesType := objectType.AttributeTypes["auth_alias"].(tftypes.List)
+	aliasType := aliasesType.ElementType.(tftypes.Object)
+
+	for name, tc := range map[string]struct {
+		values map[string]string
+		alias  map[string]string
+		errors int
+	}{
+		"username and password":      {map[string]string{"username": "svc", "password": "pw"}, nil, 0},
+		"username without password":  {map[string]string{"username": "svc"}, nil, 1},
+		"username with token":        {map[string]string{"username": "svc", "token": "t"}, nil, 1},
+		"username with client_id":    {map[string]string{"username": "svc", "client_id": "c", "client_secret": "s"}, nil, 1},
+		"alias token":                {map[string]string{"token": "t"}, map[string]string{"name": "reader", "token": "t"}, 0},
+		"alias password and token":   {map[string]string{"token": "t"}, map[string]string{"name": "reader", "username": "svc", "password": "pw", "token": "t"}, 1},
+		"alias username and client":  {map[string]string{"token": "t"}, map[string]string{"name": "reader", "username": "svc", "client_id": "c"}, 1},
+		"alias username without pwd": {map[string]string{"token": "t"}, map[string]string{"name": "reader", "username": "svc"}, 1},
+	} {
+		raw := nullObject(objectType, tc.values)
+		if tc.alias != nil {
+			var attributes map[string]tftypes.Value
+			if err := raw.As(&attributes); err != nil {
+				t.Fatal(err)
+			}
+			attributes["auth_alias"] = tftypes.NewValue(aliasesType, []tftypes.Value{nullObject(aliasType, tc.alias)})
+			raw = tftypes.NewValue(objectType, attributes)
+		}
+
+		config := tfsdk.Config{Schema: schemaResp.Schema, Raw: raw}
+		var errors int
+		for _, validator := range (&TssProvider{}).ConfigValidators(ctx) {
+			var resp provider.ValidateConfigResponse
+			validator.ValidateProvider(ctx, provider.ValidateConfigRequest{Config: config}, &resp)
+			errors += resp.Diagnostics.ErrorsCount()
+		}
+		if errors != tc.errors {
+			t.Errorf("%s: %d errors, want %d", name, errors, tc.errors)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// callBudget caps the number of Secret Server calls the provider makes in a
+// run, so a runaway configuration fails instead of flooding a shared server.
+// A nil callBudget does not limit.
+type callBudget struct {
+	max  int64
+	used atomic.Int64
+}
+
+// newCallBudget returns a budget of max calls, or nil when max is not positive
+func newCallBudget(max int64) *callBudget {
+	if max <= 0 {
+		return nil
+	}
+	return &callBudget{max: max}
+}
+
+// spend takes a call from the budget, and returns an error once it is used up.
+// Every attempt of a retried call is spent.
+func (b *callBudget) spend() error {
+	if b == nil {
+		return nil
+	}
+	if used := b.used.Add(1); used > b.max {
+		return fmt.Errorf("this run has made the %d Secret Server calls allowed by max_api_calls; "+
+			"raise the limit or narrow the configuration, e.g. a for_each over more secrets than intended", b.max)
+	}
+	return nil
+}
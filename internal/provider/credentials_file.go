@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultProfile is the profile used when neither profile nor TSS_PROFILE is set
+const defaultProfile = "default"
+
+// credentialsProfileKeys are the settings a profile of the credentials file may hold
+var credentialsProfileKeys = map[string]bool{
+	"server_url":    true,
+	"username":      true,
+	"password":      true,
+	"domain":        true,
+	"token":         true,
+	"client_id":     true,
+	"client_secret": true,
+}
+
+// credentialsProfile holds the settings of a named profile of the shared credentials file
+type credentialsProfile map[string]string
+
+// fill sets *value to the profile's setting for key, unless it is already set
+func (p credentialsProfile) fill(key string, value *string) {
+	if *value == "" {
+		*value = p[key]
+	}
+}
+
+// defaultCredentialsFile returns ~/.tss/credentials, or "" when there is no home directory
+func defaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".tss", "credentials")
+}
+
+// loadCredentialsProfile returns the named profile of the credentials file. When
+// neither the file nor the profile was chosen explicitly, a missing default file
+// or profile is not an error and nil is returned.
+func loadCredentialsProfile(file, profile string) (credentialsProfile, error) {
+	explicit := file != "" || profile != ""
+	if file == "" {
+		file = defaultCredentialsFile()
+	}
+	if profile == "" {
+		profile = defaultProfile
+	}
+	if file == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	profiles, err := parseCredentialsFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	p, ok := profiles[profile]
+	if !ok && explicit {
+		return nil, fmt.Errorf("%s has no profile %q", file, profile)
+	}
+	return p, nil
+}
+
+// parseCredentialsFile parses an INI credentials file of [profile] sections holding
+// key = value settings. Lines starting with # or ; are comments.
+func parseCredentialsFile(r io.Reader) (map[string]credentialsProfile, error) {
+	profiles := map[string]credentialsProfile{}
+	var current credentialsProfile
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty profile name", lineNumber)
+			}
+			if profiles[name] == nil {
+				profiles[name] = credentialsProfile{}
+			}
+			current = profiles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: setting outside of a [profile] section", lineNumber)
+		}
+		key = strings.TrimSpace(key)
+		if !credentialsProfileKeys[key] {
+			return nil, fmt.Errorf("line %d: unknown setting %q", lineNumber, key)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		current[key] = value
+	}
+	return profiles, scanner.Err()
+}
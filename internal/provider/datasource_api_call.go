@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// NewTssAPICallDataSource is a helper function to simplify the provider implementation.
+func NewTssAPICallDataSource() datasource.DataSource {
+	return &TssAPICallDataSource{}
+}
+
+// TssAPICallDataSource calls any Secret Server REST API endpoint, as an escape
+// hatch for features no typed data source covers yet
+type TssAPICallDataSource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// APICallModel defines the state structure for the API call data source
+type APICallModel struct {
+	Method       types.String `tfsdk:"method"`
+	Path         types.String `tfsdk:"path"`
+	Body         types.String `tfsdk:"body"`
+	ResponsePath types.String `tfsdk:"response_path"`
+	Response     types.String `tfsdk:"response"`
+	Result       types.String `tfsdk:"result"`
+}
+
+// Metadata provides the data source type name
+func (d *TssAPICallDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "dept-tss_api_call"
+}
+
+// Schema defines the schema for the data source
+func (d *TssAPICallDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Calls a Secret Server REST API endpoint on every refresh, as an escape hatch for features the provider does not cover yet. " +
+			"Prefer the typed data sources where they exist; this one may be removed once the feature it is used for is supported.",
+		Attributes: map[string]schema.Attribute{
+			"method": schema.StringAttribute{
+				Optional:    true,
+				Description: "The HTTP method. Defaults to GET. Other methods run on every refresh, so only use them for endpoints that change nothing.",
+			},
+			"path": schema.StringAttribute{
+				Required:    true,
+				Description: "The path of the endpoint relative to /api/v1, including any query string, e.g. \"folders?filter.searchText=IT\"",
+			},
+			"body": schema.StringAttribute{
+				Optional:    true,
+				Description: "The JSON request body, e.g. from jsonencode()",
+			},
+			"response_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "A jq style path selecting the result from the response, e.g. \".records[0].id\". Defaults to the whole response",
+			},
+			"response": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The JSON response body",
+			},
+			"result": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The value selected by response_path: strings as they are, other values as JSON. Null when the path selects nothing",
+			},
+		},
+	}
+}
+
+// Configure initializes the data source with the provider configuration
+func (d *TssAPICallDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	d.api = providerData.API
+	d.logging = providerData.Logging
+}
+
+// Read calls the endpoint
+func (d *TssAPICallDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.logging.context(ctx)
+
+	var state APICallModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	method := apiCallMethod(state.Method, http.MethodGet)
+	response, err := d.api.call(ctx, method, state.Path.ValueString(), state.Body)
+	if err != nil {
+		resp.Diagnostics.AddError("API Call Error", fmt.Sprintf("%s %s failed: %s", method, state.Path.ValueString(), err))
+		return
+	}
+	result, err := extractResponse(response, state.ResponsePath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("response_path"), "Invalid Response Path", err.Error())
+		return
+	}
+
+	state.Response = types.StringValue(string(response))
+	state.Result = result
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// apiCallMethod returns the upper-cased method, or fallback when it is not set
+func apiCallMethod(method types.String, fallback string) string {
+	if method.IsNull() || method.ValueString() == "" {
+		return fallback
+	}
+	return strings.ToUpper(method.ValueString())
+}
+
+// call sends a request with a JSON body given as text and returns the raw
+// response body
+func (c *apiClient) call(ctx context.Context, method, path string, body types.String) (json.RawMessage, error) {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil, fmt.Errorf("unsupported method %q", method)
+	}
+
+	var input interface{}
+	if !body.IsNull() && body.ValueString() != "" {
+		if !json.Valid([]byte(body.ValueString())) {
+			return nil, fmt.Errorf("body is not valid JSON")
+		}
+		input = json.RawMessage(body.ValueString())
+	}
+
+	tflog.Debug(ctx, "Calling Secret Server API endpoint", map[string]interface{}{
+		"method": method,
+		"path":   path,
+	})
+	var response json.RawMessage
+	if err := c.do(ctx, method, path, input, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// extractResponse returns the value of response selected by a jq style path of
+// object keys and array indexes, e.g. .records[0].name or .["key with spaces"].
+// Strings are returned as they are and other values as JSON.
+func extractResponse(response json.RawMessage, selector string) (types.String, error) {
+	if len(response) == 0 {
+		return types.StringNull(), nil
+	}
+	// Numbers are kept as they are, rather than as float64
+	decoder := json.NewDecoder(bytes.NewReader(response))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return types.StringNull(), fmt.Errorf("the response is not JSON: %w", err)
+	}
+
+	steps, err := parseResponsePath(selector)
+	if err != nil {
+		return types.StringNull(), err
+	}
+	for _, step := range steps {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if step.key == nil {
+				return types.StringNull(), fmt.Errorf("%q indexes an object with a number", selector)
+			}
+			value = v[*step.key]
+		case []interface{}:
+			if step.key != nil {
+				return types.StringNull(), fmt.Errorf("%q selects key %q of an array", selector, *step.key)
+			}
+			index := step.index
+			if index < 0 {
+				index += len(v)
+			}
+			if index < 0 || index >= len(v) {
+				return types.StringNull(), nil
+			}
+			value = v[index]
+		default:
+			return types.StringNull(), nil
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return types.StringNull(), nil
+	case string:
+		return types.StringValue(v), nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return types.StringNull(), err
+	}
+	return types.StringValue(string(encoded)), nil
+}
+
+// responsePathStep is an object key, or an array index when key is nil
+type responsePathStep struct {
+	key   *string
+	index int
+}
+
+// parseResponsePath splits a jq style path into its steps. "" and "." select
+// the whole response.
+func parseResponsePath(selector string) ([]responsePathStep, error) {
+	var steps []responsePathStep
+	rest := strings.TrimSpace(selector)
+	if rest == "" || rest == "." {
+		return steps, nil
+	}
+	if !strings.HasPrefix(rest, ".") && !strings.HasPrefix(rest, "[") {
+		return nil, fmt.Errorf("%q must start with \".\"", selector)
+	}
+
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, `.["`) || strings.HasPrefix(rest, `["`):
+			rest = strings.TrimPrefix(strings.TrimPrefix(rest, "."), `["`)
+			end := strings.Index(rest, `"]`)
+			if end < 0 {
+				return nil, fmt.Errorf("%q has an unterminated [\"key\"]", selector)
+			}
+			key := rest[:end]
+			steps = append(steps, responsePathStep{key: &key})
+			rest = rest[end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("%q has an unterminated [index]", selector)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("%q has an invalid array index %q", selector, rest[1:end])
+			}
+			steps = append(steps, responsePathStep{index: index})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("%q has an empty key", selector)
+			}
+			key := rest[:end]
+			steps = append(steps, responsePathStep{key: &key})
+			rest = rest[end:]
+		default:
+			return nil, fmt.Errorf("%q is not a path of .key and [index] steps", selector)
+		}
+	}
+	return steps, nil
+}
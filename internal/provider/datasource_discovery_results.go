@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// NewTssDiscoveryResultsDataSource is a helper function to simplify the provider implementation.
+func NewTssDiscoveryResultsDataSource() datasource.DataSource {
+	return &TssDiscoveryResultsDataSource{}
+}
+
+// TssDiscoveryResultsDataSource lists the accounts found by account discovery
+type TssDiscoveryResultsDataSource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// DiscoveredAccountModel is a single account returned by the discovery results data source
+type DiscoveredAccountModel struct {
+	Machine     types.String `tfsdk:"machine"`
+	AccountName types.String `tfsdk:"account_name"`
+	SourceID    types.Int64  `tfsdk:"source_id"`
+	RuleName    types.String `tfsdk:"rule_name"`
+	SecretID    types.Int64  `tfsdk:"secret_id"`
+}
+
+// discoveredAccount is an account as returned by the discovery results endpoint
+type discoveredAccount struct {
+	ComputerName      string
+	AccountName       string
+	DiscoverySourceID int
+	RuleName          string
+	SecretID          int
+}
+
+// Metadata provides the data source type name
+func (d *TssDiscoveryResultsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "dept-tss_discovery_results"
+}
+
+// Schema defines the schema for the data source
+func (d *TssDiscoveryResultsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the accounts found by account discovery, by default only those not yet managed by a secret.",
+		Attributes: map[string]schema.Attribute{
+			"source_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Only list accounts found by this discovery source",
+			},
+			"include_managed": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether accounts that are already managed by a secret are listed too. Defaults to false",
+			},
+			"accounts": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The discovered accounts, sorted by machine and account name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"machine": schema.StringAttribute{
+							Computed:    true,
+							Description: "The machine the account was found on",
+						},
+						"account_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The name of the account",
+						},
+						"source_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The ID of the discovery source that found the account",
+						},
+						"rule_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The name of the discovery rule that found the account",
+						},
+						"secret_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The ID of the secret managing the account. Null for unmanaged accounts",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure initializes the data source with the provider configuration
+func (d *TssDiscoveryResultsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	d.api = providerData.API
+	d.logging = providerData.Logging
+}
+
+// Read lists the discovered accounts
+func (d *TssDiscoveryResultsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.logging.context(ctx)
+	var state struct {
+		SourceID       types.Int64              `tfsdk:"source_id"`
+		IncludeManaged types.Bool               `tfsdk:"include_managed"`
+		Accounts       []DiscoveredAccountModel `tfsdk:"accounts"`
+	}
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accounts, err := d.api.discoveredAccounts(ctx, int(state.SourceID.ValueInt64()), state.IncludeManaged.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("Discovery Results Error", fmt.Sprintf("Failed to list discovered accounts: %s", err))
+		return
+	}
+
+	tflog.Debug(ctx, "Listed discovered accounts", map[string]interface{}{
+		"count": len(accounts),
+	})
+
+	// Sort so the list is stable between refreshes
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].ComputerName != accounts[j].ComputerName {
+			return accounts[i].ComputerName < accounts[j].ComputerName
+		}
+		return accounts[i].AccountName < accounts[j].AccountName
+	})
+
+	state.Accounts = make([]DiscoveredAccountModel, 0, len(accounts))
+	for _, account := range accounts {
+		secretID := types.Int64Null()
+		if account.SecretID != 0 {
+			secretID = types.Int64Value(int64(account.SecretID))
+		}
+		state.Accounts = append(state.Accounts, DiscoveredAccountModel{
+			Machine:     types.StringValue(account.ComputerName),
+			AccountName: types.StringValue(account.AccountName),
+			SourceID:    types.Int64Value(int64(account.DiscoverySourceID)),
+			RuleName:    types.StringValue(account.RuleName),
+			SecretID:    secretID,
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// discoveredAccounts returns the accounts found by discovery, following pagination
+func (c *apiClient) discoveredAccounts(ctx context.Context, sourceID int, includeManaged bool) ([]discoveredAccount, error) {
+	pageSize := c.take()
+
+	query := url.Values{}
+	if sourceID != 0 {
+		query.Set("filter.discoverySourceId", strconv.Itoa(sourceID))
+	}
+	query.Set("filter.includeManaged", strconv.FormatBool(includeManaged))
+	query.Set("take", strconv.Itoa(pageSize))
+
+	var accounts []discoveredAccount
+	for skip := 0; ; skip += pageSize {
+		query.Set("skip", strconv.Itoa(skip))
+
+		var page struct {
+			Records []discoveredAccount
+			HasNext bool
+		}
+		if err := c.do(ctx, "GET", "discovery/accounts?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, page.Records...)
+
+		if !page.HasNext || len(page.Records) == 0 {
+			return accounts, nil
+		}
+	}
+}
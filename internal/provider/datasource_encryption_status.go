@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// NewTssEncryptionStatusDataSource is a helper function to simplify the provider implementation.
+func NewTssEncryptionStatusDataSource() datasource.DataSource {
+	return &TssEncryptionStatusDataSource{}
+}
+
+// TssEncryptionStatusDataSource reports how Secret Server encrypts secrets at rest
+type TssEncryptionStatusDataSource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// EncryptionStatusModel defines the state structure for the encryption status data source
+type EncryptionStatusModel struct {
+	HSMEnabled    types.Bool   `tfsdk:"hsm_enabled"`
+	HSMType       types.String `tfsdk:"hsm_type"`
+	MasterKeyType types.String `tfsdk:"master_key_type"`
+	FIPSEnabled   types.Bool   `tfsdk:"fips_enabled"`
+}
+
+// encryptionStatus is the encryption configuration as returned by Secret Server
+type encryptionStatus struct {
+	HSMEnabled              bool
+	HSMType                 string
+	MasterEncryptionKeyType string
+	FIPSEnabled             bool
+}
+
+// Metadata provides the data source type name
+func (d *TssEncryptionStatusDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "dept-tss_encryption_status"
+}
+
+// Schema defines the schema for the data source
+func (d *TssEncryptionStatusDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports how Secret Server encrypts secrets at rest, e.g. to assert in a precondition that an HSM protects the master key. " +
+			"Reading it requires the Administer Configuration permission.",
+		Attributes: map[string]schema.Attribute{
+			"hsm_enabled": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the master encryption key is protected by a hardware security module",
+			},
+			"hsm_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "The kind of hardware security module, e.g. Luna or AzureKeyVault. Empty when no HSM is used",
+			},
+			"master_key_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "How the master encryption key is stored, e.g. File, DPAPI or HSM",
+			},
+			"fips_enabled": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether Secret Server only uses FIPS 140-2 validated cryptography",
+			},
+		},
+	}
+}
+
+// Configure initializes the data source with the provider configuration
+func (d *TssEncryptionStatusDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	d.api = providerData.API
+	d.logging = providerData.Logging
+}
+
+// Read fetches the encryption configuration
+func (d *TssEncryptionStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.logging.context(ctx)
+
+	status, err := d.api.encryptionStatus(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Encryption Status Error", fmt.Sprintf("Failed to read the encryption configuration: %s", err))
+		return
+	}
+
+	tflog.Debug(ctx, "Read encryption configuration", map[string]interface{}{
+		"hsm_enabled":     status.HSMEnabled,
+		"master_key_type": status.MasterEncryptionKeyType,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, EncryptionStatusModel{
+		HSMEnabled:    types.BoolValue(status.HSMEnabled),
+		HSMType:       types.StringValue(status.HSMType),
+		MasterKeyType: types.StringValue(status.MasterEncryptionKeyType),
+		FIPSEnabled:   types.BoolValue(status.FIPSEnabled),
+	})...)
+}
+
+// encryptionStatus returns the encryption at rest configuration
+func (c *apiClient) encryptionStatus(ctx context.Context) (*encryptionStatus, error) {
+	var status encryptionStatus
+	if err := c.do(ctx, "GET", "configuration/encryption", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
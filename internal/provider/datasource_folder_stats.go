@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// NewTssFolderStatsDataSource is a helper function to simplify the provider implementation.
+func NewTssFolderStatsDataSource() datasource.DataSource {
+	return &TssFolderStatsDataSource{}
+}
+
+// TssFolderStatsDataSource reports how many secrets and subfolders a folder
+// holds and how deep it is nested
+type TssFolderStatsDataSource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// FolderStatsModel defines the state structure for the folder stats data source
+type FolderStatsModel struct {
+	FolderID         types.Int64  `tfsdk:"folder_id"`
+	MaxSecrets       types.Int64  `tfsdk:"max_secrets"`
+	MaxSubfolders    types.Int64  `tfsdk:"max_subfolders"`
+	MaxDepth         types.Int64  `tfsdk:"max_depth"`
+	FolderPath       types.String `tfsdk:"folder_path"`
+	Depth            types.Int64  `tfsdk:"depth"`
+	SecretCount      types.Int64  `tfsdk:"secret_count"`
+	TotalSecretCount types.Int64  `tfsdk:"total_secret_count"`
+	SubfolderCount   types.Int64  `tfsdk:"subfolder_count"`
+	ExceedsLimits    types.Bool   `tfsdk:"exceeds_limits"`
+}
+
+// folderStats are the counts of a folder as returned by Secret Server
+type folderStats struct {
+	FolderPath       string
+	SecretCount      int
+	TotalSecretCount int
+	SubfolderCount   int
+}
+
+// Metadata provides the data source type name
+func (d *TssFolderStatsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "dept-tss_folder_stats"
+}
+
+// Schema defines the schema for the data source
+func (d *TssFolderStatsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports how many secrets and subfolders a folder holds and how deeply it is nested, " +
+			"e.g. so modules that shard secrets across folders can add a folder when one grows past a threshold.",
+		Attributes: map[string]schema.Attribute{
+			"folder_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the folder",
+			},
+			"max_secrets": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The number of secrets directly in the folder above which exceeds_limits is true",
+			},
+			"max_subfolders": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The number of direct subfolders above which exceeds_limits is true",
+			},
+			"max_depth": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The depth above which exceeds_limits is true",
+			},
+			"folder_path": schema.StringAttribute{
+				Computed:    true,
+				Description: "The full path of the folder, e.g. \\IT\\Prod\\DBs",
+			},
+			"depth": schema.Int64Attribute{
+				Computed:    true,
+				Description: "How deeply the folder is nested; folders at the root have depth 1",
+			},
+			"secret_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of secrets directly in the folder",
+			},
+			"total_secret_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of secrets in the folder and all of its subfolders",
+			},
+			"subfolder_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of direct subfolders of the folder",
+			},
+			"exceeds_limits": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether any of max_secrets, max_subfolders and max_depth is exceeded. False when none is set",
+			},
+		},
+	}
+}
+
+// Configure initializes the data source with the provider configuration
+func (d *TssFolderStatsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	d.api = providerData.API
+	d.logging = providerData.Logging
+}
+
+// Read fetches the counts of the folder
+func (d *TssFolderStatsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.logging.context(ctx)
+
+	var state FolderStatsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folderID := int(state.FolderID.ValueInt64())
+	stats, err := d.api.folderStats(ctx, folderID)
+	if err != nil {
+		resp.Diagnostics.AddError("Folder Stats Error", fmt.Sprintf("Failed to read the counts of folder %d: %s", folderID, err))
+		return
+	}
+	depth := folderDepth(stats.FolderPath)
+
+	tflog.Debug(ctx, "Read folder stats", map[string]interface{}{
+		"folder_id":       folderID,
+		"depth":           depth,
+		"secret_count":    stats.SecretCount,
+		"subfolder_count": stats.SubfolderCount,
+	})
+
+	state.FolderPath = types.StringValue(stats.FolderPath)
+	state.Depth = types.Int64Value(int64(depth))
+	state.SecretCount = types.Int64Value(int64(stats.SecretCount))
+	state.TotalSecretCount = types.Int64Value(int64(stats.TotalSecretCount))
+	state.SubfolderCount = types.Int64Value(int64(stats.SubfolderCount))
+	state.ExceedsLimits = types.BoolValue(exceedsLimit(state.MaxSecrets, stats.SecretCount) ||
+		exceedsLimit(state.MaxSubfolders, stats.SubfolderCount) ||
+		exceedsLimit(state.MaxDepth, depth))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// exceedsLimit reports whether count is above limit, when limit is set
+func exceedsLimit(limit types.Int64, count int) bool {
+	return !limit.IsNull() && !limit.IsUnknown() && int64(count) > limit.ValueInt64()
+}
+
+// folderDepth returns the number of folders in path, e.g. 3 for \IT\Prod\DBs
+func folderDepth(path string) int {
+	depth := 0
+	for _, name := range strings.Split(path, "\\") {
+		if name != "" {
+			depth++
+		}
+	}
+	return depth
+}
+
+// folderStats returns the path of a folder and the number of secrets and
+// subfolders it holds. Only the totals are requested, not the records.
+func (c *apiClient) folderStats(ctx context.Context, folderID int) (*folderStats, error) {
+	path, err := c.folderPath(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	stats := &folderStats{FolderPath: path}
+
+	count := func(endpoint string, query url.Values) (int, error) {
+		query.Set("take", "1")
+		var page struct {
+			Total int
+		}
+		if err := c.do(ctx, "GET", endpoint+"?"+query.Encode(), nil, &page); err != nil {
+			return 0, err
+		}
+		return page.Total, nil
+	}
+
+	id := strconv.Itoa(folderID)
+	if stats.SecretCount, err = count("secrets", url.Values{
+		"filter.folderId":          {id},
+		"filter.includeSubFolders": {"false"},
+	}); err != nil {
+		return nil, err
+	}
+	if stats.TotalSecretCount, err = count("secrets", url.Values{
+		"filter.folderId":          {id},
+		"filter.includeSubFolders": {"true"},
+	}); err != nil {
+		return nil, err
+	}
+	if stats.SubfolderCount, err = count("folders", url.Values{
+		"filter.parentFolderId": {id},
+	}); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -2,12 +2,17 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/DelineaXPM/tss-sdk-go/v2/server"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -19,7 +24,12 @@ func NewTssSecretDataSource() datasource.DataSource {
 
 // TssSecretDataSource defines the data source implementation
 type TssSecretDataSource struct {
-	client *server.Server // Store the provider configuration
+	client   *server.Server // Store the provider configuration
+	api      *apiClient
+	defaults callOptions
+	logging  loggingConfig
+	// identities are the provider's auth_alias credentials
+	identities map[string]providerIdentity
 }
 
 // Metadata provides the data source type name
@@ -47,9 +57,55 @@ func (d *TssSecretDataSource) Schema(ctx context.Context, req datasource.SchemaR
 			"value": schema.StringAttribute{
 				Computed:    true,
 				Sensitive:   true,
-				Description: "The value of the requested field from the secret.",
+				Description: "The value of the requested field from the secret. Null, with a warning, when the secret requires checkout, a comment or approval and could not be read.",
+			},
+			"fail_if_empty": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Fail with an error when the requested field exists but has an empty value.",
+			},
+			"min_last_changed": schema.StringAttribute{
+				Optional: true,
+				Description: "Fail with an error when the secret's password was last changed successfully before this point, " +
+					"has never been changed, or failed its last change. Either an RFC3339 timestamp or a maximum age such as \"90d\" or \"72h\".",
+			},
+			"last_changed": schema.StringAttribute{
+				Computed:    true,
+				Description: "When the secret's password was last changed successfully, in RFC3339 format. Only set when min_last_changed is configured.",
+			},
+			"folder_path": schema.StringAttribute{
+				Computed:    true,
+				Description: "The full path of the secret's folder, e.g. \\IT\\Prod\\DBs.",
+			},
+			"requires_checkout": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the secret must be checked out before its value can be read. Null when the secret's summary cannot be read.",
+			},
+			"requires_comment": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether a comment must be given to read the secret. Null when the secret's summary cannot be read.",
+			},
+			"requires_approval": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether access to the secret must be approved before its value can be read. Null when the secret's summary cannot be read.",
+			},
+			"include_inactive": schema.BoolAttribute{
+				Optional: true,
+				Description: "Read the secret even when it has been deactivated, e.g. to report it before it is purged. " +
+					"The value of an inactive secret cannot be read, so it is null and active is false. Otherwise an inactive secret fails the read.",
+			},
+			"active": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the secret is active.",
+			},
+			"timeout": dataSourceTimeoutAttribute(),
+			"auth_alias": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name of a provider auth_alias block whose credentials to read the secret with, instead of the provider's own.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"retry": dataSourceRetryBlock(),
+		},
 	}
 }
 
@@ -67,10 +123,10 @@ func (d *TssSecretDataSource) Configure(ctx context.Context, req datasource.Conf
 	// Log the received ProviderData
 	tflog.Debug(ctx, "Provider data received, attempting to configure")
 
-	client, ok := req.ProviderData.(*server.Server)
-	if !ok || client == nil {
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok || providerData == nil {
 		tflog.Error(ctx, "Invalid provider data type", map[string]interface{}{
-			"expected": "*server.Configuration",
+			"expected": "*TssProviderData",
 			"actual":   fmt.Sprintf("%T", req.ProviderData),
 		})
 		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
@@ -80,18 +136,35 @@ func (d *TssSecretDataSource) Configure(ctx context.Context, req datasource.Conf
 	// Log the successfully retrieved configuration
 	tflog.Debug(ctx, "Successfully configured TssSecretDataSource")
 
-	d.client = client
+	d.client = providerData.Server
+	d.logging = providerData.Logging
+	d.api = providerData.API
+	d.identities = providerData.Identities
+	d.defaults = providerData.Defaults
 }
 
 // Read retrieves the data for the data source
 func (d *TssSecretDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.logging.context(ctx)
 	tflog.Debug(ctx, "Reading TssSecretDataSource")
 
 	// Define the state structure
 	var state struct {
-		SecretID    types.String `tfsdk:"id"`
-		Field       types.String `tfsdk:"field"`
-		SecretValue types.String `tfsdk:"value"`
+		SecretID        types.String `tfsdk:"id"`
+		Field           types.String `tfsdk:"field"`
+		SecretValue     types.String `tfsdk:"value"`
+		FailIfEmpty     types.Bool   `tfsdk:"fail_if_empty"`
+		MinLastChanged  types.String `tfsdk:"min_last_changed"`
+		LastChanged     types.String `tfsdk:"last_changed"`
+		FolderPath      types.String `tfsdk:"folder_path"`
+		NeedsCheckout   types.Bool   `tfsdk:"requires_checkout"`
+		NeedsComment    types.Bool   `tfsdk:"requires_comment"`
+		NeedsApproval   types.Bool   `tfsdk:"requires_approval"`
+		IncludeInactive types.Bool   `tfsdk:"include_inactive"`
+		Active          types.Bool   `tfsdk:"active"`
+		Retry           *RetryModel  `tfsdk:"retry"`
+		Timeout         types.String `tfsdk:"timeout"`
+		AuthAlias       types.String `tfsdk:"auth_alias"`
 	}
 
 	// Read the configuration from the request
@@ -104,6 +177,12 @@ func (d *TssSecretDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
+	d, aliasDiags := d.withAuthAlias(state.AuthAlias)
+	resp.Diagnostics.Append(aliasDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Ensure the client configuration is set
 	if d.client == nil {
 		tflog.Error(ctx, "Client configuration is nil")
@@ -122,13 +201,79 @@ func (d *TssSecretDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
+	unlock, err := lockSecret(ctx, secretID)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Lock Error", fmt.Sprintf("Gave up waiting for another operation on secret %d: %s", secretID, err))
+		return
+	}
+	defer unlock()
+
 	tflog.Info(ctx, "Fetching secret from TSS", map[string]interface{}{
 		"secret_id": secretID,
 		"field":     state.Field.ValueString(),
 	})
 
+	opts, diags := d.defaults.forOperation("read").withOverrides(state.Retry, state.Timeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withCallOptions(ctx, opts)
+
+	// The summary is readable even when the value is not, so report the access
+	// requirements before fetching the secret. They are informational, so they
+	// are left unset when the summary cannot be read.
+	state.NeedsCheckout = types.BoolNull()
+	state.NeedsComment = types.BoolNull()
+	state.NeedsApproval = types.BoolNull()
+	restricted := false
+	summary, summaryErr := d.api.secretStatus(ctx, secretID)
+	if summaryErr != nil {
+		tflog.Debug(ctx, "Unable to read secret summary", map[string]interface{}{
+			"secret_id": secretID,
+			"error":     summaryErr.Error(),
+		})
+	} else {
+		state.NeedsCheckout = types.BoolValue(summary.CheckOutEnabled)
+		state.NeedsComment = types.BoolValue(summary.RequiresComment)
+		state.NeedsApproval = types.BoolValue(summary.RequiresApproval)
+		restricted = summary.CheckOutEnabled || summary.RequiresComment || summary.RequiresApproval
+	}
+
 	// Fetch the secret
-	secret, err := d.client.Secret(secretID)
+	secret, err := newSDKClient(ctx, d.client, d.api, opts).Secret(secretID)
+	if err != nil && summary != nil && !summary.Active {
+		if !state.IncludeInactive.ValueBool() {
+			resp.Diagnostics.AddError("Secret Inactive",
+				fmt.Sprintf("Secret %d has been deactivated, so its value cannot be read. Set include_inactive to read the secret without its value.", secretID))
+			return
+		}
+		tflog.Debug(ctx, "Reading inactive secret without its value", map[string]interface{}{
+			"secret_id": secretID,
+		})
+		state.SecretValue = types.StringNull()
+		state.LastChanged = types.StringNull()
+		state.Active = types.BoolValue(false)
+		state.FolderPath = d.api.folderPathValue(ctx, summary.FolderID)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+	state.Active = types.BoolValue(true)
+	if err != nil && restricted {
+		// Leave the value unset rather than failing, so configurations can
+		// branch on the requirements
+		tflog.Warn(ctx, "Secret value is not readable without checkout, comment or approval", map[string]interface{}{
+			"secret_id": secretID,
+			"error":     err.Error(),
+		})
+		resp.Diagnostics.AddWarning("Secret Value Not Read",
+			fmt.Sprintf("Secret %d requires checkout, a comment or approval before its value can be read, so value is not set: %s", secretID, err))
+		state.SecretValue = types.StringNull()
+		state.LastChanged = types.StringNull()
+		state.FolderPath = d.api.folderPathValue(ctx, summary.FolderID)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
 	if err != nil {
 		tflog.Error(ctx, "Failed to fetch secret", map[string]interface{}{
 			"secret_id": secretID,
@@ -156,16 +301,119 @@ func (d *TssSecretDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
+	if fieldValue == "" && state.FailIfEmpty.ValueBool() {
+		tflog.Error(ctx, "Field value is empty", map[string]interface{}{
+			"secret_id": secretID,
+			"field":     fieldName,
+		})
+		resp.Diagnostics.AddAttributeError(
+			path.Root("field"),
+			"Empty Field Value",
+			fmt.Sprintf("The field '%s' of secret %d is empty and fail_if_empty is set.", fieldName, secretID),
+		)
+		return
+	}
+
 	tflog.Info(ctx, "Successfully retrieved secret field", map[string]interface{}{
 		"secret_id": secretID,
 		"field":     fieldName,
 		"has_value": fieldValue != "",
 	})
 
+	// Enforce the freshness constraint, if any
+	state.LastChanged = types.StringNull()
+	if !state.MinLastChanged.IsNull() && state.MinLastChanged.ValueString() != "" {
+		threshold, err := parseFreshnessThreshold(state.MinLastChanged.ValueString(), time.Now())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("min_last_changed"), "Invalid Freshness Constraint", err.Error())
+			return
+		}
+
+		if summaryErr != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("min_last_changed"),
+				"Secret Freshness Unknown",
+				fmt.Sprintf("Unable to read the summary of secret %d, which dates its last password change: %s", secretID, summaryErr),
+			)
+			return
+		}
+		lastChanged, err := summary.lastSuccessfulChange()
+		if errors.Is(err, errNeverChanged) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("min_last_changed"),
+				"Secret Never Rotated",
+				fmt.Sprintf("The password of secret %d has never been changed, so it cannot meet min_last_changed. "+
+					"Rotate the secret or remove min_last_changed.", secretID),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("min_last_changed"),
+				"Secret Freshness Unknown",
+				fmt.Sprintf("Unable to tell when the password of secret %d was last changed successfully: %s", secretID, err),
+			)
+			return
+		}
+		state.LastChanged = types.StringValue(lastChanged.UTC().Format(time.RFC3339))
+
+		if lastChanged.Before(threshold) {
+			tflog.Error(ctx, "Secret password is older than allowed", map[string]interface{}{
+				"secret_id":    secretID,
+				"last_changed": state.LastChanged.ValueString(),
+				"threshold":    threshold.UTC().Format(time.RFC3339),
+			})
+			resp.Diagnostics.AddAttributeError(
+				path.Root("min_last_changed"),
+				"Secret Too Old",
+				fmt.Sprintf("The password of secret %d was last changed at %s, before the required %s.",
+					secretID, state.LastChanged.ValueString(), threshold.UTC().Format(time.RFC3339)),
+			)
+			return
+		}
+	}
+
+	state.FolderPath = d.api.folderPathValue(ctx, secret.FolderID)
+
 	// Set the secret value in the state
 	state.SecretValue = types.StringValue(fieldValue)
+	state.Active = types.BoolValue(secret.Active)
 
 	// Set the state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
+
+// parseFreshnessThreshold converts a min_last_changed value into the oldest acceptable
+// change time. The value is either an RFC3339 timestamp or a maximum age expressed as a
+// Go duration, with an additional "d" suffix for whole days.
+func parseFreshnessThreshold(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC3339 timestamp nor a duration such as \"90d\" or \"72h\"", value)
+	}
+	return now.Add(-age), nil
+}
+
+// withAuthAlias returns the data source acting with the credentials of alias
+func (d *TssSecretDataSource) withAuthAlias(alias types.String) (*TssSecretDataSource, diag.Diagnostics) {
+	s, api, diags := selectIdentity(d.identities, alias, d.client, d.api)
+	if diags.HasError() || api == d.api {
+		return d, diags
+	}
+	copied := *d
+	copied.client = s
+	copied.api = api
+	return &copied, diags
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// NewTssSecretDependencyTemplatesDataSource is a helper function to simplify the provider implementation.
+func NewTssSecretDependencyTemplatesDataSource() datasource.DataSource {
+	return &TssSecretDependencyTemplatesDataSource{}
+}
+
+// TssSecretDependencyTemplatesDataSource lists the secret dependency templates,
+// whose IDs differ between Secret Server instances
+type TssSecretDependencyTemplatesDataSource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// SecretDependencyTemplatesModel defines the state structure for the secret dependency templates data source
+type SecretDependencyTemplatesModel struct {
+	Name      types.String                   `tfsdk:"name"`
+	Templates []SecretDependencyTemplateItem `tfsdk:"templates"`
+	IDs       types.Map                      `tfsdk:"ids"`
+}
+
+// SecretDependencyTemplateItem is a secret dependency template
+type SecretDependencyTemplateItem struct {
+	ID          types.Int64  `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	ChangerID   types.Int64  `tfsdk:"changer_id"`
+	ChangerName types.String `tfsdk:"changer_name"`
+	Active      types.Bool   `tfsdk:"active"`
+}
+
+// secretDependencyTemplate is a secret dependency template as returned by Secret Server
+type secretDependencyTemplate struct {
+	ID                          int
+	Name                        string
+	SecretDependencyChangerID   int
+	SecretDependencyChangerName string
+	Active                      bool
+}
+
+// secretDependencyTemplates returns the secret dependency templates
+func (c *apiClient) secretDependencyTemplates(ctx context.Context) ([]secretDependencyTemplate, error) {
+	var templates []secretDependencyTemplate
+	if err := c.do(ctx, "GET", "secret-dependencies/templates", nil, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Metadata provides the data source type name
+func (d *TssSecretDependencyTemplatesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "dept-tss_secret_dependency_templates"
+}
+
+// Schema defines the schema for the data source
+func (d *TssSecretDependencyTemplatesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the secret dependency templates and the changers that update them, e.g. \"Windows Service\" or \"IIS App Pool\", " +
+			"so dependencies can refer to them by name on servers where their IDs differ.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list the template with this name, compared case-insensitively. Reading fails when there is none.",
+			},
+			"templates": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The dependency templates, ordered by ID",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The ID of the dependency template",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The name of the dependency template, e.g. Windows Service",
+						},
+						"changer_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The ID of the dependency changer that updates dependencies of the template",
+						},
+						"changer_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The name of the dependency changer",
+						},
+						"active": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the template is active",
+						},
+					},
+				},
+			},
+			"ids": schema.MapAttribute{
+				ElementType: types.Int64Type,
+				Computed:    true,
+				Description: "The ID of each template keyed by name, e.g. ids[\"IIS App Pool\"]",
+			},
+		},
+	}
+}
+
+// Configure initializes the data source with the provider configuration
+func (d *TssSecretDependencyTemplatesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	d.api = providerData.API
+	d.logging = providerData.Logging
+}
+
+// Read fetches the dependency templates
+func (d *TssSecretDependencyTemplatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.logging.context(ctx)
+	var state SecretDependencyTemplatesModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templates, err := d.api.secretDependencyTemplates(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Dependency Templates Error", fmt.Sprintf("Failed to read the secret dependency templates: %s", err))
+		return
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+	tflog.Debug(ctx, "Read secret dependency templates", map[string]interface{}{
+		"templates": len(templates),
+	})
+
+	state.Templates = make([]SecretDependencyTemplateItem, 0, len(templates))
+	ids := map[string]int64{}
+	for _, template := range templates {
+		if !state.Name.IsNull() && !strings.EqualFold(template.Name, state.Name.ValueString()) {
+			continue
+		}
+		state.Templates = append(state.Templates, SecretDependencyTemplateItem{
+			ID:          types.Int64Value(int64(template.ID)),
+			Name:        types.StringValue(template.Name),
+			ChangerID:   types.Int64Value(int64(template.SecretDependencyChangerID)),
+			ChangerName: types.StringValue(template.SecretDependencyChangerName),
+			Active:      types.BoolValue(template.Active),
+		})
+		ids[template.Name] = int64(template.ID)
+	}
+
+	if !state.Name.IsNull() && len(state.Templates) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Secret Dependency Template Not Found",
+			fmt.Sprintf("Secret Server has no secret dependency template named %q.", state.Name.ValueString()))
+		return
+	}
+
+	idsValue, diags := types.MapValueFrom(ctx, types.Int64Type, ids)
+	resp.Diagnostics.Append(diags...)
+	state.IDs = idsValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
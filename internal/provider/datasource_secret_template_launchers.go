@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// NewTssSecretTemplateLaunchersDataSource is a helper function to simplify the provider implementation.
+func NewTssSecretTemplateLaunchersDataSource() datasource.DataSource {
+	return &TssSecretTemplateLaunchersDataSource{}
+}
+
+// TssSecretTemplateLaunchersDataSource lists the launchers mapped to a secret template
+type TssSecretTemplateLaunchersDataSource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// SecretTemplateLaunchersModel defines the state structure for the secret template launchers data source
+type SecretTemplateLaunchersModel struct {
+	TemplateID types.Int64                  `tfsdk:"template_id"`
+	Launchers  []SecretTemplateLauncherItem `tfsdk:"launchers"`
+}
+
+// SecretTemplateLauncherItem is a launcher of the secret template
+type SecretTemplateLauncherItem struct {
+	LauncherTypeID types.Int64  `tfsdk:"launcher_type_id"`
+	LauncherName   types.String `tfsdk:"launcher_name"`
+	FieldMappings  types.Map    `tfsdk:"field_mappings"`
+}
+
+// Metadata provides the data source type name
+func (d *TssSecretTemplateLaunchersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "dept-tss_secret_template_launchers"
+}
+
+// Schema defines the schema for the data source
+func (d *TssSecretTemplateLaunchersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the launchers mapped to a secret template and the template fields supplying their arguments.",
+		Attributes: map[string]schema.Attribute{
+			"template_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the secret template",
+			},
+			"launchers": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The launchers of the template, ordered by launcher type ID",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"launcher_type_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The ID of the launcher type",
+						},
+						"launcher_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The name of the launcher type",
+						},
+						"field_mappings": schema.MapAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "The template field slug supplying each launcher argument, keyed by argument",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure initializes the data source with the provider configuration
+func (d *TssSecretTemplateLaunchersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	d.api = providerData.API
+	d.logging = providerData.Logging
+}
+
+// Read fetches the launchers of the template
+func (d *TssSecretTemplateLaunchersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.logging.context(ctx)
+	var state SecretTemplateLaunchersModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateID := int(state.TemplateID.ValueInt64())
+	launchers, err := d.api.templateLaunchers(ctx, templateID)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Template Launchers Error", fmt.Sprintf("Failed to read the launchers of template %d: %s", templateID, err))
+		return
+	}
+	sort.Slice(launchers, func(i, j int) bool { return launchers[i].LauncherTypeID < launchers[j].LauncherTypeID })
+
+	tflog.Debug(ctx, "Read secret template launchers", map[string]interface{}{
+		"template_id": templateID,
+		"launchers":   len(launchers),
+	})
+
+	state.Launchers = make([]SecretTemplateLauncherItem, 0, len(launchers))
+	for _, launcher := range launchers {
+		mappings, diags := launcherFieldMappingsValue(ctx, launcher.FieldMappings)
+		resp.Diagnostics.Append(diags...)
+		state.Launchers = append(state.Launchers, SecretTemplateLauncherItem{
+			LauncherTypeID: types.Int64Value(int64(launcher.LauncherTypeID)),
+			LauncherName:   types.StringValue(launcher.LauncherTypeName),
+			FieldMappings:  mappings,
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSecretDataSourceReadsWithoutSummary(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/secrets/5":
+			_, _ = w.Write([]byte(`{"id":5,"name":"db","folderId":-1,"active":true,"items":[{"slug":"password","fieldName":"Password","itemValue":"s3cret"}]}`))
+		default:
+			http.Error(w, `{"message":"Access denied"}`, http.StatusForbidden)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	s, err := server.New(server.Configuration{ServerURL: ts.URL, Credentials: server.UserCredential{Token: "token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &TssSecretDataSource{client: s, api: newTestAPIClient(ts.URL)}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	attributes["id"] = tftypes.NewValue(tftypes.String, "5")
+	attributes["field"] = tftypes.NewValue(tftypes.String, "password")
+	raw := tftypes.NewValue(objectType, attributes)
+
+	req := datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: raw}}
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: raw}}
+	d.Read(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("the read failed: %v", resp.Diagnostics)
+	}
+
+	var value types.String
+	var requiresCheckout types.Bool
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("value"), &value)...)
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("requires_checkout"), &requiresCheckout)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	if value.ValueString() != "s3cret" {
+		t.Errorf("value is %s", value)
+	}
+	if !requiresCheckout.IsNull() {
+		t.Errorf("requires_checkout is %s, want null", requiresCheckout)
+	}
+}
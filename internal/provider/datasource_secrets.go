@@ -3,14 +3,21 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+var _ datasource.DataSourceWithConfigValidators = &TssSecretsDataSource{}
+
 // With the datasource.DataSource implementation
 func NewTssSecretsDataSource() datasource.DataSource {
 	return &TssSecretsDataSource{}
@@ -18,7 +25,20 @@ func NewTssSecretsDataSource() datasource.DataSource {
 
 // TssSecretsDataSource defines the data source implementation
 type TssSecretsDataSource struct {
-	client *server.Server // Store the provider configuration
+	client   *server.Server // Store the provider configuration
+	api      *apiClient
+	defaults callOptions
+	logging  loggingConfig
+	// identities are the provider's auth_alias credentials
+	identities map[string]providerIdentity
+}
+
+// SecretDataModel is a single secret returned by the secrets data source
+type SecretDataModel struct {
+	ID         types.Int64  `tfsdk:"id"`
+	Value      types.String `tfsdk:"value"`
+	FolderPath types.String `tfsdk:"folder_path"`
+	Active     types.Bool   `tfsdk:"active"`
 }
 
 // Metadata provides the data source type name
@@ -29,6 +49,45 @@ func (d *TssSecretsDataSource) Metadata(ctx context.Context, req datasource.Meta
 	})
 }
 
+// ConfigValidators rejects the folder search filters without folder_id, as they
+// would otherwise be ignored
+func (d *TssSecretsDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		alsoRequiresValidator{attribute: "recursive", requires: "folder_id"},
+		alsoRequiresValidator{attribute: "template_id", requires: "folder_id"},
+	}
+}
+
+// alsoRequiresValidator rejects a configured attribute when the attribute it
+// depends on is not configured
+type alsoRequiresValidator struct {
+	attribute string
+	requires  string
+}
+
+func (v alsoRequiresValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("%s may only be configured with %s.", v.attribute, v.requires)
+}
+
+func (v alsoRequiresValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("`%s` may only be configured with `%s`.", v.attribute, v.requires)
+}
+
+func (v alsoRequiresValidator) ValidateDataSource(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var value, required attr.Value
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(v.attribute), &value)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(v.requires), &required)...)
+	if resp.Diagnostics.HasError() || value.IsNull() || !required.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root(v.attribute),
+		"Missing Required Attribute",
+		fmt.Sprintf("%s only applies to the folder search, so it requires %s to be set.", v.attribute, v.requires),
+	)
+}
+
 // Schema defines the schema for the data source
 func (d *TssSecretsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	tflog.Trace(ctx, "Defining schema for TssSecretsDataSource")
@@ -37,16 +96,34 @@ func (d *TssSecretsDataSource) Schema(ctx context.Context, req datasource.Schema
 		Attributes: map[string]schema.Attribute{
 			"ids": schema.ListAttribute{
 				ElementType: types.Int64Type,
-				Required:    true,
-				Description: "A list of IDs of the secrets",
+				Optional:    true,
+				Computed:    true,
+				Description: "A list of IDs of the secrets. Conflicts with folder_id, which resolves it",
+			},
+			"folder_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The ID of a folder whose secrets should be fetched. Conflicts with ids",
+			},
+			"recursive": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether secrets in subfolders of folder_id are included. Requires folder_id",
+			},
+			"template_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Only fetch secrets of folder_id created from this secret template. Requires folder_id",
 			},
 			"field": schema.StringAttribute{
 				Required:    true,
 				Description: "The field to extract from the secrets",
 			},
+			"include_inactive": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether deactivated secrets are included, e.g. to report them before they are purged. " +
+					"Their values cannot be read, so they are returned with active false and a null value",
+			},
 			"secrets": schema.ListNestedAttribute{
 				Computed:    true,
-				Description: "A list of secrets with their field values",
+				Description: "A list of secrets with their field values, sorted by ID",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"id": schema.Int64Attribute{
@@ -58,9 +135,50 @@ func (d *TssSecretsDataSource) Schema(ctx context.Context, req datasource.Schema
 							Sensitive:   true,
 							Description: "The ephemeral value of the field of the secret",
 						},
+						"folder_path": schema.StringAttribute{
+							Computed:    true,
+							Description: "The full path of the secret's folder",
+						},
+						"active": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the secret is active. Only inactive with include_inactive, and then value is null",
+						},
+					},
+				},
+			},
+			"secrets_by_id": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "The secrets with their field values, keyed by secret ID",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The ID of the secret",
+						},
+						"value": schema.StringAttribute{
+							Computed:    true,
+							Sensitive:   true,
+							Description: "The value of the field of the secret",
+						},
+						"folder_path": schema.StringAttribute{
+							Computed:    true,
+							Description: "The full path of the secret's folder",
+						},
+						"active": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the secret is active. Only inactive with include_inactive, and then value is null",
+						},
 					},
 				},
 			},
+			"timeout": dataSourceTimeoutAttribute(),
+			"auth_alias": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name of a provider auth_alias block whose credentials to read the secrets with, instead of the provider's own.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": dataSourceRetryBlock(),
 		},
 	}
 }
@@ -80,10 +198,10 @@ func (d *TssSecretsDataSource) Configure(ctx context.Context, req datasource.Con
 	tflog.Debug(ctx, "Provider data received, attempting to configure")
 
 	// Retrieve the provider configuration
-	client, ok := req.ProviderData.(*server.Server)
+	providerData, ok := req.ProviderData.(*TssProviderData)
 	if !ok {
 		tflog.Error(ctx, "Invalid provider data type", map[string]interface{}{
-			"expected": "*server.Configuration",
+			"expected": "*TssProviderData",
 			"actual":   fmt.Sprintf("%T", req.ProviderData),
 		})
 		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
@@ -91,20 +209,30 @@ func (d *TssSecretsDataSource) Configure(ctx context.Context, req datasource.Con
 	}
 
 	// Store the provider configuration in the data source
-	d.client = client
+	d.client = providerData.Server
+	d.logging = providerData.Logging
+	d.api = providerData.API
+	d.identities = providerData.Identities
+	d.defaults = providerData.Defaults
 	tflog.Debug(ctx, "Successfully configured TssSecretsDataSource")
 }
 
 func (d *TssSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.logging.context(ctx)
 	tflog.Debug(ctx, "Reading TssSecretsDataSource")
 
 	var state struct {
-		IDs     []types.Int64 `tfsdk:"ids"`
-		Field   types.String  `tfsdk:"field"`
-		Secrets []struct {
-			ID    types.Int64  `tfsdk:"id"`
-			Value types.String `tfsdk:"value"`
-		} `tfsdk:"secrets"`
+		IDs             []types.Int64              `tfsdk:"ids"`
+		FolderID        types.Int64                `tfsdk:"folder_id"`
+		Recursive       types.Bool                 `tfsdk:"recursive"`
+		TemplateID      types.Int64                `tfsdk:"template_id"`
+		Field           types.String               `tfsdk:"field"`
+		IncludeInactive types.Bool                 `tfsdk:"include_inactive"`
+		Retry           *RetryModel                `tfsdk:"retry"`
+		Timeout         types.String               `tfsdk:"timeout"`
+		Secrets         []SecretDataModel          `tfsdk:"secrets"`
+		SecretsByID     map[string]SecretDataModel `tfsdk:"secrets_by_id"`
+		AuthAlias       types.String               `tfsdk:"auth_alias"`
 	}
 
 	// Read the configuration
@@ -117,6 +245,12 @@ func (d *TssSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
+	d, aliasDiags := d.withAuthAlias(state.AuthAlias)
+	resp.Diagnostics.Append(aliasDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Ensure the client configuration is set
 	if d.client == nil {
 		tflog.Error(ctx, "Client configuration is nil")
@@ -124,16 +258,66 @@ func (d *TssSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
+	opts, diags := d.defaults.forOperation("read").withOverrides(state.Retry, state.Timeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withCallOptions(ctx, opts)
+	client := newSDKClient(ctx, d.client, d.api, opts)
+
+	// Inactive secrets found by the folder search are reported without
+	// fetching their values
+	inactive := map[int]secretSummary{}
+
+	// Exactly one of ids and folder_id selects the secrets
+	hasFolder := !state.FolderID.IsNull()
+	if hasFolder == (state.IDs != nil) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ids"),
+			"Invalid Secret Selection",
+			"Exactly one of ids or folder_id must be set.",
+		)
+		return
+	}
+
+	if hasFolder {
+		tflog.Debug(ctx, "Resolving secrets in folder", map[string]interface{}{
+			"folder_id":   state.FolderID.ValueInt64(),
+			"recursive":   state.Recursive.ValueBool(),
+			"template_id": state.TemplateID.ValueInt64(),
+		})
+
+		summaries, err := d.api.searchSecrets(ctx, secretSearchFilter{
+			FolderID:          int(state.FolderID.ValueInt64()),
+			IncludeSubFolders: state.Recursive.ValueBool(),
+			TemplateID:        int(state.TemplateID.ValueInt64()),
+			IncludeInactive:   state.IncludeInactive.ValueBool(),
+		})
+		if err != nil {
+			tflog.Error(ctx, "Failed to search folder for secrets", map[string]interface{}{
+				"folder_id": state.FolderID.ValueInt64(),
+				"error":     err.Error(),
+			})
+			resp.Diagnostics.AddError("Secret Search Error", fmt.Sprintf("Failed to list secrets in folder %d: %s", state.FolderID.ValueInt64(), err))
+			return
+		}
+
+		for _, summary := range summaries {
+			state.IDs = append(state.IDs, types.Int64Value(int64(summary.ID)))
+			if !summary.Active {
+				inactive[summary.ID] = summary
+			}
+		}
+	}
+
 	tflog.Info(ctx, "Fetching multiple secrets from Tss", map[string]interface{}{
 		"count": len(state.IDs),
 		"field": state.Field.ValueString(),
 	})
 
 	// Fetch secrets
-	var results []struct {
-		ID    types.Int64  `tfsdk:"id"`
-		Value types.String `tfsdk:"value"`
-	}
+	var results []SecretDataModel
 
 	successCount := 0
 	failedCount := 0
@@ -141,12 +325,34 @@ func (d *TssSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	for _, id := range state.IDs {
 		secretID := int(id.ValueInt64())
 
+		if summary, ok := inactive[secretID]; ok {
+			results = append(results, d.inactiveSecret(ctx, summary))
+			successCount++
+			continue
+		}
+
 		tflog.Debug(ctx, "Fetching secret", map[string]interface{}{
 			"secret_id": secretID,
 		})
 
 		// Fetch the secret
-		secret, err := d.client.Secret(secretID)
+		unlock, err := lockSecret(ctx, secretID)
+		if err != nil {
+			resp.Diagnostics.AddError("Secret Lock Error", fmt.Sprintf("Gave up waiting for another operation on secret %d: %s", secretID, err))
+			return
+		}
+		secret, err := client.Secret(secretID)
+		unlock()
+		if err != nil && state.IncludeInactive.ValueBool() {
+			// The value of an inactive secret cannot be read, but the
+			// secret is still reported
+			if summary, lookupErr := d.api.lookupSecret(ctx, secretID); lookupErr == nil && !summary.Active {
+				summary.ID = secretID
+				results = append(results, d.inactiveSecret(ctx, *summary))
+				successCount++
+				continue
+			}
+		}
 		if err != nil {
 			tflog.Warn(ctx, "Failed to fetch secret, skipping", map[string]interface{}{
 				"secret_id": secretID,
@@ -183,12 +389,11 @@ func (d *TssSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		})
 
 		// Save the secret value in the state
-		results = append(results, struct {
-			ID    types.Int64  `tfsdk:"id"`
-			Value types.String `tfsdk:"value"`
-		}{
-			ID:    types.Int64Value(int64(secretID)),
-			Value: types.StringValue(fieldValue),
+		results = append(results, SecretDataModel{
+			ID:         types.Int64Value(int64(secretID)),
+			Value:      types.StringValue(fieldValue),
+			FolderPath: d.api.folderPathValue(ctx, secret.FolderID),
+			Active:     types.BoolValue(secret.Active),
 		})
 		successCount++
 	}
@@ -199,6 +404,17 @@ func (d *TssSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		"failed":     failedCount,
 	})
 
+	// Sort by ID so for_each over the results does not churn when the server
+	// returns secrets in a different order
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID.ValueInt64() < results[j].ID.ValueInt64()
+	})
+
+	state.SecretsByID = make(map[string]SecretDataModel, len(results))
+	for _, result := range results {
+		state.SecretsByID[strconv.FormatInt(result.ID.ValueInt64(), 10)] = result
+	}
+
 	// Set the state
 	state.Secrets = results
 	diags = resp.State.Set(ctx, &state)
@@ -212,3 +428,29 @@ func (d *TssSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequ
 
 	tflog.Debug(ctx, "TssSecretsDataSource read completed successfully")
 }
+
+// inactiveSecret is an inactive secret as reported by the data source: without
+// a value, as Secret Server does not return the values of inactive secrets
+func (d *TssSecretsDataSource) inactiveSecret(ctx context.Context, summary secretSummary) SecretDataModel {
+	tflog.Debug(ctx, "Reporting inactive secret", map[string]interface{}{
+		"secret_id": summary.ID,
+	})
+	return SecretDataModel{
+		ID:         types.Int64Value(int64(summary.ID)),
+		Value:      types.StringNull(),
+		FolderPath: d.api.folderPathValue(ctx, summary.FolderID),
+		Active:     types.BoolValue(false),
+	}
+}
+
+// withAuthAlias returns the data source acting with the credentials of alias
+func (d *TssSecretsDataSource) withAuthAlias(alias types.String) (*TssSecretsDataSource, diag.Diagnostics) {
+	s, api, diags := selectIdentity(d.identities, alias, d.client, d.api)
+	if diags.HasError() || api == d.api {
+		return d, diags
+	}
+	copied := *d
+	copied.client = s
+	copied.api = api
+	return &copied, diags
+}
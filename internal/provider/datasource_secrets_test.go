@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// secretsDataSourceConfig returns a configuration of the secrets data source
+// with the given attributes set and the others null
+func secretsDataSourceConfig(t *testing.T, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+	var resp datasource.SchemaResponse
+	NewTssSecretsDataSource().Schema(ctx, datasource.SchemaRequest{}, &resp)
+
+	objectType := resp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		}
+	}
+	return tfsdk.Config{Schema: resp.Schema, Raw: tftypes.NewValue(objectType, attributes)}
+}
+
+func TestSecretsDataSourceFiltersRequireFolder(t *testing.T) {
+	ctx := context.Background()
+	field := tftypes.NewValue(tftypes.String, "password")
+	for name, tc := range map[string]struct {
+		values map[string]tftypes.Value
+		errors int
+	}{
+		"folder search": {map[string]tftypes.Value{
+			"field":       field,
+			"folder_id":   tftypes.NewValue(tftypes.Number, 4),
+			"recursive":   tftypes.NewValue(tftypes.Bool, true),
+			"template_id": tftypes.NewValue(tftypes.Number, 6003),
+		}, 0},
+		"filters with ids": {map[string]tftypes.Value{
+			"field":       field,
+			"ids":         tftypes.NewValue(tftypes.List{ElementType: tftypes.Number}, []tftypes.Value{tftypes.NewValue(tftypes.Number, 1)}),
+			"recursive":   tftypes.NewValue(tftypes.Bool, false),
+			"template_id": tftypes.NewValue(tftypes.Number, 6003),
+		}, 2},
+	} {
+		config := secretsDataSourceConfig(t, tc.values)
+		var errors int
+		for _, validator := range (&TssSecretsDataSource{}).ConfigValidators(ctx) {
+			var resp datasource.ValidateConfigResponse
+			validator.ValidateDataSource(ctx, datasource.ValidateConfigRequest{Config: config}, &resp)
+			errors += resp.Diagnostics.ErrorsCount()
+		}
+		if errors != tc.errors {
+			t.Errorf("%s: %d errors, want %d", name, errors, tc.errors)
+		}
+	}
+}
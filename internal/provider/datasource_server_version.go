@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// NewTssServerVersionDataSource is a helper function to simplify the provider implementation.
+func NewTssServerVersionDataSource() datasource.DataSource {
+	return &TssServerVersionDataSource{}
+}
+
+// TssServerVersionDataSource reports the version of Secret Server
+type TssServerVersionDataSource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// ServerVersionModel defines the state structure for the server version data source
+type ServerVersionModel struct {
+	Version types.String `tfsdk:"version"`
+	Major   types.Int64  `tfsdk:"major"`
+	Minor   types.Int64  `tfsdk:"minor"`
+}
+
+// Metadata provides the data source type name
+func (d *TssServerVersionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "dept-tss_server_version"
+}
+
+// Schema defines the schema for the data source
+func (d *TssServerVersionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reports the version of Secret Server, e.g. to only create resources in a precondition when the server has the APIs they need. " +
+			"To fail every plan against an older server, set minimum_server_version on the provider instead.",
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				Computed:    true,
+				Description: "The full version, e.g. 11.7.000049",
+			},
+			"major": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The major version, e.g. 11",
+			},
+			"minor": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The minor version, e.g. 7",
+			},
+		},
+	}
+}
+
+// Configure initializes the data source with the provider configuration
+func (d *TssServerVersionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	d.api = providerData.API
+	d.logging = providerData.Logging
+}
+
+// Read fetches the version
+func (d *TssServerVersionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.logging.context(ctx)
+
+	version, err := d.api.serverVersion(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Server Version Error", fmt.Sprintf("Failed to read the version of Secret Server: %s", err))
+		return
+	}
+	numbers, err := parseVersion(version)
+	if err != nil {
+		resp.Diagnostics.AddError("Server Version Error", fmt.Sprintf("Secret Server reported an unrecognized version: %s", err))
+		return
+	}
+	numbers = append(numbers, 0, 0)
+
+	tflog.Debug(ctx, "Read Secret Server version", map[string]interface{}{
+		"server_version": version,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, ServerVersionModel{
+		Version: types.StringValue(version),
+		Major:   types.Int64Value(int64(numbers[0])),
+		Minor:   types.Int64Value(int64(numbers[1])),
+	})...)
+}
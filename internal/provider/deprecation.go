@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// deprecations collects the deprecation notices Secret Server sends for the
+// endpoints the provider calls, as they pass through the provider transport.
+// Each endpoint is reported once per run, by the first operation to finish
+// after the notice was received.
+var deprecations = &deprecationNotices{seen: map[string]bool{}}
+
+// deprecationNotices holds the notices received and those not yet reported
+type deprecationNotices struct {
+	mu      sync.Mutex
+	seen    map[string]bool
+	pending []string
+}
+
+// endpointIDPattern matches the IDs in an endpoint path, so the calls to an
+// endpoint share a notice whatever object they are for
+var endpointIDPattern = regexp.MustCompile(`/\d+(/|$)`)
+
+// record keeps the deprecation notice of a response, if it has one. Secret
+// Server marks deprecated endpoints with the Deprecation and Sunset headers, or
+// a Warning header with code 299.
+func (d *deprecationNotices) record(method, endpointPath string, header http.Header) {
+	deprecation, sunset := header.Get("Deprecation"), header.Get("Sunset")
+	var warnings []string
+	for _, warning := range header.Values("Warning") {
+		if code, text, ok := strings.Cut(warning, " "); ok && code == "299" {
+			warnings = append(warnings, warningText(text))
+		}
+	}
+	if deprecation == "" && sunset == "" && len(warnings) == 0 {
+		return
+	}
+
+	endpoint := endpointPath
+	if i := strings.Index(endpoint, "/"+apiPathURI+"/"); i >= 0 {
+		endpoint = endpoint[i+len(apiPathURI)+2:]
+	}
+	endpoint = method + " " + endpointIDPattern.ReplaceAllString(endpoint, "/{id}$1")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.seen[endpoint] {
+		return
+	}
+	d.seen[endpoint] = true
+
+	notice := fmt.Sprintf("Secret Server reports that %s, which this provider calls, is deprecated", endpoint)
+	if since := httpDate(deprecation); since != "" {
+		notice += " since " + since
+	}
+	if until := httpDate(sunset); until != "" {
+		notice += " and will be removed on " + until
+	}
+	notice += "."
+	for _, warning := range warnings {
+		notice += " " + strings.TrimSuffix(warning, ".") + "."
+	}
+	d.pending = append(d.pending, notice+" Upgrading Secret Server may require upgrading the provider.")
+}
+
+// take returns the notices not yet reported, which are then reported
+func (d *deprecationNotices) take() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	pending := d.pending
+	d.pending = nil
+	return pending
+}
+
+// withDeprecations returns diags with a warning added for each deprecation
+// notice not yet reported
+func withDeprecations(diags []*tfprotov6.Diagnostic) []*tfprotov6.Diagnostic {
+	for _, notice := range deprecations.take() {
+		diags = append(diags, &tfprotov6.Diagnostic{
+			Severity: tfprotov6.DiagnosticSeverityWarning,
+			Summary:  "Deprecated Secret Server API",
+			Detail:   notice,
+		})
+	}
+	return diags
+}
+
+// deprecationReportingServer reports the deprecation notices received during
+// each operation that calls Secret Server in the operation's response, so they
+// are reported once whichever data source, resource or ephemeral resource
+// made the calls
+type deprecationReportingServer struct {
+	tfprotov6.ProviderServer
+}
+
+func (s deprecationReportingServer) ConfigureProvider(ctx context.Context, req *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	resp, err := s.ProviderServer.ConfigureProvider(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = withDeprecations(resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s deprecationReportingServer) ReadResource(ctx context.Context, req *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	resp, err := s.ProviderServer.ReadResource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = withDeprecations(resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s deprecationReportingServer) PlanResourceChange(ctx context.Context, req *tfprotov6.PlanResourceChangeRequest) (*tfprotov6.PlanResourceChangeResponse, error) {
+	resp, err := s.ProviderServer.PlanResourceChange(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = withDeprecations(resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s deprecationReportingServer) ApplyResourceChange(ctx context.Context, req *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	resp, err := s.ProviderServer.ApplyResourceChange(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = withDeprecations(resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s deprecationReportingServer) ImportResourceState(ctx context.Context, req *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
+	resp, err := s.ProviderServer.ImportResourceState(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = withDeprecations(resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s deprecationReportingServer) ReadDataSource(ctx context.Context, req *tfprotov6.ReadDataSourceRequest) (*tfprotov6.ReadDataSourceResponse, error) {
+	resp, err := s.ProviderServer.ReadDataSource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = withDeprecations(resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s deprecationReportingServer) OpenEphemeralResource(ctx context.Context, req *tfprotov6.OpenEphemeralResourceRequest) (*tfprotov6.OpenEphemeralResourceResponse, error) {
+	resp, err := s.ProviderServer.OpenEphemeralResource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = withDeprecations(resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s deprecationReportingServer) RenewEphemeralResource(ctx context.Context, req *tfprotov6.RenewEphemeralResourceRequest) (*tfprotov6.RenewEphemeralResourceResponse, error) {
+	resp, err := s.ProviderServer.RenewEphemeralResource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = withDeprecations(resp.Diagnostics)
+	}
+	return resp, err
+}
+
+func (s deprecationReportingServer) CloseEphemeralResource(ctx context.Context, req *tfprotov6.CloseEphemeralResourceRequest) (*tfprotov6.CloseEphemeralResourceResponse, error) {
+	resp, err := s.ProviderServer.CloseEphemeralResource(ctx, req)
+	if resp != nil {
+		resp.Diagnostics = withDeprecations(resp.Diagnostics)
+	}
+	return resp, err
+}
+
+// httpDate returns the date of a Deprecation or Sunset header, which is an
+// HTTP date or, for Deprecation, a Unix time such as @1688169599. It returns
+// "" when the header holds no date, e.g. Deprecation: true.
+func httpDate(value string) string {
+	if seconds, ok := strings.CutPrefix(value, "@"); ok {
+		if unix, err := strconv.ParseInt(seconds, 10, 64); err == nil {
+			return time.Unix(unix, 0).UTC().Format(time.DateOnly)
+		}
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.UTC().Format(time.DateOnly)
+	}
+	return ""
+}
+
+// warningText returns the text of a Warning header after its code, without the
+// agent and the quotes around it
+func warningText(value string) string {
+	_, text, _ := strings.Cut(strings.TrimSpace(value), " ")
+	if quoted, err := strconv.QuotedPrefix(text); err == nil {
+		if unquoted, err := strconv.Unquote(quoted); err == nil {
+			return unquoted
+		}
+	}
+	return strings.TrimSpace(value)
+}
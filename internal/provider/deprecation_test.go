@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// readResourceServer answers ReadResource, calling Secret Server through transport
+type readResourceServer struct {
+	tfprotov6.ProviderServer
+	transport http.RoundTripper
+	url       string
+}
+
+func (s readResourceServer) ReadResource(ctx context.Context, req *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	res, err := (&http.Client{Transport: s.transport}).Get(s.url)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	return &tfprotov6.ReadResourceResponse{}, nil
+}
+
+func TestDeprecationsReportedOncePerOperation(t *testing.T) {
+	deprecations = &deprecationNotices{seen: map[string]bool{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "@1688169599")
+		w.Header().Set("Sunset", "Wed, 01 Jan 2025 00:00:00 GMT")
+	}))
+	defer server.Close()
+
+	transport := &providerTransport{base: baseTransport}
+	provider := deprecationReportingServer{readResourceServer{transport: transport, url: server.URL + "/SecretServer/api/v1/secrets/42"}}
+
+	resp, err := provider.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(resp.Diagnostics))
+	}
+	diag := resp.Diagnostics[0]
+	if diag.Severity != tfprotov6.DiagnosticSeverityWarning {
+		t.Errorf("severity = %s, want a warning", diag.Severity)
+	}
+	want := "GET secrets/{id}, which this provider calls, is deprecated since 2023-06-30 and will be removed on 2025-01-01."
+	if !strings.Contains(diag.Detail, want) {
+		t.Errorf("detail %q does not contain %q", diag.Detail, want)
+	}
+
+	// The endpoint was reported, so calling it again reports nothing
+	resp, err = provider.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 0 {
+		t.Errorf("the notice was reported again: %v", resp.Diagnostics)
+	}
+}
+
+func TestDeprecationNoticeFromWarning(t *testing.T) {
+	notices := &deprecationNotices{seen: map[string]bool{}}
+	header := http.Header{}
+	header.Add("Warning", `299 - "Use /api/v2/users instead."`)
+	header.Add("Warning", `199 - "Miscellaneous warning"`)
+	notices.record(http.MethodPost, "/SecretServer/api/v1/users", header)
+	notices.record(http.MethodGet, "/SecretServer/api/v1/users", http.Header{})
+
+	pending := notices.take()
+	if len(pending) != 1 {
+		t.Fatalf("got %d notices, want 1", len(pending))
+	}
+	if want := "POST users, which this provider calls, is deprecated. Use /api/v2/users instead."; !strings.HasPrefix(pending[0], "Secret Server reports that "+want) {
+		t.Errorf("notice = %q", pending[0])
+	}
+	if pending := notices.take(); len(pending) != 0 {
+		t.Errorf("notices were taken twice: %v", pending)
+	}
+}
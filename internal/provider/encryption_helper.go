@@ -7,7 +7,6 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
-	"io"
 	"log"
 	"os"
 
@@ -27,8 +26,32 @@ func fileExists(filename string) bool {
 	return err == nil
 }
 
-// EncryptFile encrypts the file content
-func EncryptFile(passphrase, stateFile string) error {
+// passphraseCipher returns AES-256-GCM keyed with PBKDF2-HMAC-SHA256 of
+// passphrase and salt. The cipher generates the nonce and prepends it to the
+// ciphertext. fips restricts it to the algorithms of the Go FIPS 140-3 module.
+func passphraseCipher(passphrase string, salt []byte, fips bool) (cipher.AEAD, error) {
+	if fips {
+		return fipsCipher(passphrase, salt)
+	}
+
+	// Derive the encryption key using PBKDF2
+	key := pbkdf2.Key([]byte(passphrase), salt, iterations, keyLength, sha256.New)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher block: %v", err)
+	}
+
+	gcm, err := cipher.NewGCMWithRandomNonce(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %v", err)
+	}
+	return gcm, nil
+}
+
+// EncryptFile encrypts the file content. fips restricts the encryption to the
+// algorithms of the Go FIPS 140-3 module.
+func EncryptFile(passphrase, stateFile string, fips bool) error {
 	if !fileExists(stateFile) {
 		return nil
 	}
@@ -45,27 +68,12 @@ func EncryptFile(passphrase, stateFile string) error {
 		return fmt.Errorf("failed to generate salt: %v", err)
 	}
 
-	// Derive the encryption key using PBKDF2
-	key := pbkdf2.Key([]byte(passphrase), salt, iterations, keyLength, sha256.New)
-
-	// Encrypt the data
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return fmt.Errorf("failed to create cipher block: %v", err)
-	}
-
-	gcm, err := cipher.NewGCM(block)
+	gcm, err := passphraseCipher(passphrase, salt, fips)
 	if err != nil {
-		return fmt.Errorf("failed to create GCM: %v", err)
+		return err
 	}
-
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return fmt.Errorf("failed to generate nonce: %v", err)
-	}
-
-	// Encrypt the data using GCM
-	encryptedData := gcm.Seal(nonce, nonce, data, nil)
+	// The nonce is generated by the cipher and prepended to its output
+	encryptedData := gcm.Seal(nil, nil, data, nil)
 
 	// Prepend the salt to the encrypted data
 	finalData := append(salt, encryptedData...)
@@ -80,8 +88,9 @@ func EncryptFile(passphrase, stateFile string) error {
 	return nil
 }
 
-// DecryptFile decrypts the content of the state file
-func DecryptFile(passphrase, stateFile string) error {
+// DecryptFile decrypts the content of the state file. fips restricts the
+// decryption to the algorithms of the Go FIPS 140-3 module.
+func DecryptFile(passphrase, stateFile string, fips bool) error {
 	if !fileExists(stateFile) {
 		return nil
 	}
@@ -102,25 +111,12 @@ func DecryptFile(passphrase, stateFile string) error {
 	salt := encryptedData[:saltLength]
 	encryptedContent := encryptedData[saltLength:]
 
-	// Derive the decryption key using PBKDF2
-	key := pbkdf2.Key([]byte(passphrase), salt, iterations, keyLength, sha256.New)
-
-	// Decrypt the data
-	block, err := aes.NewCipher(key)
+	gcm, err := passphraseCipher(passphrase, salt, fips)
 	if err != nil {
-		return fmt.Errorf("failed to create cipher block: %v", err)
+		return err
 	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return fmt.Errorf("failed to create GCM: %v", err)
-	}
-
-	nonceSize := gcm.NonceSize()
-	nonce, ciphertext := encryptedContent[:nonceSize], encryptedContent[nonceSize:]
-
-	// Decrypt the data using GCM
-	decryptedData, err := gcm.Open(nil, nonce, ciphertext, nil)
+	// The cipher reads the nonce prepended to the ciphertext
+	decryptedData, err := gcm.Open(nil, nil, encryptedContent, nil)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt data: %v", err)
 	}
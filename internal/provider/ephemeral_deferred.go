@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// deferUnknownOpen handles an Open whose configuration is not known yet, e.g. a
+// secret ID computed by a resource created in the same apply, and reports
+// whether it did. Terraform is asked to defer the open when it supports
+// deferred actions; otherwise the result is unknown until the configuration is
+// known. Either way, nothing is read from or created on Secret Server.
+func deferUnknownOpen(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) bool {
+	if req.Config.Raw.IsFullyKnown() {
+		return false
+	}
+
+	resp.Result.Raw = tftypes.NewValue(req.Config.Schema.Type().TerraformType(ctx), tftypes.UnknownValue)
+	if req.ClientCapabilities.DeferralAllowed {
+		tflog.Debug(ctx, "Configuration is not known yet, deferring open")
+		resp.Deferred = &ephemeral.Deferred{Reason: ephemeral.DeferredReasonEphemeralResourceConfigUnknown}
+		return true
+	}
+	tflog.Debug(ctx, "Configuration is not known yet, opening with an unknown result")
+	return true
+}
@@ -0,0 +1,276 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &TssAPIAccountEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &TssAPIAccountEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &TssAPIAccountEphemeralResource{}
+)
+
+// apiAccountPrivateKey is the private data key holding the ID of the account to delete on Close
+const apiAccountPrivateKey = "tss_api_account"
+
+// NewTssAPIAccountEphemeralResource is a helper function to simplify the provider implementation.
+func NewTssAPIAccountEphemeralResource() ephemeral.EphemeralResource {
+	return &TssAPIAccountEphemeralResource{}
+}
+
+// TssAPIAccountEphemeralResource creates a temporary application account with
+// the given roles when opened, and deletes it when closed
+type TssAPIAccountEphemeralResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// TssAPIAccountEphemeralResourceModel defines the model of the API account ephemeral resource
+type TssAPIAccountEphemeralResourceModel struct {
+	RoleIDs     types.List   `tfsdk:"role_ids"`
+	GroupIDs    types.List   `tfsdk:"group_ids"`
+	NamePrefix  types.String `tfsdk:"name_prefix"`
+	UserID      types.Int64  `tfsdk:"user_id"`
+	Username    types.String `tfsdk:"username"`
+	Password    types.String `tfsdk:"password"`
+	AccessToken types.String `tfsdk:"access_token"`
+	ExpiresAt   types.String `tfsdk:"expires_at"`
+}
+
+// apiAccountPrivateData is the private data of an open API account
+type apiAccountPrivateData struct {
+	UserID int `json:"user_id"`
+}
+
+func (r *TssAPIAccountEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = "dept-tss_api_account"
+}
+
+func (r *TssAPIAccountEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Creates an application account with the given roles for the duration of the Terraform run, " +
+			"and deletes it when Terraform closes the resource, so that tools run during apply get short-lived, " +
+			"least-privilege API credentials. Not supported on the Delinea Platform.",
+		Attributes: map[string]schema.Attribute{
+			"role_ids": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.Int64Type,
+				Description: "The IDs of the roles assigned to the account. Keep them to the permissions the tools need.",
+			},
+			"group_ids": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.Int64Type,
+				Description: "The IDs of the groups the account is added to, granting it their folder and secret permissions.",
+			},
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "The prefix of the generated username. Defaults to \"tf-temp-\".",
+			},
+			"user_id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The ID of the account.",
+			},
+			"username": schema.StringAttribute{
+				Computed:    true,
+				Description: "The generated username of the account.",
+			},
+			"password": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated password of the account.",
+			},
+			"access_token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "An access token issued to the account.",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "When the access token expires, in RFC 3339 format.",
+			},
+		},
+	}
+}
+
+func (r *TssAPIAccountEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Invalid Provider Data", "Expected provider data of type *TssProviderData")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+func (r *TssAPIAccountEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	ctx = r.logging.context(ctx)
+	if deferUnknownOpen(ctx, req, resp) {
+		return
+	}
+
+	var data TssAPIAccountEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.api == nil {
+		resp.Diagnostics.AddError("Provider not configured", "Cannot create an API account because the provider is not configured.")
+		return
+	}
+	if r.api.platform {
+		resp.Diagnostics.AddError("Unsupported Server", "Temporary API accounts cannot be created on the Delinea Platform.")
+		return
+	}
+
+	var roleIDs, groupIDs []int64
+	resp.Diagnostics.Append(data.RoleIDs.ElementsAs(ctx, &roleIDs, false)...)
+	if !data.GroupIDs.IsNull() {
+		resp.Diagnostics.Append(data.GroupIDs.ElementsAs(ctx, &groupIDs, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prefix := "tf-temp-"
+	if !data.NamePrefix.IsNull() {
+		prefix = data.NamePrefix.ValueString()
+	}
+	username, err := randomString(4)
+	if err != nil {
+		resp.Diagnostics.AddError("API Account Error", fmt.Sprintf("Failed to generate a username: %s", err))
+		return
+	}
+	username = prefix + username
+	password, err := randomString(24)
+	if err != nil {
+		resp.Diagnostics.AddError("API Account Error", fmt.Sprintf("Failed to generate a password: %s", err))
+		return
+	}
+
+	userID, err := r.api.createAPIAccount(ctx, username, password)
+	if err != nil {
+		resp.Diagnostics.AddError("API Account Error", fmt.Sprintf("Failed to create API account %s: %s", username, err))
+		return
+	}
+
+	token, lifetime, err := r.api.setUpAPIAccount(ctx, userID, username, password, roleIDs, groupIDs)
+	if err != nil {
+		// Don't leave a half configured account behind
+		if deleteErr := r.api.deleteUser(ctx, userID); deleteErr != nil {
+			tflog.Warn(ctx, "Failed to delete the API account after a failed setup", map[string]interface{}{
+				"user_id": userID,
+				"error":   deleteErr.Error(),
+			})
+		}
+		resp.Diagnostics.AddError("API Account Error", fmt.Sprintf("Failed to set up API account %s: %s", username, err))
+		return
+	}
+
+	data.UserID = types.Int64Value(int64(userID))
+	data.Username = types.StringValue(username)
+	data.Password = types.StringValue(password)
+	data.AccessToken = types.StringValue(token)
+	data.ExpiresAt = types.StringValue(time.Now().Add(lifetime).UTC().Format(time.RFC3339))
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+
+	privateData, _ := json.Marshal(apiAccountPrivateData{UserID: userID})
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, apiAccountPrivateKey, privateData)...)
+}
+
+func (r *TssAPIAccountEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	ctx = r.logging.context(ctx)
+
+	privateBytes, diags := req.Private.GetKey(ctx, apiAccountPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if privateBytes == nil || r.api == nil {
+		return
+	}
+
+	var privateData apiAccountPrivateData
+	if err := json.Unmarshal(privateBytes, &privateData); err != nil {
+		resp.Diagnostics.AddError("Invalid Private Data", "Failed to unmarshal private data.")
+		return
+	}
+
+	if err := r.api.deleteUser(ctx, privateData.UserID); err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("API Account Error",
+			fmt.Sprintf("Failed to delete API account %d; delete it in Secret Server: %s", privateData.UserID, err))
+	}
+}
+
+// createAPIAccount creates an enabled local application account, returning its ID
+func (c *apiClient) createAPIAccount(ctx context.Context, username, password string) (int, error) {
+	body := map[string]interface{}{
+		"userName":             username,
+		"displayName":          username,
+		"password":             password,
+		"isApplicationAccount": true,
+		"enabled":              true,
+	}
+	var created struct {
+		ID int
+	}
+	if err := c.do(ctx, "POST", "users", body, &created); err != nil {
+		return 0, err
+	}
+
+	tflog.Info(ctx, "Created API account", map[string]interface{}{
+		"user_id":  created.ID,
+		"username": username,
+	})
+	return created.ID, nil
+}
+
+// setUpAPIAccount assigns the roles and groups of an API account and issues it an
+// access token, returned with its lifetime
+func (c *apiClient) setUpAPIAccount(ctx context.Context, userID int, username, password string, roleIDs, groupIDs []int64) (string, time.Duration, error) {
+	if err := c.do(ctx, "POST", fmt.Sprintf("users/%d/roles", userID), map[string]interface{}{"roleIds": roleIDs}, nil); err != nil {
+		return "", 0, fmt.Errorf("failed to assign roles: %w", err)
+	}
+	for _, groupID := range groupIDs {
+		if err := c.do(ctx, "POST", fmt.Sprintf("groups/%d/users", groupID), map[string]interface{}{"userId": userID}, nil); err != nil {
+			return "", 0, fmt.Errorf("failed to add the account to group %d: %w", groupID, err)
+		}
+	}
+
+	values := url.Values{
+		"username":   {username},
+		"password":   {password},
+		"grant_type": {"password"},
+	}
+	return c.grant(ctx, c.baseURL()+"/"+tokenPathURI, values)
+}
+
+// deleteUser deletes a user
+func (c *apiClient) deleteUser(ctx context.Context, userID int) error {
+	tflog.Info(ctx, "Deleting user", map[string]interface{}{
+		"user_id": userID,
+	})
+	return c.do(ctx, "DELETE", fmt.Sprintf("users/%d", userID), nil, nil)
+}
+
+// randomString returns n random bytes, hex encoded
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
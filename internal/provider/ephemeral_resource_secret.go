@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"strconv"
 	"time"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // TssSecretEphemeralResource is a helper function to simplify the provider implementation.
@@ -21,7 +21,10 @@ func NewTssSecretEphemeralResource() ephemeral.EphemeralResource {
 
 // TssSecretResource defines the resource implementation
 type TssSecretEphemeralResource struct {
-	clientConfig *server.Configuration // Store the provider configuration
+	client   *server.Server // Store the provider configuration
+	api      *apiClient
+	defaults callOptions
+	logging  loggingConfig
 }
 
 func (r *TssSecretEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
@@ -35,11 +38,21 @@ type TssSecretEphemeralResourceModel struct {
 	SecretValue types.String `tfsdk:"value"`
 }
 
-// Define private data structure (optional)
+// TssSecretPrivateData holds the inputs Renew fetches the secret with. It never
+// holds the value, since private data is persisted by Terraform.
 type TssSecretPrivateData struct {
-	SecretID    string `json:"id"`
-	Field       string `json:"field"`
-	SecretValue string `json:"value"`
+	SecretID string `json:"id"`
+	Field    string `json:"field"`
+}
+
+// secretPrivateData returns the private data of data, which holds the ID and
+// field of the secret but not its value
+func secretPrivateData(data TssSecretEphemeralResourceModel) []byte {
+	privateData, _ := json.Marshal(TssSecretPrivateData{
+		SecretID: data.SecretID.ValueString(),
+		Field:    data.Field.ValueString(),
+	})
+	return privateData
 }
 
 func (r *TssSecretEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
@@ -65,19 +78,26 @@ func (r *TssSecretEphemeralResource) Configure(ctx context.Context, req ephemera
 	if req.ProviderData == nil {
 		return
 	}
-	log.Printf("DEBUG: ProviderData received in Configure")
-	client, ok := req.ProviderData.(*server.Configuration)
+	tflog.Debug(ctx, "Provider data received, attempting to configure")
+	providerData, ok := req.ProviderData.(*TssProviderData)
 	if !ok {
-		resp.Diagnostics.AddError("Invalid Provider Data", "Expected provider data of type *server.Configuration")
+		resp.Diagnostics.AddError("Invalid Provider Data", "Expected provider data of type *TssProviderData")
 		return
 	}
 
-	log.Printf("DEBUG: Successfully retrieved provider configuration")
+	tflog.Debug(ctx, "Successfully retrieved provider configuration")
 
-	r.clientConfig = client
+	r.client = providerData.Server
+	r.api = providerData.API
+	r.defaults = providerData.Defaults
+	r.logging = providerData.Logging
 }
 
 func (r *TssSecretEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	ctx = r.logging.context(ctx)
+	if deferUnknownOpen(ctx, req, resp) {
+		return
+	}
 	// Create a model to hold the input configuration
 	var data TssSecretEphemeralResourceModel
 
@@ -87,7 +107,7 @@ func (r *TssSecretEphemeralResource) Open(ctx context.Context, req ephemeral.Ope
 		return
 	}
 
-	if r.clientConfig == nil {
+	if r.client == nil {
 		resp.Diagnostics.AddError("Provider not configured", "Cannot fetch secrets because the provider is not configured.")
 		return
 	}
@@ -98,13 +118,6 @@ func (r *TssSecretEphemeralResource) Open(ctx context.Context, req ephemeral.Ope
 		return
 	}
 
-	// Initialize your Delinea API client (e.g., using the secret_id and field)
-	client, err := server.New(*r.clientConfig)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Creation Error", err.Error())
-		return
-	}
-
 	// Convert SecretID to integer
 	secretID, err := strconv.Atoi(data.SecretID.ValueString())
 	if err != nil {
@@ -112,16 +125,27 @@ func (r *TssSecretEphemeralResource) Open(ctx context.Context, req ephemeral.Ope
 		return
 	}
 
-	log.Printf("[DEBUG] getting secret with id %d", secretID)
+	tflog.Debug(ctx, "Fetching secret", map[string]interface{}{
+		"secret_id": secretID,
+	})
 
 	// Fetch the secret from the server using Delinea SDK
-	secret, err := client.Secret(secretID)
+	unlock, err := lockSecret(ctx, secretID)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Lock Error", fmt.Sprintf("Gave up waiting for another operation on secret %d: %s", secretID, err))
+		return
+	}
+	secret, err := newSDKClient(ctx, r.client, r.api, r.defaults.forOperation("read")).Secret(secretID)
+	unlock()
 	if err != nil {
 		resp.Diagnostics.AddError("Secret Fetch Error", err.Error())
 		return
 	}
 
-	log.Printf("[DEBUG] using '%s' field of secret with id %d", data.Field.ValueString(), secretID)
+	tflog.Debug(ctx, "Using field of secret", map[string]interface{}{
+		"secret_id": secretID,
+		"field":     data.Field.ValueString(),
+	})
 
 	// Extract the requested field value (assuming Field() method is available)
 	fieldValue, ok := secret.Field(data.Field.ValueString())
@@ -140,15 +164,11 @@ func (r *TssSecretEphemeralResource) Open(ctx context.Context, req ephemeral.Ope
 	resp.RenewAt = time.Now().Add(5 * time.Minute)
 
 	// Store private data for use during renewal
-	privateData, _ := json.Marshal(TssSecretPrivateData{
-		SecretID:    data.SecretID.ValueString(),
-		Field:       data.Field.ValueString(),
-		SecretValue: data.SecretValue.ValueString(),
-	})
-	resp.Private.SetKey(ctx, "tss_secret_data", privateData)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, "tss_secret_data", secretPrivateData(data))...)
 }
 
 func (r *TssSecretEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	ctx = r.logging.context(ctx)
 	// Retrieve the private data that was stored during Open
 	privateBytes, _ := req.Private.GetKey(ctx, "tss_secret_data")
 	if privateBytes == nil {
@@ -169,13 +189,6 @@ func (r *TssSecretEphemeralResource) Renew(ctx context.Context, req ephemeral.Re
 		return
 	}
 
-	// Initialize your Delinea API client
-	client, err := server.New(*r.clientConfig)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Creation Error", err.Error())
-		return
-	}
-
 	// Convert SecretID to integer
 	secretID, err := strconv.Atoi(privateData.SecretID)
 	if err != nil {
@@ -183,34 +196,44 @@ func (r *TssSecretEphemeralResource) Renew(ctx context.Context, req ephemeral.Re
 		return
 	}
 
-	log.Printf("[DEBUG] getting secret with id %d to renew data", secretID)
+	tflog.Debug(ctx, "Renewing secret", map[string]interface{}{
+		"secret_id": secretID,
+	})
 
 	// Fetch the secret from the server
-	secret, err := client.Secret(secretID)
+	unlock, err := lockSecret(ctx, secretID)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Lock Error", fmt.Sprintf("Gave up waiting for another operation on secret %d: %s", secretID, err))
+		return
+	}
+	secret, err := newSDKClient(ctx, r.client, r.api, r.defaults.forOperation("read")).Secret(secretID)
+	unlock()
 	if err != nil {
 		resp.Diagnostics.AddError("Secret Fetch Error", err.Error())
 		return
 	}
 
-	log.Printf("[DEBUG] using '%s' field of secret with id %d to renew data", privateData.Field, secretID)
+	tflog.Debug(ctx, "Using field of secret to renew data", map[string]interface{}{
+		"secret_id": secretID,
+		"field":     privateData.Field,
+	})
 
-	// Extract the requested field value
-	fieldValue, ok := secret.Field(privateData.Field)
-	if !ok {
+	// Check the field is still readable. The value is not kept, since Renew
+	// cannot change the result.
+	if _, ok := secret.Field(privateData.Field); !ok {
 		resp.Diagnostics.AddError("Field Not Found", fmt.Sprintf("Field %s not found in the secret", privateData.Field))
 		return
 	}
 
-	// Update the private data with the new secret value
-	privateData.SecretValue = fieldValue
-
-	// Store the updated private data for the next renewal
-	privateDataBytes, _ := json.Marshal(privateData)
-	resp.Private.SetKey(ctx, "tss_secret_data", privateDataBytes)
+	// Keep the inputs for the next renewal
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, "tss_secret_data", privateBytes)...)
 
 	// Set the renewal time (e.g., 5 minutes from now)
 	resp.RenewAt = time.Now().Add(5 * time.Minute)
 }
 
 func (r *TssSecretEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	ctx = r.logging.context(ctx)
+	tflog.Debug(ctx, "Closing TssSecretEphemeralResource")
+	// No cleanup needed for this resource
 }
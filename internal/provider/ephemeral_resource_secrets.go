@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/DelineaXPM/tss-sdk-go/v2/server"
@@ -21,15 +22,19 @@ func NewTssSecretsEphemeralResource() ephemeral.EphemeralResource {
 // TssSecretsEphemeralResource implements the ephemeral resource for fetching multiple secrets.
 // Ephemeral resources are used for sensitive data that should not be persisted in state.
 type TssSecretsEphemeralResource struct {
-	client *server.Server // Store the provider configuration
+	client   *server.Server // Store the provider configuration
+	api      *apiClient
+	defaults callOptions
+	logging  loggingConfig
 }
 
 // TssSecretsEphemeralResourceModel represents the data model for the ephemeral resource.
 // This structure maps directly to the Terraform schema.
 type TssSecretsEphemeralResourceModel struct {
-	IDs     []types.Int64 `tfsdk:"ids"`
-	Field   types.String  `tfsdk:"field"`
-	Secrets []SecretModel `tfsdk:"secrets"`
+	IDs         []types.Int64 `tfsdk:"ids"`
+	Field       types.String  `tfsdk:"field"`
+	Secrets     []SecretModel `tfsdk:"secrets"`
+	SecretsByID types.Map     `tfsdk:"secrets_by_id"`
 }
 
 // SecretModel represents a single secret's extracted data
@@ -38,13 +43,21 @@ type SecretModel struct {
 	Value types.String `tfsdk:"value"`
 }
 
-// Define private data structure (optional)
-// TssSecretsPrivateData stores data between resource lifecycle operations.
-// This is used during renewal to avoid re-reading configuration.
+// TssSecretsPrivateData holds the inputs Renew fetches the secrets with. It
+// never holds their values, since private data is persisted by Terraform.
 type TssSecretsPrivateData struct {
-	IDs     []types.Int64 `tfsdk:"ids"`
-	Field   string        `json:"field"`
-	Secrets []SecretModel `tfsdk:"secrets"`
+	IDs   []int  `json:"ids"`
+	Field string `json:"field"`
+}
+
+// secretsPrivateData returns the private data of data, which holds the IDs and
+// field of the secrets but not their values
+func secretsPrivateData(data TssSecretsEphemeralResourceModel) []byte {
+	privateData, _ := json.Marshal(TssSecretsPrivateData{
+		IDs:   uniqueSecretIDs(data.IDs),
+		Field: data.Field.ValueString(),
+	})
+	return privateData
 }
 
 func (r *TssSecretsEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
@@ -62,7 +75,7 @@ func (r *TssSecretsEphemeralResource) Schema(ctx context.Context, req ephemeral.
 			"ids": schema.ListAttribute{
 				ElementType: types.Int64Type,
 				Required:    true,
-				Description: "A list of IDs of the secrets",
+				Description: "A list of IDs of the secrets. Repeated IDs are fetched once",
 			},
 			"field": schema.StringAttribute{
 				Required:    true,
@@ -70,7 +83,7 @@ func (r *TssSecretsEphemeralResource) Schema(ctx context.Context, req ephemeral.
 			},
 			"secrets": schema.ListNestedAttribute{
 				Computed:    true,
-				Description: "A list of secrets with their field values",
+				Description: "A list of secrets with their field values, in the order of their first occurrence in ids",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"id": schema.Int64Attribute{
@@ -84,6 +97,11 @@ func (r *TssSecretsEphemeralResource) Schema(ctx context.Context, req ephemeral.
 					},
 				},
 			},
+			"secrets_by_id": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The field values of the secrets, keyed by secret ID",
+			},
 		},
 	}
 }
@@ -96,22 +114,29 @@ func (r *TssSecretsEphemeralResource) Configure(ctx context.Context, req ephemer
 		return
 	}
 
-	client, ok := req.ProviderData.(*server.Server)
+	providerData, ok := req.ProviderData.(*TssProviderData)
 	if !ok {
 		tflog.Error(ctx, "Invalid provider data type", map[string]interface{}{
-			"expected": "*server.Server",
+			"expected": "*TssProviderData",
 			"actual":   fmt.Sprintf("%T", req.ProviderData),
 		})
-		resp.Diagnostics.AddError("Invalid Provider Data", "Expected provider data of type *server.Configuration")
+		resp.Diagnostics.AddError("Invalid Provider Data", "Expected provider data of type *TssProviderData")
 		return
 	}
 
 	tflog.Debug(ctx, "Successfully retrieved provider configuration")
 
-	r.client = client
+	r.client = providerData.Server
+	r.api = providerData.API
+	r.defaults = providerData.Defaults
+	r.logging = providerData.Logging
 }
 
 func (r *TssSecretsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	ctx = r.logging.context(ctx)
+	if deferUnknownOpen(ctx, req, resp) {
+		return
+	}
 	tflog.Debug(ctx, "Opening TssSecretsEphemeralResource")
 
 	// Create a model to hold the input configuration
@@ -149,16 +174,21 @@ func (r *TssSecretsEphemeralResource) Open(ctx context.Context, req ephemeral.Op
 
 	// Fetch secrets
 	var results []SecretModel
+	byID := map[string]string{}
 
-	for _, id := range data.IDs {
-		secretID := int(id.ValueInt64())
-
+	for _, secretID := range uniqueSecretIDs(data.IDs) {
 		tflog.Debug(ctx, "Fetching secret", map[string]interface{}{
 			"secret_id": secretID,
 		})
 
 		// Fetch the secret
-		secret, err := r.client.Secret(secretID)
+		unlock, err := lockSecret(ctx, secretID)
+		if err != nil {
+			resp.Diagnostics.AddError("Secret Lock Error", fmt.Sprintf("Gave up waiting for another operation on secret %d: %s", secretID, err))
+			return
+		}
+		secret, err := newSDKClient(ctx, r.client, r.api, r.defaults.forOperation("read")).Secret(secretID)
+		unlock()
 		if err != nil {
 			tflog.Warn(ctx, "Failed to fetch secret", map[string]interface{}{
 				"secret_id": secretID,
@@ -197,15 +227,19 @@ func (r *TssSecretsEphemeralResource) Open(ctx context.Context, req ephemeral.Op
 			ID:    types.Int64Value(int64(secretID)),
 			Value: types.StringValue(fieldValue),
 		})
-
-		tflog.Info(ctx, "Successfully fetched secrets", map[string]interface{}{
-			"requested": len(data.IDs),
-			"retrieved": len(results),
-		})
+		byID[strconv.Itoa(secretID)] = fieldValue
 	}
 
+	tflog.Info(ctx, "Successfully fetched secrets", map[string]interface{}{
+		"requested": len(data.IDs),
+		"retrieved": len(results),
+	})
+
 	// Set the secret value in the result
 	data.Secrets = results
+	secretsByID, diags := types.MapValueFrom(ctx, types.StringType, byID)
+	resp.Diagnostics.Append(diags...)
+	data.SecretsByID = secretsByID
 
 	// Save the data into the ephemeral result state
 	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
@@ -217,16 +251,12 @@ func (r *TssSecretsEphemeralResource) Open(ctx context.Context, req ephemeral.Op
 	})
 
 	// Store private data for use during renewal
-	privateData, _ := json.Marshal(TssSecretsPrivateData{
-		IDs:     data.IDs,
-		Field:   data.Field.ValueString(),
-		Secrets: data.Secrets,
-	})
-	resp.Private.SetKey(ctx, "tss_secrets_data", privateData)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, "tss_secrets_data", secretsPrivateData(data))...)
 	tflog.Trace(ctx, "Stored private data for renewal")
 }
 
 func (r *TssSecretsEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	ctx = r.logging.context(ctx)
 	tflog.Debug(ctx, "Renewing TssSecretsEphemeralResource")
 
 	// Retrieve the private data that was stored during Open
@@ -262,18 +292,23 @@ func (r *TssSecretsEphemeralResource) Renew(ctx context.Context, req ephemeral.R
 		"field": privateData.Field,
 	})
 
-	// Fetch secrets
-	var results []SecretModel
-
-	for _, id := range privateData.IDs {
-		secretID := int(id.ValueInt64())
+	// Fetch the secrets again to check they are still readable. Their values
+	// are not kept, since Renew cannot change the result.
+	renewed := 0
 
+	for _, secretID := range privateData.IDs {
 		tflog.Debug(ctx, "Renewing secret", map[string]interface{}{
 			"secret_id": secretID,
 		})
 
 		// Fetch the secret
-		secret, err := r.client.Secret(secretID)
+		unlock, err := lockSecret(ctx, secretID)
+		if err != nil {
+			resp.Diagnostics.AddError("Secret Lock Error", fmt.Sprintf("Gave up waiting for another operation on secret %d: %s", secretID, err))
+			return
+		}
+		secret, err := newSDKClient(ctx, r.client, r.api, r.defaults.forOperation("read")).Secret(secretID)
+		unlock()
 		if err != nil {
 			tflog.Warn(ctx, "Failed to fetch secret during renewal", map[string]interface{}{
 				"secret_id": secretID,
@@ -288,9 +323,7 @@ func (r *TssSecretsEphemeralResource) Renew(ctx context.Context, req ephemeral.R
 			"field":     privateData.Field,
 		})
 
-		// Extract the requested field value (assuming Field() method is available)
-		fieldValue, ok := secret.Field(privateData.Field)
-		if !ok {
+		if _, ok := secret.Field(privateData.Field); !ok {
 			tflog.Error(ctx, "Field not found during renewal", map[string]interface{}{
 				"secret_id": secretID,
 				"field":     privateData.Field,
@@ -303,28 +336,16 @@ func (r *TssSecretsEphemeralResource) Renew(ctx context.Context, req ephemeral.R
 			"secret_id": secretID,
 			"field":     privateData.Field,
 		})
-
-		// Save the secret value in the state
-		results = append(results, struct {
-			ID    types.Int64  `tfsdk:"id"`
-			Value types.String `tfsdk:"value"`
-		}{
-			ID:    types.Int64Value(int64(secretID)),
-			Value: types.StringValue(fieldValue),
-		})
+		renewed++
 	}
 
 	tflog.Info(ctx, "Successfully renewed secrets", map[string]interface{}{
 		"requested": len(privateData.IDs),
-		"retrieved": len(results),
+		"retrieved": renewed,
 	})
 
-	// Update the private data with the new secret value
-	privateData.Secrets = results
-
-	// Store the updated private data for the next renewal
-	privateDataBytes, _ := json.Marshal(privateData)
-	resp.Private.SetKey(ctx, "tss_secrets_data", privateDataBytes)
+	// Keep the inputs for the next renewal
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, "tss_secrets_data", privateBytes)...)
 
 	// Set the renewal time (e.g., 5 minutes from now)
 	resp.RenewAt = time.Now().Add(5 * time.Minute)
@@ -334,6 +355,26 @@ func (r *TssSecretsEphemeralResource) Renew(ctx context.Context, req ephemeral.R
 }
 
 func (r *TssSecretsEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	ctx = r.logging.context(ctx)
 	tflog.Debug(ctx, "Closing TssSecretsEphemeralResource")
 	// No cleanup needed for this resource
 }
+
+// uniqueSecretIDs returns the known IDs of ids without repeats, in the order of
+// their first occurrence
+func uniqueSecretIDs(ids []types.Int64) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id.IsNull() || id.IsUnknown() {
+			continue
+		}
+		secretID := int(id.ValueInt64())
+		if seen[secretID] {
+			continue
+		}
+		seen[secretID] = true
+		unique = append(unique, secretID)
+	}
+	return unique
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSecretsPrivateDataRoundTrip(t *testing.T) {
+	const value = "hunter2-plaintext"
+	data := TssSecretsEphemeralResourceModel{
+		IDs:   []types.Int64{types.Int64Value(3), types.Int64Value(1), types.Int64Value(3), types.Int64Null()},
+		Field: types.StringValue("password"),
+		Secrets: []SecretModel{
+			{ID: types.Int64Value(3), Value: types.StringValue(value)},
+			{ID: types.Int64Value(1), Value: types.StringValue(value)},
+		},
+		SecretsByID: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"3": types.StringValue(value),
+			"1": types.StringValue(value),
+		}),
+	}
+
+	privateData := secretsPrivateData(data)
+	if strings.Contains(string(privateData), value) {
+		t.Fatalf("private data holds a secret value: %s", privateData)
+	}
+
+	var renewed TssSecretsPrivateData
+	if err := json.Unmarshal(privateData, &renewed); err != nil {
+		t.Fatal(err)
+	}
+	want := TssSecretsPrivateData{IDs: []int{3, 1}, Field: "password"}
+	if !reflect.DeepEqual(renewed, want) {
+		t.Errorf("private data %s was read back as %+v, want %+v", privateData, renewed, want)
+	}
+}
+
+func TestSecretPrivateDataRoundTrip(t *testing.T) {
+	const value = "hunter2-plaintext"
+	data := TssSecretEphemeralResourceModel{
+		SecretID:    types.StringValue("42"),
+		Field:       types.StringValue("password"),
+		SecretValue: types.StringValue(value),
+	}
+
+	privateData := secretPrivateData(data)
+	if strings.Contains(string(privateData), value) {
+		t.Fatalf("private data holds the secret value: %s", privateData)
+	}
+
+	var renewed TssSecretPrivateData
+	if err := json.Unmarshal(privateData, &renewed); err != nil {
+		t.Fatal(err)
+	}
+	want := TssSecretPrivateData{SecretID: "42", Field: "password"}
+	if renewed != want {
+		t.Errorf("private data %s was read back as %+v, want %+v", privateData, renewed, want)
+	}
+}
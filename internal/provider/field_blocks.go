@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// SecretFieldBlock is a field block, the set based alternative to the fields
+// blocks. Set elements are compared by their content rather than their
+// position, so neither the configuration nor the server can reorder them.
+type SecretFieldBlock struct {
+	Name  types.String `tfsdk:"name"`
+	Value types.String `tfsdk:"value"`
+}
+
+// fieldBlock is the schema of the field blocks. Computed attributes would make
+// the elements unknown, and so unmatchable, until apply, so there are none.
+func fieldBlock() schema.SetNestedBlock {
+	return schema.SetNestedBlock{
+		Description: "A field of the secret, used instead of fields blocks. The blocks are unordered and matched by name, " +
+			"so reordering them, or Secret Server returning the fields in another order, plans no change.",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Required:    true,
+					Description: "The slug or name of the field.",
+				},
+				"value": schema.StringAttribute{
+					Optional:  true,
+					Sensitive: true,
+					Description: "The value of the field. When not set, the field keeps its current value; " +
+						"password fields created without a value get a generated password.",
+				},
+			},
+		},
+	}
+}
+
+// fieldBlocksValue returns the field blocks of a secret read back from Secret
+// Server: blocks with a value get the field's current value, and blocks without
+// one stay without, as configured
+func fieldBlocksValue(fields []SecretField, blocks []SecretFieldBlock) []SecretFieldBlock {
+	if len(blocks) == 0 {
+		return blocks
+	}
+	values := make([]SecretFieldBlock, len(blocks))
+	for i, block := range blocks {
+		values[i] = block
+		if block.Value.IsNull() {
+			continue
+		}
+		for _, field := range fields {
+			if strings.EqualFold(field.Slug.ValueString(), block.Name.ValueString()) ||
+				strings.EqualFold(field.FieldName.ValueString(), block.Name.ValueString()) {
+				values[i].Value = field.ItemValue
+				break
+			}
+		}
+	}
+	return values
+}
+
+// keepUnsetFieldBlockValues gives the fields written from field blocks without a
+// value their current value, so an update leaves them as they are
+func (r *TssSecretResource) keepUnsetFieldBlockValues(ctx context.Context, client sdkClient, plan, state *SecretResourceState) (*SecretResourceState, error) {
+	var current map[string]string
+	withValues := *plan
+	withValues.Fields = append([]SecretField(nil), plan.Fields...)
+
+	for _, block := range plan.Field {
+		if !block.Value.IsNull() {
+			continue
+		}
+		if current == nil {
+			var err error
+			if current, err = currentFieldValues(client, state.ID.ValueString()); err != nil {
+				return nil, err
+			}
+		}
+		for i, field := range withValues.Fields {
+			if strings.EqualFold(field.FieldName.ValueString(), block.Name.ValueString()) {
+				tflog.Trace(ctx, "Keeping the current value of field", map[string]interface{}{
+					"field": block.Name.ValueString(),
+				})
+				withValues.Fields[i].ItemValue = types.StringValue(current[strings.ToLower(block.Name.ValueString())])
+			}
+		}
+	}
+	return &withValues, nil
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+var (
+	_ resource.ResourceWithValidateConfig = &TssSecretResource{}
+	_ resource.ResourceWithUpgradeState   = &TssSecretResource{}
+)
+
+// secretSchemaVersion is the schema version of the secret resource. Version 1
+// added field_values.
+const secretSchemaVersion = 1
+
+// fieldValuesAttribute is the schema of field_values, the field values keyed
+// by slug. Unlike the fields blocks, a map has no order for the server's field
+// order to disagree with.
+func fieldValuesAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType: types.StringType,
+		Optional:    true,
+		Computed:    true,
+		Sensitive:   true,
+		Description: "The values of the secret's fields keyed by field slug, e.g. { username = \"svc\", password = \"...\" }, used instead of fields blocks. " +
+			"Only the fields given are written; values are written as given, so leave out password fields to keep their current value. " +
+			"When not set, it holds the value of every field other than file fields.",
+		PlanModifiers: []planmodifier.Map{
+			fieldValuesPlanModifier{},
+		},
+	}
+}
+
+// fieldValuesPlanModifier keeps the computed field_values from state while the
+// fields and field blocks do not change
+type fieldValuesPlanModifier struct{}
+
+func (m fieldValuesPlanModifier) Description(ctx context.Context) string {
+	return "Uses the field values from state unless the fields change."
+}
+
+func (m fieldValuesPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return "Uses the field values from state unless the fields change."
+}
+
+func (m fieldValuesPlanModifier) PlanModifyMap(ctx context.Context, req planmodifier.MapRequest, resp *planmodifier.MapResponse) {
+	if !req.ConfigValue.IsNull() || req.StateValue.IsNull() || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var planFields, stateFields types.List
+	var planBlocks, stateBlocks types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("fields"), &planFields)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("fields"), &stateFields)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("field"), &planBlocks)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("field"), &stateBlocks)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if planFields.Equal(stateFields) && planBlocks.Equal(stateBlocks) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// ValidateConfig rejects configurations setting fields more than one way, and
+// checks the write-only field values
+func (r *TssSecretResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var fieldValues types.Map
+	var fields types.List
+	var blocks types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("field_values"), &fieldValues)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("fields"), &fields)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("field"), &blocks)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ways := 0
+	for _, set := range []bool{!fieldValues.IsNull(), len(fields.Elements()) > 0, len(blocks.Elements()) > 0} {
+		if set {
+			ways++
+		}
+	}
+	if ways > 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("field_values"), "Conflicting Field Configuration",
+			"Only one of field_values, fields blocks and field blocks can be set; declare every field in one of them.")
+	}
+	validateWriteOnlyFields(ctx, req.Config, &resp.Diagnostics)
+	validatePasswordPolicies(ctx, req.Config, &resp.Diagnostics)
+	validateValueHashes(ctx, req.Config, &resp.Diagnostics)
+	validateSourceFiles(ctx, req.Config, &resp.Diagnostics)
+}
+
+// configuredFieldValues returns the field_values of the configuration, which
+// is null when it is not set
+func configuredFieldValues(ctx context.Context, config tfsdk.Config) (types.Map, diag.Diagnostics) {
+	var fieldValues types.Map
+	diags := config.GetAttribute(ctx, path.Root("field_values"), &fieldValues)
+	return fieldValues, diags
+}
+
+// withFieldValues returns plan with fields blocks holding values, so a secret
+// configured with field_values, or with field blocks, is written like one
+// configured with fields blocks
+func withFieldValues(plan *SecretResourceState, values types.Map) *SecretResourceState {
+	if len(plan.Field) > 0 {
+		withBlocks := *plan
+		withBlocks.Fields = make([]SecretField, 0, len(plan.Field))
+		for _, block := range plan.Field {
+			withBlocks.Fields = append(withBlocks.Fields, SecretField{
+				FieldName: block.Name,
+				ItemValue: block.Value,
+			})
+		}
+		return &withBlocks
+	}
+	if values.IsNull() || values.IsUnknown() {
+		return plan
+	}
+	elements := values.Elements()
+	slugs := make([]string, 0, len(elements))
+	for slug := range elements {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	withValues := *plan
+	withValues.Fields = make([]SecretField, 0, len(slugs))
+	for _, slug := range slugs {
+		value, _ := elements[slug].(types.String)
+		withValues.Fields = append(withValues.Fields, SecretField{
+			FieldName: types.StringValue(slug),
+			ItemValue: value,
+		})
+	}
+	return &withValues
+}
+
+// fieldValuesValue returns the values of fields by slug. Only the keys of keys
+// are included when it is known, otherwise every field other than file fields.
+func fieldValuesValue(fields []SecretField, keys types.Map) (types.Map, diag.Diagnostics) {
+	values := map[string]attr.Value{}
+	if keys.IsNull() || keys.IsUnknown() {
+		for _, field := range fields {
+			if !field.IsFile.ValueBool() && field.Slug.ValueString() != "" {
+				values[field.Slug.ValueString()] = field.ItemValue
+			}
+		}
+		return types.MapValue(types.StringType, values)
+	}
+
+	for key := range keys.Elements() {
+		for _, field := range fields {
+			if strings.EqualFold(field.Slug.ValueString(), key) || strings.EqualFold(field.FieldName.ValueString(), key) {
+				values[key] = field.ItemValue
+				break
+			}
+		}
+	}
+	return types.MapValue(types.StringType, values)
+}
+
+// setFieldValues sets field_values and the field blocks of a state read back
+// after a write. When field_values or field blocks are configured, the fields
+// blocks read back are dropped, as the configuration declares none.
+func setFieldValues(newState, plan *SecretResourceState, configured bool) diag.Diagnostics {
+	fieldValues, diags := fieldValuesValue(newState.Fields, plan.FieldValues)
+	newState.FieldValues = fieldValues
+	newState.Field = fieldBlocksValue(newState.Fields, plan.Field)
+	if configured || len(plan.Field) > 0 {
+		newState.Fields = plan.Fields
+	}
+	return diags
+}
+
+// UpgradeState upgrades the state of earlier schema versions
+func (r *TssSecretResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 only had the fields blocks; field_values is filled from them
+		0: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var state map[string]interface{}
+				if err := json.Unmarshal(req.RawState.JSON, &state); err != nil {
+					resp.Diagnostics.AddError("State Upgrade Error", "Failed to parse the state of the secret: "+err.Error())
+					return
+				}
+
+				fields, _ := state["fields"].([]interface{})
+				values := map[string]interface{}{}
+				for _, f := range fields {
+					field, _ := f.(map[string]interface{})
+					if isFile, _ := field["isfile"].(bool); isFile {
+						continue
+					}
+					key, _ := field["slug"].(string)
+					if key == "" {
+						key, _ = field["fieldname"].(string)
+					}
+					if key != "" {
+						value, _ := field["itemvalue"].(string)
+						values[key] = value
+					}
+				}
+				state["field_values"] = values
+
+				upgraded, err := json.Marshal(state)
+				if err != nil {
+					resp.Diagnostics.AddError("State Upgrade Error", "Failed to encode the upgraded state of the secret: "+err.Error())
+					return
+				}
+				resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+			},
+		},
+	}
+}
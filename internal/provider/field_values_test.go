@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// upgradeSecretState runs the state upgrader of version on rawState
+func upgradeSecretState(t *testing.T, version int64, rawState string) *resource.UpgradeStateResponse {
+	t.Helper()
+	upgrader, ok := (&TssSecretResource{}).UpgradeState(context.Background())[version]
+	if !ok {
+		t.Fatalf("no state upgrader for version %d", version)
+	}
+	resp := &resource.UpgradeStateResponse{}
+	upgrader.StateUpgrader(context.Background(), resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(rawState)}}, resp)
+	return resp
+}
+
+func TestUpgradeSecretStateFromVersion0(t *testing.T) {
+	resp := upgradeSecretState(t, 0, `{
+		"id": "42",
+		"name": "db",
+		"fields": [
+			{"fieldname": "Username", "slug": "username", "itemvalue": "svc", "isfile": false},
+			{"fieldname": "Password", "slug": "password", "itemvalue": "hunter2", "isfile": false},
+			{"fieldname": "Notes", "slug": "", "itemvalue": null, "isfile": false},
+			{"fieldname": "Key", "slug": "key", "itemvalue": "-----BEGIN KEY-----", "isfile": true}
+		]
+	}`)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	if resp.DynamicValue == nil {
+		t.Fatal("the upgrader returned no state")
+	}
+
+	var upgraded map[string]interface{}
+	if err := json.Unmarshal(resp.DynamicValue.JSON, &upgraded); err != nil {
+		t.Fatal(err)
+	}
+	// Fields without a slug are keyed by name, and file fields are left out
+	want := map[string]interface{}{"username": "svc", "password": "hunter2", "Notes": ""}
+	if !reflect.DeepEqual(upgraded["field_values"], want) {
+		t.Errorf("field_values = %v, want %v", upgraded["field_values"], want)
+	}
+	if upgraded["id"] != "42" || len(upgraded["fields"].([]interface{})) != 4 {
+		t.Errorf("the upgrade changed the rest of the state: %v", upgraded)
+	}
+}
+
+func TestUpgradeSecretStateWithoutFields(t *testing.T) {
+	resp := upgradeSecretState(t, 0, `{"id": "42", "fields": null}`)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	var upgraded map[string]interface{}
+	if err := json.Unmarshal(resp.DynamicValue.JSON, &upgraded); err != nil {
+		t.Fatal(err)
+	}
+	if values, ok := upgraded["field_values"].(map[string]interface{}); !ok || len(values) != 0 {
+		t.Errorf("field_values = %v, want an empty map", upgraded["field_values"])
+	}
+}
+
+func TestUpgradeSecretStateRejectsMalformedState(t *testing.T) {
+	if resp := upgradeSecretState(t, 0, `{"id": `); !resp.Diagnostics.HasError() {
+		t.Error("a malformed state was upgraded")
+	}
+}
+
+func TestSecretSchemaVersionHasUpgraders(t *testing.T) {
+	upgraders := (&TssSecretResource{}).UpgradeState(context.Background())
+	for version := int64(0); version < secretSchemaVersion; version++ {
+		if _, ok := upgraders[version]; !ok {
+			t.Errorf("state of version %d cannot be upgraded to %d", version, secretSchemaVersion)
+		}
+	}
+}
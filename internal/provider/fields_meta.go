@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// fieldMetaAttrTypes are the attributes of an element of fields_meta
+var fieldMetaAttrTypes = map[string]attr.Type{
+	"fieldname":        types.StringType,
+	"itemid":           types.Int64Type,
+	"fieldid":          types.Int64Type,
+	"fileattachmentid": types.Int64Type,
+	"slug":             types.StringType,
+	"fielddescription": types.StringType,
+	"isfile":           types.BoolType,
+	"isnotes":          types.BoolType,
+	"ispassword":       types.BoolType,
+	"islist":           types.BoolType,
+	"listtype":         types.StringType,
+}
+
+// fieldMetaDeprecation is the deprecation message of the structural attributes
+// of the fields blocks
+const fieldMetaDeprecation = "Secret Server assigns this attribute; read it from fields_meta instead of setting it in configuration."
+
+// fieldsMetaAttribute is the schema of fields_meta, the server-assigned
+// structure of the fields, kept apart from the values declared in the fields
+// blocks so that it is never compared with configuration
+func fieldsMetaAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Computed: true,
+		Description: "The structure of every field of the secret as Secret Server reports it: its IDs, slug, description and type. " +
+			"Use it instead of the same attributes of the fields blocks, which only exist for compatibility.",
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"fieldname":        schema.StringAttribute{Computed: true, Description: "The name of the field."},
+				"itemid":           schema.Int64Attribute{Computed: true, Description: "The ID of the field's item on this secret."},
+				"fieldid":          schema.Int64Attribute{Computed: true, Description: "The ID of the template field."},
+				"fileattachmentid": schema.Int64Attribute{Computed: true, Description: "The ID of the file attachment of a file field."},
+				"slug":             schema.StringAttribute{Computed: true, Description: "The slug of the field."},
+				"fielddescription": schema.StringAttribute{Computed: true, Description: "The description of the field."},
+				"isfile":           schema.BoolAttribute{Computed: true, Description: "Whether the field holds a file attachment."},
+				"isnotes":          schema.BoolAttribute{Computed: true, Description: "Whether the field is a notes field."},
+				"ispassword":       schema.BoolAttribute{Computed: true, Description: "Whether the field is a password field."},
+				"islist":           schema.BoolAttribute{Computed: true, Description: "Whether the field is a list field."},
+				"listtype":         schema.StringAttribute{Computed: true, Description: "The list type of a list field."},
+			},
+		},
+		PlanModifiers: []planmodifier.List{
+			fieldsMetaPlanModifier{},
+		},
+	}
+}
+
+// fieldsMetaValue returns fields_meta for the fields read from Secret Server
+func fieldsMetaValue(fields []SecretField) (types.List, diag.Diagnostics) {
+	elementType := types.ObjectType{AttrTypes: fieldMetaAttrTypes}
+	elements := make([]attr.Value, 0, len(fields))
+	for _, f := range fields {
+		element, diags := types.ObjectValue(fieldMetaAttrTypes, map[string]attr.Value{
+			"fieldname":        f.FieldName,
+			"itemid":           f.ItemID,
+			"fieldid":          f.FieldID,
+			"fileattachmentid": f.FileAttachmentID,
+			"slug":             f.Slug,
+			"fielddescription": f.FieldDescription,
+			"isfile":           f.IsFile,
+			"isnotes":          f.IsNotes,
+			"ispassword":       f.IsPassword,
+			"islist":           f.IsList,
+			"listtype":         f.ListType,
+		})
+		if diags.HasError() {
+			return types.ListNull(elementType), diags
+		}
+		elements = append(elements, element)
+	}
+	return types.ListValue(elementType, elements)
+}
+
+// fieldsMetaPlanModifier keeps fields_meta from state while the fields blocks
+// declare the same fields, since Secret Server only assigns the structure of a
+// field when it is added
+type fieldsMetaPlanModifier struct{}
+
+func (m fieldsMetaPlanModifier) Description(ctx context.Context) string {
+	return "Uses the field structure from state unless fields are added or removed."
+}
+
+func (m fieldsMetaPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m fieldsMetaPlanModifier) PlanModifyList(ctx context.Context, req planmodifier.ListRequest, resp *planmodifier.ListResponse) {
+	if req.StateValue.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+
+	var planFields, stateFields []SecretField
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("fields"), &planFields)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("fields"), &stateFields)...)
+	if resp.Diagnostics.HasError() || !sameFieldNames(planFields, stateFields) {
+		return
+	}
+	resp.PlanValue = req.StateValue
+}
+
+// sameFieldNames reports whether a and b declare the same fields, in any order
+func sameFieldNames(a, b []SecretField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	names := map[string]int{}
+	for _, f := range a {
+		if f.FieldName.IsUnknown() {
+			return false
+		}
+		names[strings.ToLower(f.FieldName.ValueString())]++
+	}
+	for _, f := range b {
+		name := strings.ToLower(f.FieldName.ValueString())
+		if names[name] == 0 {
+			return false
+		}
+		names[name]--
+	}
+	return true
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// stateSecret is a secret resource instance of a state file
+type stateSecret struct {
+	Address  string
+	ID       int
+	Name     string
+	FolderID int
+}
+
+// orphanKind is how a secret resource instance no longer matches Secret Server
+type orphanKind string
+
+const (
+	orphanMissing  orphanKind = "missing"
+	orphanInactive orphanKind = "inactive"
+	orphanMoved    orphanKind = "moved"
+)
+
+// orphan is a secret resource instance whose secret is gone or has changed
+type orphan struct {
+	Kind   orphanKind
+	Secret stateSecret
+	// Current is the secret as it is now, for inactive and moved secrets
+	Current *secretSummary
+	// Replacements are active secrets with the name of a missing or inactive
+	// secret, in the same folder, which the resource could be imported from
+	Replacements []secretSummary
+}
+
+// FindOrphans implements the find-orphans command. It looks up the secret of
+// every secret resource instance of a state file and writes the terraform state
+// rm and import commands that clean up those whose secret was deleted, and notes
+// those whose secret was renamed or moved. It connects as described for
+// cliClient, and fails when any instance is orphaned.
+func FindOrphans(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("find-orphans", flag.ContinueOnError)
+	statePath := flags.String("state", "terraform.tfstate", "the state file to check, e.g. from terraform state pull")
+	parallelism := flags.Int("parallelism", 8, "the number of secrets looked up at the same time")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *parallelism < 1 {
+		return fmt.Errorf("--parallelism must be at least 1")
+	}
+
+	data, err := os.ReadFile(*statePath)
+	if err != nil {
+		return err
+	}
+	var state stateFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse state file %s (decrypt it first if it is encrypted): %w", *statePath, err)
+	}
+	secrets := stateSecrets(&state)
+
+	_, api, err := cliClient()
+	if err != nil {
+		return err
+	}
+
+	orphans, err := findOrphans(ctx, api, secrets, *parallelism)
+	if err != nil {
+		return err
+	}
+	if err := writeOrphans(os.Stdout, orphans); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Checked %d secrets of %s\n", len(secrets), *statePath)
+	if len(orphans) > 0 {
+		return fmt.Errorf("found %d secret resources in %s that no longer match Secret Server", len(orphans), *statePath)
+	}
+	return nil
+}
+
+// stateSecrets returns the secret resource instances of the state
+func stateSecrets(state *stateFile) []stateSecret {
+	var secrets []stateSecret
+	for _, resource := range state.Resources {
+		if resource.Mode != "managed" || resource.Type != secretResourceType {
+			continue
+		}
+		for _, instance := range resource.Instances {
+			id, err := strconv.Atoi(fmt.Sprint(instance.Attributes["id"]))
+			if err != nil {
+				continue
+			}
+			folderID, _ := strconv.Atoi(fmt.Sprint(instance.Attributes["folderid"]))
+			name, _ := instance.Attributes["name"].(string)
+			secrets = append(secrets, stateSecret{
+				Address:  stateAddress(resource.Module, resource.Mode, resource.Type, resource.Name, instance.IndexKey),
+				ID:       id,
+				Name:     name,
+				FolderID: folderID,
+			})
+		}
+	}
+	return secrets
+}
+
+// findOrphans looks up the secrets with at most parallelism lookups in flight,
+// returning the orphaned instances ordered by address
+func findOrphans(ctx context.Context, api *apiClient, secrets []stateSecret, parallelism int) ([]orphan, error) {
+	found := make([]*orphan, len(secrets))
+	errs := make([]error, len(secrets))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	for i, secret := range secrets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, secret stateSecret) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			found[i], errs[i] = checkStateSecret(ctx, api, secret)
+		}(i, secret)
+	}
+	wg.Wait()
+
+	var orphans []orphan
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up secret %d of %s: %w", secrets[i].ID, secrets[i].Address, err)
+		}
+		if found[i] != nil {
+			orphans = append(orphans, *found[i])
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].Secret.Address < orphans[j].Secret.Address })
+	return orphans, nil
+}
+
+// checkStateSecret returns how the secret of a resource instance no longer
+// matches Secret Server, or nil when it still does
+func checkStateSecret(ctx context.Context, api *apiClient, secret stateSecret) (*orphan, error) {
+	current, err := api.lookupSecret(ctx, secret.ID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	o := &orphan{Secret: secret, Current: current}
+	switch {
+	case current == nil:
+		o.Kind = orphanMissing
+	case !current.Active:
+		o.Kind = orphanInactive
+	case current.Name != secret.Name || current.FolderID != secret.FolderID:
+		o.Kind = orphanMoved
+		return o, nil
+	default:
+		return nil, nil
+	}
+
+	// A secret deleted and created again by hand has the same name and folder
+	// but a new ID
+	if secret.Name == "" {
+		return o, nil
+	}
+	candidates, err := api.searchSecrets(ctx, secretSearchFilter{FolderID: secret.FolderID, SearchText: secret.Name})
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		if candidate.Active && candidate.ID != secret.ID && candidate.Name == secret.Name && candidate.FolderID == secret.FolderID {
+			o.Replacements = append(o.Replacements, candidate)
+		}
+	}
+	return o, nil
+}
+
+// lookupSecret returns the name, folder and status of a secret
+func (c *apiClient) lookupSecret(ctx context.Context, secretID int) (*secretSummary, error) {
+	summary := new(secretSummary)
+	if err := c.do(ctx, "GET", fmt.Sprintf("secrets/%d/summary", secretID), nil, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// writeOrphans writes a comment per orphaned instance describing what changed,
+// followed by the commands that bring the state back in line. A single
+// replacement is imported; several are listed for the user to choose from.
+func writeOrphans(w io.Writer, orphans []orphan) error {
+	var b strings.Builder
+	for _, o := range orphans {
+		address := shellQuote(o.Secret.Address)
+		switch o.Kind {
+		case orphanMissing:
+			fmt.Fprintf(&b, "# %s: secret %d (%s) no longer exists\n", o.Secret.Address, o.Secret.ID, o.Secret.Name)
+		case orphanInactive:
+			fmt.Fprintf(&b, "# %s: secret %d (%s) has been deleted\n", o.Secret.Address, o.Secret.ID, o.Secret.Name)
+		case orphanMoved:
+			fmt.Fprintf(&b, "# %s: secret %d is now %q in folder %d, not %q in folder %d\n", o.Secret.Address, o.Secret.ID,
+				o.Current.Name, o.Current.FolderID, o.Secret.Name, o.Secret.FolderID)
+			b.WriteString("# update the configuration to match, or apply it to move the secret back\n\n")
+			continue
+		}
+
+		fmt.Fprintf(&b, "terraform state rm %s\n", address)
+		switch len(o.Replacements) {
+		case 0:
+		case 1:
+			fmt.Fprintf(&b, "terraform import %s %d\n", address, o.Replacements[0].ID)
+		default:
+			ids := make([]string, len(o.Replacements))
+			for i, replacement := range o.Replacements {
+				ids[i] = strconv.Itoa(replacement.ID)
+			}
+			fmt.Fprintf(&b, "# secrets %s have the same name and folder; import one of them:\n", strings.Join(ids, ", "))
+			fmt.Fprintf(&b, "# terraform import %s <id>\n", address)
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// shellQuote quotes s as a single POSIX shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
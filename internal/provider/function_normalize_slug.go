@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &NormalizeSlugFunction{}
+
+// slugSeparators matches the runs of characters a slug replaces with a hyphen
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// NewNormalizeSlugFunction is a helper function to simplify the provider implementation.
+func NewNormalizeSlugFunction() function.Function {
+	return &NormalizeSlugFunction{}
+}
+
+// NormalizeSlugFunction turns a display name into the slug Secret Server derives
+// from it, e.g. "Private Key" into "private-key"
+type NormalizeSlugFunction struct{}
+
+// Metadata provides the function name
+func (f *NormalizeSlugFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_slug"
+}
+
+// Definition defines the parameters and return type of the function
+func (f *NormalizeSlugFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts a field or template name to its slug",
+		Description: "Returns the slug Secret Server derives from a display name: lower case, with every run of characters other than " +
+			"letters and digits replaced by a single hyphen and leading and trailing hyphens removed, e.g. \"Private Key\" becomes \"private-key\".",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "name",
+				Description: "The display name to convert.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run converts the name
+func (f *NormalizeSlugFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	resp.Error = req.Arguments.Get(ctx, &name)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, normalizeSlug(name))
+}
+
+// normalizeSlug returns the slug of a display name
+func normalizeSlug(name string) string {
+	return strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
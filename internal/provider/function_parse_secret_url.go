@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &ParseSecretURLFunction{}
+
+// secretURLAttrTypes are the attributes of the object parse_secret_url returns
+var secretURLAttrTypes = map[string]attr.Type{
+	"server_url": types.StringType,
+	"secret_id":  types.Int64Type,
+}
+
+// secretURLPaths match the paths that identify a secret: the web UI's secret
+// page, the classic SecretView page and the REST API. The part before them is
+// the server's base path.
+var secretURLPaths = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^(.*?)/app/?$`),
+	regexp.MustCompile(`(?i)^(.*?)/secretview\.aspx$`),
+	regexp.MustCompile(`(?i)^(.*?)/api/v1/secrets/(\d+)(?:/.*)?$`),
+}
+
+// appSecretFragment matches the web UI route of a secret, e.g. #/secret/42/general
+var appSecretFragment = regexp.MustCompile(`^/secret/(\d+)(?:/.*)?$`)
+
+// NewParseSecretURLFunction is a helper function to simplify the provider implementation.
+func NewParseSecretURLFunction() function.Function {
+	return &ParseSecretURLFunction{}
+}
+
+// ParseSecretURLFunction extracts the server URL and secret ID from the URL of a secret
+type ParseSecretURLFunction struct{}
+
+// Metadata provides the function name
+func (f *ParseSecretURLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_secret_url"
+}
+
+// Definition defines the parameters and return type of the function
+func (f *ParseSecretURLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses the URL of a secret",
+		Description: "Returns the server_url and secret_id of a secret from the URL of its page in the web UI " +
+			"(e.g. https://example.com/SecretServer/app/#/secret/42/general), its classic SecretView.aspx?secretid=42 page, " +
+			"or its REST API path (api/v1/secrets/42).",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "url",
+				Description: "The URL of the secret.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: secretURLAttrTypes,
+		},
+	}
+}
+
+// Run parses the URL
+func (f *ParseSecretURLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var raw string
+	resp.Error = req.Arguments.Get(ctx, &raw)
+	if resp.Error != nil {
+		return
+	}
+
+	serverURL, secretID, err := parseSecretURL(raw)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result, diags := types.ObjectValue(secretURLAttrTypes, map[string]attr.Value{
+		"server_url": types.StringValue(serverURL),
+		"secret_id":  types.Int64Value(int64(secretID)),
+	})
+	if diags.HasError() {
+		resp.Error = function.FuncErrorFromDiags(ctx, diags)
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, result)
+}
+
+// parseSecretURL returns the server URL and secret ID of the URL of a secret
+func parseSecretURL(raw string) (string, int, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", 0, fmt.Errorf("%q is not an absolute URL", raw)
+	}
+
+	for i, pattern := range secretURLPaths {
+		m := pattern.FindStringSubmatch(u.Path)
+		if m == nil {
+			continue
+		}
+
+		var id string
+		switch i {
+		case 0:
+			if f := appSecretFragment.FindStringSubmatch(u.Fragment); f != nil {
+				id = f[1]
+			}
+		case 1:
+			for key, values := range u.Query() {
+				if strings.EqualFold(key, "secretid") && len(values) > 0 {
+					id = values[0]
+				}
+			}
+		case 2:
+			id = m[2]
+		}
+
+		secretID, err := strconv.Atoi(id)
+		if err != nil || secretID <= 0 {
+			break
+		}
+		return fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, m[1]), secretID, nil
+	}
+
+	return "", 0, fmt.Errorf("%q is not the URL of a secret: expected its web UI page, SecretView.aspx?secretid= page or api/v1/secrets/ path", raw)
+}
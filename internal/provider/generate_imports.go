@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+)
+
+// secretResourceType is the type name of the secret resource in configuration
+const secretResourceType = "dept-tss_resource_secret"
+
+// GenerateImports implements the generate-imports command. It writes an import
+// block and a skeleton resource block for every secret in a folder, reading the
+// secrets in parallel. It connects as described for cliClient.
+func GenerateImports(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("generate-imports", flag.ContinueOnError)
+	folderID := flags.Int("folder", 0, "the ID of the folder whose secrets are imported (required)")
+	out := flags.String("out", "", "the file to write the configuration to; defaults to standard output")
+	subFolders := flags.Bool("include-subfolders", false, "also import the secrets of subfolders")
+	parallelism := flags.Int("parallelism", 8, "the number of secrets read at the same time")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *folderID <= 0 {
+		return fmt.Errorf("--folder is required")
+	}
+	if *parallelism < 1 {
+		return fmt.Errorf("--parallelism must be at least 1")
+	}
+
+	tssClient, api, err := cliClient()
+	if err != nil {
+		return err
+	}
+
+	summaries, err := api.searchSecrets(ctx, secretSearchFilter{FolderID: *folderID, IncludeSubFolders: *subFolders})
+	if err != nil {
+		return fmt.Errorf("failed to list the secrets of folder %d: %w", *folderID, err)
+	}
+
+	secrets, err := readSecretsInParallel(ctx, tssClient, api, summaries, *parallelism)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := writeImportConfig(w, secrets); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Generated import blocks for %d secrets in folder %d\n", len(secrets), *folderID)
+	return nil
+}
+
+// cliClient returns the SDK server and api client for a command, connected with
+// the TSS_SERVER_URL, TSS_USER, TSS_PASSWORD, TSS_DOMAIN and TSS_TOKEN
+// environment variables, or the SS_* names the provider also reads
+func cliClient() (*server.Server, *apiClient, error) {
+	var config server.Configuration
+	config.ServerURL, config.Credentials = environmentCredentials()
+	if config.ServerURL == "" {
+		return nil, nil, fmt.Errorf("neither TSS_SERVER_URL nor SS_SERVER_URL is set")
+	}
+	tssClient, err := server.New(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the Secret Server client: %w", err)
+	}
+	api := newAPIClient(tssClient)
+	api.defaults = defaultCallOptions
+
+	return tssClient, api, nil
+}
+
+// readSecretsInParallel reads the secrets with at most parallelism reads in flight,
+// returning them in the order of the summaries
+func readSecretsInParallel(ctx context.Context, s *server.Server, api *apiClient, summaries []secretSummary, parallelism int) ([]*server.Secret, error) {
+	client := newSDKClient(ctx, s, api, defaultCallOptions)
+	secrets := make([]*server.Secret, len(summaries))
+	errs := make([]error, len(summaries))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	for i, summary := range summaries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i, id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			secrets[i], errs[i] = client.Secret(id)
+		}(i, summary.ID)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret %d: %w", summaries[i].ID, err)
+		}
+	}
+	return secrets, nil
+}
+
+// writeImportConfig writes an import block and a resource block per secret.
+// Password, notes and file field values are left out: the resource keeps them
+// from state until they are set in configuration.
+func writeImportConfig(w io.Writer, secrets []*server.Secret) error {
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].ID < secrets[j].ID })
+
+	var b strings.Builder
+	for _, secret := range secrets {
+		label := resourceLabel(secret.Name, secret.ID)
+
+		fmt.Fprintf(&b, "import {\n  to = %s.%s\n  id = %q\n}\n\n", secretResourceType, label, strconv.Itoa(secret.ID))
+		fmt.Fprintf(&b, "resource %q %q {\n", secretResourceType, label)
+		fmt.Fprintf(&b, "  name             = %s\n", hclString(secret.Name))
+		fmt.Fprintf(&b, "  folderid         = %q\n", strconv.Itoa(secret.FolderID))
+		fmt.Fprintf(&b, "  siteid           = %q\n", strconv.Itoa(secret.SiteID))
+		fmt.Fprintf(&b, "  secrettemplateid = %q\n", strconv.Itoa(secret.SecretTemplateID))
+
+		for _, field := range secret.Fields {
+			fmt.Fprintf(&b, "\n  fields {\n    fieldname = %s\n", hclString(field.FieldName))
+			switch {
+			case field.IsPassword, field.IsNotes, field.IsFile:
+				b.WriteString("    # value kept from state\n")
+			default:
+				fmt.Fprintf(&b, "    itemvalue = %s\n", hclString(field.ItemValue))
+			}
+			b.WriteString("  }\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var labelInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// resourceLabel returns a resource name derived from the secret name, made unique by the ID
+func resourceLabel(name string, id int) string {
+	label := strings.Trim(labelInvalidChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = "secret_" + label
+	}
+	return fmt.Sprintf("%s_%d", strings.TrimRight(label, "_"), id)
+}
+
+// hclString quotes s as an HCL string literal, escaping template sequences
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			// ${ and %{ start template sequences
+			b.WriteRune(r)
+			if i+1 < len(s) && s[i+1] == '{' {
+				b.WriteRune(r)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package provider
+
+import "testing"
+
+func TestCLIClientReadsSDKEnvironment(t *testing.T) {
+	for _, name := range []string{"TSS_SERVER_URL", "TSS_USER", "TSS_PASSWORD", "TSS_DOMAIN", "TSS_TOKEN"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("SS_SERVER_URL", "https://tss.example.com/SecretServer")
+	t.Setenv("SS_USERNAME", "terraform")
+	t.Setenv("SS_PASSWORD", "hunter2")
+
+	tssClient, _, err := cliClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := tssClient.Configuration
+	if config.ServerURL != "https://tss.example.com/SecretServer" || config.Credentials.Username != "terraform" || config.Credentials.Password != "hunter2" {
+		t.Errorf("configuration = %s as %s", config.ServerURL, config.Credentials.Username)
+	}
+
+	// The TSS_* names win over the SDK's
+	t.Setenv("TSS_SERVER_URL", "https://other.example.com/SecretServer")
+	if tssClient, _, err = cliClient(); err != nil {
+		t.Fatal(err)
+	}
+	if tssClient.ServerURL != "https://other.example.com/SecretServer" {
+		t.Errorf("server URL = %s", tssClient.ServerURL)
+	}
+
+	t.Setenv("TSS_SERVER_URL", "")
+	t.Setenv("SS_SERVER_URL", "")
+	if _, _, err := cliClient(); err == nil {
+		t.Error("a client was created without a server URL")
+	}
+}
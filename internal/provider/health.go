@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// healthGate checks the health of Secret Server once, before the first write of
+// the run, so an apply against a degraded server stops before changing anything
+type healthGate struct {
+	once sync.Once
+	err  error
+}
+
+// serverHealth is the response of the health check endpoint
+type serverHealth struct {
+	Healthy               bool
+	DatabaseHealthy       bool
+	ServiceBusHealthy     bool
+	StorageAccountHealthy bool
+}
+
+// ensureHealthy returns an error when health gating is enabled and Secret Server
+// reported itself unhealthy. The server is only checked once; every later write
+// gets the same result.
+func (c *apiClient) ensureHealthy(ctx context.Context) error {
+	if c.health == nil {
+		return nil
+	}
+	c.health.once.Do(func() {
+		c.health.err = c.checkHealth(ctx)
+	})
+	return c.health.err
+}
+
+// checkHealth calls the health check endpoint and returns an error naming the
+// unhealthy components
+func (c *apiClient) checkHealth(ctx context.Context) error {
+	var health serverHealth
+	if err := c.do(ctx, "GET", "healthcheck", nil, &health); err != nil {
+		return fmt.Errorf("aborting because abort_if_unhealthy is set and the Secret Server health check failed: %w", err)
+	}
+
+	tflog.Debug(ctx, "Checked Secret Server health", map[string]interface{}{
+		"healthy": health.Healthy,
+	})
+	if health.Healthy {
+		return nil
+	}
+
+	var unhealthy []string
+	if !health.DatabaseHealthy {
+		unhealthy = append(unhealthy, "database")
+	}
+	if !health.ServiceBusHealthy {
+		unhealthy = append(unhealthy, "service bus")
+	}
+	if !health.StorageAccountHealthy {
+		unhealthy = append(unhealthy, "storage")
+	}
+	detail := ""
+	if len(unhealthy) > 0 {
+		detail = fmt.Sprintf(" (unhealthy: %s)", strings.Join(unhealthy, ", "))
+	}
+	return fmt.Errorf("aborting because abort_if_unhealthy is set and Secret Server reports it is degraded%s; no changes were made by this call", detail)
+}
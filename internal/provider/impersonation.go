@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// impersonation holds the access token reads are made with when
+// impersonate_user is set, so Secret Server attributes them to that user
+type impersonation struct {
+	username string
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// impersonationError is returned when the provider cannot act as the
+// impersonated user
+type impersonationError struct {
+	username string
+	err      error
+}
+
+func (e *impersonationError) Error() string {
+	switch {
+	case isCredentialError(e.err):
+		return fmt.Sprintf("the authenticated account is not permitted to impersonate %q; grant it a role with the "+
+			"Impersonate Users permission or unset impersonate_user: %s", e.username, e.err)
+	case isNotFound(e.err):
+		return fmt.Sprintf("Secret Server does not support impersonation, or has no user %q: %s", e.username, e.err)
+	}
+	return fmt.Sprintf("failed to impersonate %q: %s", e.username, e.err)
+}
+
+func (e *impersonationError) Unwrap() error {
+	return e.err
+}
+
+// impersonateUser makes the client read as username
+func (c *apiClient) impersonateUser(username string) {
+	c.impersonation = &impersonation{username: username}
+}
+
+// impersonates reports whether calls made with method act as the impersonated
+// user. Only reads do; writes stay attributed to the authenticated account.
+func (c *apiClient) impersonates(method string) bool {
+	return c.impersonation != nil && method == http.MethodGet
+}
+
+// callToken returns the access token of a call made with method
+func (c *apiClient) callToken(ctx context.Context, method string) (string, error) {
+	if !c.impersonates(method) {
+		return c.token(ctx)
+	}
+
+	i := c.impersonation
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.accessToken != "" && time.Now().Before(i.expiresAt) {
+		return i.accessToken, nil
+	}
+
+	accessToken, lifetime, err := c.impersonate(ctx, i.username)
+	if err != nil {
+		return "", &impersonationError{username: i.username, err: err}
+	}
+	i.accessToken = accessToken
+	// As with the account's own token, stop using it a little early
+	i.expiresAt = time.Now().Add(lifetime * 9 / 10)
+	tflog.Debug(ctx, "Impersonating user for reads", map[string]interface{}{
+		"impersonate_user": i.username,
+	})
+	return i.accessToken, nil
+}
+
+// impersonate looks up username and requests an access token acting as that
+// user, returning it with its lifetime
+func (c *apiClient) impersonate(ctx context.Context, username string) (string, time.Duration, error) {
+	accessToken, err := c.token(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	baseURL, err := c.apiBaseURL(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	call := func(method, path string, output interface{}) error {
+		req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s/%s", baseURL, apiPathURI, path), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		start := time.Now()
+		data, err := c.send(req)
+		c.audit.record(c.baseURL(), method, path, nil, start, err)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, output)
+	}
+
+	var lookup struct {
+		Records []struct {
+			ID    int    `json:"id"`
+			Value string `json:"value"`
+		} `json:"records"`
+	}
+	if err := call("GET", "users/lookup?filter.searchText="+url.QueryEscape(username), &lookup); err != nil {
+		return "", 0, err
+	}
+	userID := 0
+	for _, record := range lookup.Records {
+		if strings.EqualFold(record.Value, username) {
+			userID = record.ID
+			break
+		}
+	}
+	if userID == 0 {
+		return "", 0, &apiError{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: "no user named " + username}
+	}
+
+	var grant struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := call("POST", fmt.Sprintf("users/%d/impersonate", userID), &grant); err != nil {
+		return "", 0, err
+	}
+	return grant.AccessToken, time.Duration(grant.ExpiresIn) * time.Second, nil
+}
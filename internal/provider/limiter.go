@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestLimiter bounds the Secret Server calls the provider has in flight, and
+// how fast it starts them, across every resource and data source. A nil
+// requestLimiter does not limit.
+type requestLimiter struct {
+	// slots holds a token per call in flight; nil when concurrency is unlimited
+	slots chan struct{}
+
+	mu sync.Mutex
+	// interval is the minimum time between the start of two calls; zero when the
+	// rate is unlimited
+	interval time.Duration
+	next     time.Time
+}
+
+// newRequestLimiter returns a limiter for the given settings, or nil when neither limits
+func newRequestLimiter(maxConcurrent int, perSecond float64) *requestLimiter {
+	if maxConcurrent <= 0 && perSecond <= 0 {
+		return nil
+	}
+
+	l := &requestLimiter{}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	if perSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return l
+}
+
+// acquire waits until a call may start. The returned release must be called
+// once the call has finished.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if l.interval > 0 {
+		// Reserve the next start time, then wait for it
+		l.mu.Lock()
+		now := time.Now()
+		start := l.next
+		if start.Before(now) {
+			start = now
+		}
+		l.next = start.Add(l.interval)
+		l.mu.Unlock()
+
+		if wait := time.Until(start); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	return release, nil
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklog"
+)
+
+// defaultRedactFields are log field names whose values are always masked: those
+// holding credentials and secret values, and those identifying the server, its
+// users and their files, so TF_LOG=TRACE output can be shared
+var defaultRedactFields = []string{
+	"password", "token", "itemvalue", "value",
+	"access_token", "client_secret", "subject_token", "onboarding_key", "socks5_password",
+	"private_key", "passphrase",
+	"server_url", "read_replica_url", "username", "filename",
+}
+
+// redactPatterns match secrets that can appear anywhere in a log message or
+// field value, e.g. in an error returned by Secret Server
+var redactPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`),
+	// JSON web tokens, such as OIDC ID tokens and platform access tokens
+	regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// minSensitiveValueLength is the length below which a sensitive value is not
+// masked wherever it appears, since short values would mask unrelated text
+const minSensitiveValueLength = 4
+
+// loggingConfig controls the provider's own log output
+type loggingConfig struct {
+	Level        hclog.Level
+	RedactFields []string
+	// SensitiveValues are masked wherever they appear in log output, e.g. the
+	// configured password and client secret
+	SensitiveValues []string
+}
+
+// withSensitiveValues returns l also masking values, ignoring empty and very short ones
+func (l loggingConfig) withSensitiveValues(values ...string) loggingConfig {
+	sensitive := append([]string{}, l.SensitiveValues...)
+	for _, value := range values {
+		if len(value) >= minSensitiveValueLength {
+			sensitive = append(sensitive, value)
+		}
+	}
+	l.SensitiveValues = sensitive
+	return l
+}
+
+// defaultLoggingConfig logs at the level Terraform was started with
+var defaultLoggingConfig = loggingConfig{
+	Level:        hclog.NoLevel,
+	RedactFields: defaultRedactFields,
+}
+
+// parseLogLevel converts a log_level value into an hclog level. The empty string
+// keeps the level Terraform was started with.
+func parseLogLevel(value string) (hclog.Level, bool) {
+	if value == "" {
+		return hclog.NoLevel, true
+	}
+	level := hclog.LevelFromString(value)
+	return level, level != hclog.NoLevel
+}
+
+// context returns ctx with the provider logger configured for this provider instance.
+// Every data source, resource and ephemeral resource operation should start with it.
+func (l loggingConfig) context(ctx context.Context) context.Context {
+	if l.Level != hclog.NoLevel {
+		// Replacing the root logger keeps the fields and masks already set on ctx,
+		// since those are stored separately from the logger itself
+		ctx = tfsdklog.NewRootProviderLogger(ctx, tfsdklog.WithLevel(l.Level))
+	}
+
+	keys := make([]string, 0, len(l.RedactFields))
+	for _, key := range l.RedactFields {
+		keys = append(keys, strings.ToLower(key))
+	}
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, keys...)
+	ctx = tflog.MaskLogRegexes(ctx, redactPatterns...)
+	if len(l.SensitiveValues) > 0 {
+		ctx = tflog.MaskLogStrings(ctx, l.SensitiveValues...)
+	}
+	return ctx
+}
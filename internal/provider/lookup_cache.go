@@ -0,0 +1,38 @@
+package provider
+
+import "sync"
+
+// lookupCache holds values read from Secret Server by ID for the life of a
+// client, i.e. for the run, so resources sharing them read each one once
+type lookupCache[V any] struct {
+	mu     sync.Mutex
+	values map[int]V
+}
+
+// get returns the value of id, reading it with read the first time it is
+// asked for. Errors are not cached, so a failed read is tried again. A nil
+// cache reads every time.
+func (c *lookupCache[V]) get(id int, read func() (V, error)) (V, error) {
+	if c == nil {
+		return read()
+	}
+
+	c.mu.Lock()
+	v, ok := c.values[id]
+	c.mu.Unlock()
+	if ok {
+		return v, nil
+	}
+
+	v, err := read()
+	if err != nil {
+		return v, err
+	}
+	c.mu.Lock()
+	if c.values == nil {
+		c.values = make(map[int]V)
+	}
+	c.values[id] = v
+	c.mu.Unlock()
+	return v, nil
+}
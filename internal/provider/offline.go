@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// errOffline is returned for every request of an offline client
+var errOffline = errors.New("Secret Server is not contacted because the provider configuration is not known until apply and skip_validation_during_plan is set")
+
+// newOfflineAPIClient returns a client for planning while the provider
+// configuration is unknown, e.g. because credentials come from the output of
+// another resource. It makes no requests: resources keep their prior state and
+// anything that needs Secret Server fails with errOffline.
+func newOfflineAPIClient() *apiClient {
+	return &apiClient{
+		offline:     true,
+		authSession: &authSession{},
+	}
+}
+
+// keepStateOffline reports whether a resource Read should keep its prior state
+// because the client is offline
+func (c *apiClient) keepStateOffline(ctx context.Context) bool {
+	if !c.offline {
+		return false
+	}
+	tflog.Debug(ctx, "Provider configuration is unknown, keeping prior state without refreshing")
+	return true
+}
+
+// unknownConnectionAttributes returns the connection and credential attributes
+// of the provider configuration whose values are unknown
+func unknownConnectionAttributes(data TssProviderModel) []string {
+	var unknown []string
+	for name, value := range map[string]interface{ IsUnknown() bool }{
+		"server_url":      data.ServerURL,
+		"username":        data.Username,
+		"password":        data.Password,
+		"domain":          data.Domain,
+		"domains":         data.Domains,
+		"token":           data.Token,
+		"client_id":       data.ClientID,
+		"client_secret":   data.ClientSecret,
+		"onboarding_rule": data.OnboardingRule,
+		"onboarding_key":  data.OnboardingKey,
+		"oidc_token_file": data.OIDCTokenFile,
+		"oidc_audience":   data.OIDCAudience,
+	} {
+		if value.IsUnknown() {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
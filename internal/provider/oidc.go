@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// GitHub Actions sets these when a job has the id-token: write permission
+	githubTokenRequestURLEnv   = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	githubTokenRequestTokenEnv = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
+
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	idTokenType            = "urn:ietf:params:oauth:token-type:id_token"
+)
+
+// oidcTokenSource supplies the OIDC ID token of a CI job, which Secret Server
+// exchanges for an access token
+type oidcTokenSource struct {
+	// tokenFile holds the ID token, e.g. one written from a GitLab CI id_tokens
+	// variable. It is read for every exchange, so rotated tokens are picked up.
+	tokenFile string
+	// audience is requested from GitHub Actions, which issues a token per audience
+	audience   string
+	httpClient *http.Client
+}
+
+// idToken returns a current ID token from the token file, or from the GitHub
+// Actions token request URL when no file is configured
+func (s *oidcTokenSource) idToken(ctx context.Context) (string, error) {
+	if s.tokenFile != "" {
+		data, err := os.ReadFile(s.tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read OIDC token file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	requestURL := os.Getenv(githubTokenRequestURLEnv)
+	requestToken := os.Getenv(githubTokenRequestTokenEnv)
+	if requestURL == "" || requestToken == "" {
+		return "", fmt.Errorf("no OIDC token file is configured and %s is not set; "+
+			"in GitHub Actions, grant the job the id-token: write permission", githubTokenRequestURLEnv)
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", githubTokenRequestURLEnv, err)
+	}
+	if s.audience != "" {
+		query := u.Query()
+		query.Set("audience", s.audience)
+		u.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request OIDC token: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to request OIDC token: %s", res.Status)
+	}
+
+	var token struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to parse OIDC token response: %w", err)
+	}
+	return token.Value, nil
+}
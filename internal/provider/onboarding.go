@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// onboarding serializes the registrations of the process, so that provider
+// configurations of the same client configured together register it once
+var onboarding sync.Mutex
+
+// onboardedClientSecret returns the client secret of clientID, registering it
+// under ruleName the first time. The secret issued is written to secretFile,
+// and read from it on later runs, so the client is only registered once.
+func (c *apiClient) onboardedClientSecret(ctx context.Context, clientID, ruleName, onboardingKey, secretFile string) (string, error) {
+	onboarding.Lock()
+	defer onboarding.Unlock()
+
+	data, err := os.ReadFile(secretFile)
+	if err == nil {
+		if secret := strings.TrimSpace(string(data)); secret != "" {
+			tflog.Debug(ctx, "Using the client secret of a previous registration", map[string]interface{}{
+				"client_id":   clientID,
+				"secret_file": secretFile,
+			})
+			return secret, nil
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read onboarding secret file: %w", err)
+	}
+
+	tflog.Info(ctx, "Registering SDK client", map[string]interface{}{
+		"client_id":       clientID,
+		"onboarding_rule": ruleName,
+	})
+	secret, err := c.registerSDKClient(ctx, clientID, ruleName, onboardingKey)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(secretFile, []byte(secret+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("client %q was registered, but its client secret could not be written to %s, "+
+			"so it cannot be used again; remove the client's account before retrying: %w", clientID, secretFile, err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOnboardedClientSecretRegistersOnce(t *testing.T) {
+	var registrations atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/sdk-client-accounts" {
+			http.NotFound(w, r)
+			return
+		}
+		registrations.Add(1)
+		_, _ = w.Write([]byte(`{"clientSecret":"issued-secret"}`))
+	}))
+	defer ts.Close()
+
+	secretFile := filepath.Join(t.TempDir(), "client-secret")
+	for run := 1; run <= 2; run++ {
+		// Each run configures a new client, as a new provider process would
+		c := newTestAPIClient(ts.URL)
+		secret, err := c.onboardedClientSecret(context.Background(), "terraform", "rule", "", secretFile)
+		if err != nil {
+			t.Fatalf("run %d: %s", run, err)
+		}
+		if secret != "issued-secret" {
+			t.Errorf("run %d: client secret = %q", run, secret)
+		}
+	}
+	if n := registrations.Load(); n != 1 {
+		t.Errorf("the client was registered %d times", n)
+	}
+
+	info, err := os.Stat(secretFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("the secret file is readable by others: %s", mode)
+	}
+}
+
+func TestOnboardedClientSecretKeepsNoSecretOnFailure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "onboarding rule not found", http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	secretFile := filepath.Join(t.TempDir(), "client-secret")
+	if _, err := newTestAPIClient(ts.URL).onboardedClientSecret(context.Background(), "terraform", "rule", "", secretFile); err == nil {
+		t.Fatal("a failed registration returned no error")
+	}
+	if _, err := os.Stat(secretFile); !os.IsNotExist(err) {
+		t.Errorf("a failed registration wrote the secret file: %v", err)
+	}
+}
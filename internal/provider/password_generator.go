@@ -0,0 +1,338 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The password_generator values: the Secret Server generate-password endpoint,
+// or the provider itself following the field's password requirement
+const (
+	passwordGeneratorServer = "server"
+	passwordGeneratorLocal  = "local"
+)
+
+// defaultGeneratedPasswordLength is the length of locally generated passwords
+// when the password requirement allows it
+const defaultGeneratedPasswordLength = 20
+
+// defaultPasswordCharacters are used when a password requirement does not
+// restrict the allowed characters
+const defaultPasswordCharacters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789!@#$%^&*()-_=+[]{}:;,.?"
+
+// The character sets of a password_policy, which together make up
+// defaultPasswordCharacters
+const (
+	lowercaseCharacters = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseCharacters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	numberCharacters    = "0123456789"
+	symbolCharacters    = "!@#$%^&*()-_=+[]{}:;,.?"
+)
+
+// PasswordPolicy is the password_policy block of a fields block, which
+// generates the field's password locally instead of following the template's
+// password requirement
+type PasswordPolicy struct {
+	Length            types.Int64  `tfsdk:"length"`
+	Lowercase         types.Bool   `tfsdk:"lowercase"`
+	Uppercase         types.Bool   `tfsdk:"uppercase"`
+	Numbers           types.Bool   `tfsdk:"numbers"`
+	Symbols           types.Bool   `tfsdk:"symbols"`
+	ExcludeCharacters types.String `tfsdk:"exclude_characters"`
+}
+
+// passwordPolicyBlock is the schema of the password_policy block
+func passwordPolicyBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Overrides the template's password requirement when the provider generates the password of this field, " +
+			"which it then does itself whatever password_generator is. The password has at least one character of each included set.",
+		Attributes: map[string]schema.Attribute{
+			"length": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The length of the password. Defaults to %d.", defaultGeneratedPasswordLength),
+			},
+			"lowercase": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Include lowercase letters. Defaults to true.",
+			},
+			"uppercase": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Include uppercase letters. Defaults to true.",
+			},
+			"numbers": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Include digits. Defaults to true.",
+			},
+			"symbols": schema.BoolAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Include the symbols %s. Defaults to true.", symbolCharacters),
+			},
+			"exclude_characters": schema.StringAttribute{
+				Optional:    true,
+				Description: "Characters never used in the password, e.g. ones the target system rejects.",
+			},
+		},
+	}
+}
+
+// requirement returns the password requirement the policy describes: each
+// included set, less the excluded characters, with a minimum of one character
+func (p *PasswordPolicy) requirement() (*passwordRequirement, error) {
+	length := defaultGeneratedPasswordLength
+	if !p.Length.IsNull() {
+		length = int(p.Length.ValueInt64())
+	}
+	requirement := &passwordRequirement{MinLength: length, MaxLength: length}
+
+	var allowed strings.Builder
+	sets := 0
+	for _, set := range []struct {
+		included   types.Bool
+		characters string
+	}{
+		{p.Lowercase, lowercaseCharacters},
+		{p.Uppercase, uppercaseCharacters},
+		{p.Numbers, numberCharacters},
+		{p.Symbols, symbolCharacters},
+	} {
+		if !set.included.IsNull() && !set.included.ValueBool() {
+			continue
+		}
+		characters := strings.Map(func(ch rune) rune {
+			if strings.ContainsRune(p.ExcludeCharacters.ValueString(), ch) {
+				return -1
+			}
+			return ch
+		}, set.characters)
+		if characters == "" {
+			continue
+		}
+		allowed.WriteString(characters)
+		rule := passwordRequirementRule{Minimum: 1}
+		rule.CharacterSet.Characters = characters
+		requirement.PasswordRequirementRules = append(requirement.PasswordRequirementRules, rule)
+		sets++
+	}
+	requirement.AllowedCharacterSet.Characters = allowed.String()
+
+	switch {
+	case sets == 0:
+		return nil, fmt.Errorf("password_policy leaves no characters to generate the password from")
+	case length < sets:
+		return nil, fmt.Errorf("password_policy length %d is too short for a character of each of its %d character sets", length, sets)
+	}
+	return requirement, nil
+}
+
+// validatePasswordPolicies checks the password_policy blocks of the fields
+// blocks whose arguments are known
+func validatePasswordPolicies(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var list types.List
+	diags.Append(config.GetAttribute(ctx, path.Root("fields"), &list)...)
+	if diags.HasError() || list.IsNull() || list.IsUnknown() {
+		return
+	}
+	var fields []SecretField
+	diags.Append(list.ElementsAs(ctx, &fields, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	for i, field := range fields {
+		policy := field.PasswordPolicy
+		if policy == nil || policy.Length.IsUnknown() || policy.Lowercase.IsUnknown() || policy.Uppercase.IsUnknown() ||
+			policy.Numbers.IsUnknown() || policy.Symbols.IsUnknown() || policy.ExcludeCharacters.IsUnknown() {
+			continue
+		}
+		if _, err := policy.requirement(); err != nil {
+			diags.AddAttributeError(path.Root("fields").AtListIndex(i).AtName("password_policy"), "Invalid Password Policy", err.Error())
+		}
+	}
+}
+
+// fieldPasswordPolicy returns the password_policy of the field of fields that
+// is the template field, or nil when it has none
+func fieldPasswordPolicy(fields []SecretField, templateField *server.SecretTemplateField) *PasswordPolicy {
+	for _, field := range fields {
+		name := field.FieldName.ValueString()
+		if strings.EqualFold(name, templateField.Name) || strings.EqualFold(name, templateField.FieldSlugName) {
+			return field.PasswordPolicy
+		}
+	}
+	return nil
+}
+
+// keepPasswordPolicies copies the password_policy of each field from src into
+// fields read back from Secret Server, which knows nothing of them
+func keepPasswordPolicies(fields []SecretField, src []SecretField) {
+	for i, field := range fields {
+		for _, srcField := range src {
+			if strings.EqualFold(srcField.FieldName.ValueString(), field.FieldName.ValueString()) {
+				fields[i].PasswordPolicy = srcField.PasswordPolicy
+				break
+			}
+		}
+	}
+}
+
+// passwordRequirement is a password requirement as returned by Secret Server
+type passwordRequirement struct {
+	ID                  int
+	MinLength           int
+	MaxLength           int
+	AllowedCharacterSet struct {
+		Characters string
+	}
+	PasswordRequirementRules []passwordRequirementRule
+}
+
+// passwordRequirementRule is the minimum number of characters of a set a
+// password requirement needs
+type passwordRequirementRule struct {
+	CharacterSet struct {
+		Characters string
+	}
+	Minimum int
+}
+
+// templatePasswordRequirements returns the password requirement ID of each
+// field of a template by field ID, which the SDK does not expose
+func (c *apiClient) templatePasswordRequirements(ctx context.Context, templateID int) (map[int]int, error) {
+	var template struct {
+		Fields []struct {
+			SecretTemplateFieldID int
+			PasswordRequirementID int
+		}
+	}
+	if err := c.do(ctx, "GET", fmt.Sprintf("secret-templates/%d", templateID), nil, &template); err != nil {
+		return nil, err
+	}
+	requirements := map[int]int{}
+	for _, field := range template.Fields {
+		requirements[field.SecretTemplateFieldID] = field.PasswordRequirementID
+	}
+	return requirements, nil
+}
+
+// passwordRequirement returns a password requirement
+func (c *apiClient) passwordRequirement(ctx context.Context, id int) (*passwordRequirement, error) {
+	requirement := new(passwordRequirement)
+	if err := c.do(ctx, "GET", fmt.Sprintf("password-requirements/%d", id), nil, requirement); err != nil {
+		return nil, err
+	}
+	return requirement, nil
+}
+
+// newPassword generates the value of a password field following the field's
+// password_policy, or else with the generator the resource selects
+func (r *TssSecretResource) newPassword(ctx context.Context, state *SecretResourceState, client sdkClient, template *server.SecretTemplate, field *server.SecretTemplateField) (string, error) {
+	if policy := fieldPasswordPolicy(state.Fields, field); policy != nil {
+		requirement, err := policy.requirement()
+		if err != nil {
+			return "", err
+		}
+		tflog.Debug(ctx, "Generating password from the field's password_policy", map[string]interface{}{
+			"field":  field.FieldSlugName,
+			"length": requirement.MinLength,
+		})
+		return generateLocalPassword(requirement)
+	}
+	switch generator := state.PasswordGenerator.ValueString(); generator {
+	case "", passwordGeneratorServer:
+		return client.GeneratePassword(field.FieldSlugName, template)
+	case passwordGeneratorLocal:
+		requirements, err := r.api.templatePasswordRequirements(ctx, template.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the password requirements of template %d: %w", template.ID, err)
+		}
+		requirement := &passwordRequirement{}
+		if id := requirements[field.SecretTemplateFieldID]; id > 0 {
+			if requirement, err = r.api.passwordRequirement(ctx, id); err != nil {
+				return "", fmt.Errorf("failed to read password requirement %d: %w", id, err)
+			}
+		}
+		tflog.Debug(ctx, "Generating password locally", map[string]interface{}{
+			"field":                   field.FieldSlugName,
+			"password_requirement_id": requirement.ID,
+		})
+		return generateLocalPassword(requirement)
+	default:
+		return "", fmt.Errorf("password_generator must be %q or %q, not %q", passwordGeneratorServer, passwordGeneratorLocal, generator)
+	}
+}
+
+// generateLocalPassword returns a password from the system CSPRNG meeting the
+// length, allowed characters and per character set minimums of requirement
+func generateLocalPassword(requirement *passwordRequirement) (string, error) {
+	allowed := requirement.AllowedCharacterSet.Characters
+	if allowed == "" {
+		allowed = defaultPasswordCharacters
+	}
+
+	// Each rule contributes its minimum number of characters from its set,
+	// limited to the allowed characters
+	var password []byte
+	for _, rule := range requirement.PasswordRequirementRules {
+		var set strings.Builder
+		for _, ch := range []byte(rule.CharacterSet.Characters) {
+			if strings.IndexByte(allowed, ch) >= 0 {
+				set.WriteByte(ch)
+			}
+		}
+		if rule.Minimum > 0 && set.Len() == 0 {
+			return "", fmt.Errorf("password requirement %d needs characters that it does not allow", requirement.ID)
+		}
+		for i := 0; i < rule.Minimum; i++ {
+			ch, err := randomByte(set.String())
+			if err != nil {
+				return "", err
+			}
+			password = append(password, ch)
+		}
+	}
+
+	length := max(defaultGeneratedPasswordLength, requirement.MinLength)
+	if requirement.MaxLength > 0 {
+		length = min(length, requirement.MaxLength)
+	}
+	if len(password) > length {
+		return "", fmt.Errorf("password requirement %d needs more characters than its maximum length of %d", requirement.ID, length)
+	}
+	for len(password) < length {
+		ch, err := randomByte(allowed)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, ch)
+	}
+
+	// Shuffle, so the characters required by the rules are not always first
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return string(password), nil
+}
+
+// randomByte returns a uniformly chosen byte of chars
+func randomByte(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n.Int64()], nil
+}
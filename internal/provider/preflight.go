@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// validateCredentials authenticates and looks up the current user, so a wrong
+// server_url or bad credentials fail Configure with a clear error rather than
+// the first resource failing partway through an apply
+func (c *apiClient) validateCredentials(ctx context.Context) error {
+	var current user
+	err := c.do(ctx, "GET", "users/current", nil, &current)
+	var impersonationErr *impersonationError
+	switch {
+	case errors.As(err, &impersonationErr):
+		return impersonationErr
+	case err == nil:
+		tflog.Debug(ctx, "Validated Secret Server credentials", map[string]interface{}{
+			"user_id":  current.ID,
+			"username": current.UserName,
+		})
+		return nil
+	case isCredentialError(err):
+		return fmt.Errorf("Secret Server at %s rejected the credentials: %w", c.baseURL(), err)
+	case errorStatusCode(err) == http.StatusNotFound:
+		return fmt.Errorf("%s does not look like a Secret Server URL, as it has no REST API: %w", c.baseURL(), err)
+	case errorStatusCode(err) != 0:
+		return fmt.Errorf("Secret Server at %s failed the credential check: %w", c.baseURL(), err)
+	}
+	return fmt.Errorf("unable to reach Secret Server at %s: %w", c.baseURL(), err)
+}
+
+// isCredentialError reports whether err is Secret Server refusing the
+// credentials: the token endpoint answers 400 to a bad grant, and the API 401
+// or 403
+func isCredentialError(err error) bool {
+	switch errorStatusCode(err) {
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	}
+	return false
+}
+
+// serverVersion returns the version of Secret Server, e.g. 11.7.000049
+func (c *apiClient) serverVersion(ctx context.Context) (string, error) {
+	var version struct {
+		Model struct {
+			Version string
+		}
+	}
+	if err := c.do(ctx, "GET", "version", nil, &version); err != nil {
+		return "", err
+	}
+	if version.Model.Version == "" {
+		return "", fmt.Errorf("Secret Server at %s did not report its version", c.baseURL())
+	}
+	return version.Model.Version, nil
+}
+
+// checkServerVersion fails unless Secret Server is at least version minimum,
+// so a configuration using APIs that older versions lack fails before changing
+// anything rather than with a 404 partway through an apply
+func (c *apiClient) checkServerVersion(ctx context.Context, minimum string) error {
+	version, err := c.serverVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to read the version of Secret Server at %s: %w", c.baseURL(), err)
+	}
+	tflog.Debug(ctx, "Read Secret Server version", map[string]interface{}{
+		"server_version":         version,
+		"minimum_server_version": minimum,
+	})
+	if compareVersions(version, minimum) < 0 {
+		return fmt.Errorf("Secret Server at %s is version %s, older than minimum_server_version %s; "+
+			"upgrade Secret Server or remove the resources that need the newer version", c.baseURL(), version, minimum)
+	}
+	return nil
+}
+
+// parseVersion splits a dotted version such as 11.7.000049 into its numbers
+func parseVersion(version string) ([]int, error) {
+	var numbers []int
+	for _, part := range strings.Split(strings.TrimSpace(version), ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%q is not a version of the form 11.7.000049", version)
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
+
+// compareVersions returns -1, 0 or 1 as version a is older than, the same as or
+// newer than b. Missing trailing numbers count as 0, so 11.7 equals 11.7.0.
+// Unparseable versions compare as equal.
+func compareVersions(a, b string) int {
+	x, errA := parseVersion(a)
+	y, errB := parseVersion(b)
+	if errA != nil || errB != nil {
+		return 0
+	}
+	for i := 0; i < len(x) || i < len(y); i++ {
+		var m, n int
+		if i < len(x) {
+			m = x[i]
+		}
+		if i < len(y) {
+			n = y[i]
+		}
+		switch {
+		case m < n:
+			return -1
+		case m > n:
+			return 1
+		}
+	}
+	return 0
+}
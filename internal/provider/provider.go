@@ -2,16 +2,21 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/DelineaXPM/tss-sdk-go/v2/server"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -19,6 +24,7 @@ import (
 var (
 	_ provider.Provider                       = &TssProvider{}
 	_ provider.ProviderWithEphemeralResources = (*TssProvider)(nil)
+	_ provider.ProviderWithFunctions          = (*TssProvider)(nil)
 )
 
 // Define the provider structure
@@ -29,12 +35,84 @@ type TssProvider struct {
 	version string
 }
 
+// TssProviderData is handed to data sources, resources and ephemeral resources
+// once the provider is configured
+type TssProviderData struct {
+	Server   *server.Server
+	API      *apiClient
+	Defaults callOptions
+	Logging  loggingConfig
+	// FingerprintKey keys the value fingerprints of secret fields; nil disables them
+	FingerprintKey []byte
+	// StateCipher decrypts the field values encrypted in state by earlier versions, if set
+	StateCipher *stateCipher
+	// Identities are the credentials of the auth_alias blocks by name
+	Identities map[string]providerIdentity
+	// Workspace marks the secrets created by this configuration, if set
+	Workspace string
+}
+
 // Define the provider schema model
 type TssProviderModel struct {
-	ServerURL types.String `tfsdk:"server_url"`
-	Username  types.String `tfsdk:"username"`
-	Password  types.String `tfsdk:"password"`
-	Domain    types.String `tfsdk:"domain"`
+	ServerURL       types.String           `tfsdk:"server_url"`
+	Username        types.String           `tfsdk:"username"`
+	Password        types.String           `tfsdk:"password"`
+	Domain          types.String           `tfsdk:"domain"`
+	Domains         types.List             `tfsdk:"domains"`
+	Token           types.String           `tfsdk:"token"`
+	ClientID        types.String           `tfsdk:"client_id"`
+	ClientSecret    types.String           `tfsdk:"client_secret"`
+	OnboardingRule  types.String           `tfsdk:"onboarding_rule"`
+	OnboardingKey   types.String           `tfsdk:"onboarding_key"`
+	OnboardingFile  types.String           `tfsdk:"onboarding_secret_file"`
+	Platform        types.Bool             `tfsdk:"platform"`
+	UseOIDC         types.Bool             `tfsdk:"use_oidc"`
+	OIDCTokenFile   types.String           `tfsdk:"oidc_token_file"`
+	OIDCAudience    types.String           `tfsdk:"oidc_audience"`
+	CACertFile      types.String           `tfsdk:"ca_cert_file"`
+	CACertPEM       types.String           `tfsdk:"ca_cert_pem"`
+	TLSSkipVerify   types.Bool             `tfsdk:"tls_skip_verify"`
+	TLSMinVersion   types.String           `tfsdk:"tls_min_version"`
+	TLSCiphers      types.List             `tfsdk:"tls_cipher_suites"`
+	HTTPProxy       types.String           `tfsdk:"http_proxy"`
+	HTTPSProxy      types.String           `tfsdk:"https_proxy"`
+	NoProxy         types.String           `tfsdk:"no_proxy"`
+	SOCKS5Proxy     types.String           `tfsdk:"socks5_proxy"`
+	SOCKS5Username  types.String           `tfsdk:"socks5_username"`
+	SOCKS5Password  types.String           `tfsdk:"socks5_password"`
+	RequestTimeout  types.String           `tfsdk:"request_timeout"`
+	ConnectTimeout  types.String           `tfsdk:"connect_timeout"`
+	Timeouts        *ProviderTimeoutsModel `tfsdk:"operation_timeouts"`
+	Retry           *RetryModel            `tfsdk:"retry"`
+	MaxConcurrent   types.Int64            `tfsdk:"max_concurrent_requests"`
+	RequestsPerSec  types.Float64          `tfsdk:"requests_per_second"`
+	LogLevel        types.String           `tfsdk:"log_level"`
+	RedactFields    types.List             `tfsdk:"redact_fields"`
+	Profile         types.String           `tfsdk:"profile"`
+	CredentialsFile types.String           `tfsdk:"credentials_file"`
+	Fingerprints    types.Bool             `tfsdk:"value_fingerprints"`
+	FingerprintKey  types.String           `tfsdk:"value_fingerprint_key"`
+	StatePassphrase types.String           `tfsdk:"state_encryption_passphrase"`
+	AbortUnhealthy  types.Bool             `tfsdk:"abort_if_unhealthy"`
+	PageSize        types.Int64            `tfsdk:"api_page_size"`
+	SkipValidation  types.Bool             `tfsdk:"skip_validation_during_plan"`
+	ValidateCreds   types.Bool             `tfsdk:"validate_credentials"`
+	AuditLogPath    types.String           `tfsdk:"audit_log_path"`
+	MaxAPICalls     types.Int64            `tfsdk:"max_api_calls"`
+	OTelEndpoint    types.String           `tfsdk:"otel_endpoint"`
+	ImpersonateUser types.String           `tfsdk:"impersonate_user"`
+	AuthAliases     []AuthAliasModel       `tfsdk:"auth_alias"`
+	ReadReplicaURL  types.String           `tfsdk:"read_replica_url"`
+	MinVersion      types.String           `tfsdk:"minimum_server_version"`
+	Workspace       types.String           `tfsdk:"workspace"`
+}
+
+// ProviderTimeoutsModel holds the per-operation overrides of request_timeout
+type ProviderTimeoutsModel struct {
+	Read   types.String `tfsdk:"read"`
+	Create types.String `tfsdk:"create"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
 }
 
 // Metadata returns the provider type name
@@ -53,23 +131,268 @@ func (p *TssProvider) Schema(ctx context.Context, req provider.SchemaRequest, re
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"server_url": schema.StringAttribute{
-				Required:    true,
-				Description: "The Secret Server base URL e.g. https://localhost/SecretServer",
+				Optional:    true,
+				Description: "The Secret Server base URL e.g. https://localhost/SecretServer. Required unless set with the TSS_SERVER_URL or SS_SERVER_URL environment variable",
 			},
 			"username": schema.StringAttribute{
-				Required:    true,
-				Description: "The username of the Secret Server User to connect as",
+				Optional:    true,
+				Description: "The username of the Secret Server User to connect as. Not required when token or client_id is set. May also be set with the TSS_USER or SS_USERNAME environment variable",
 			},
 			"password": schema.StringAttribute{
-				Required:    true,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The password of the Secret Server User. Not required when token or client_id is set. Accepts ephemeral values. May also be set with the TSS_PASSWORD or SS_PASSWORD environment variable",
+			},
+			"audit_log_path": schema.StringAttribute{
+				Optional: true,
+				Description: "A file to append a JSON line to for every Secret Server call, with the method, path, secret ID, status and duration. " +
+					"Request bodies are recorded with the values of redact_fields, such as itemvalue and password, replaced; responses are never recorded. " +
+					"May also be set with the TSS_AUDIT_LOG_PATH environment variable",
+			},
+			"api_page_size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The number of records requested per page when listing secrets or discovery results. Larger pages need fewer requests for large folders. Defaults to 100",
+			},
+			"abort_if_unhealthy": schema.BoolAttribute{
+				Optional: true,
+				Description: "Check the health of Secret Server before the first change of the run, and fail every change when it reports itself degraded, " +
+					"so an apply stops before it starts rather than partway through",
+			},
+			"skip_validation_during_plan": schema.BoolAttribute{
+				Optional: true,
+				Description: "Plan without contacting Secret Server while the connection or credentials are unknown, e.g. because they come from another resource's output. " +
+					"Resources keep their prior state and data sources fail until the values are known. Not needed when Terraform supports deferred actions. " +
+					"May also be set with the TSS_SKIP_VALIDATION_DURING_PLAN environment variable",
+			},
+			"validate_credentials": schema.BoolAttribute{
+				Optional: true,
+				Description: "Authenticate and look up the current user while configuring the provider, so a wrong server_url or bad credentials fail before any resource is read or changed. " +
+					"May also be set with the TSS_VALIDATE_CREDENTIALS environment variable",
+			},
+			"minimum_server_version": schema.StringAttribute{
+				Optional: true,
+				Description: "The oldest version of Secret Server the configuration works with, e.g. 11.4. When set, the provider reads the version of Secret Server while configuring " +
+					"and fails before anything is read or changed when it is older, rather than with a 404 from an API the server lacks partway through an apply. " +
+					"May also be set with the TSS_MINIMUM_SERVER_VERSION environment variable",
+			},
+			"workspace": schema.StringAttribute{
+				Optional: true,
+				Description: "A name for the Terraform workspace, e.g. prod-network. Secrets created by the provider are marked as managed by it with the managed-by metadata field, " +
+					"and secrets marked by another workspace are not updated unless their allow_takeover is set, so two workspaces cannot silently fight over a secret. " +
+					"Not set by default. May also be set with the TSS_WORKSPACE environment variable, e.g. from TFC_WORKSPACE_NAME in HCP Terraform runs",
+			},
+			"value_fingerprints": schema.BoolAttribute{
+				Optional: true,
+				Description: "Record a keyed hash of each secret field value, so plans show whether a sensitive value changes without showing it. " +
+					"Requires value_fingerprint_key. May also be set with the TSS_VALUE_FINGERPRINTS environment variable",
+			},
+			"value_fingerprint_key": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "The secret key of the value fingerprints. Without it a fingerprint could be checked against guessed values, so keep it out of " +
+					"the configuration and state, e.g. in the TSS_VALUE_FINGERPRINT_KEY environment variable. Changing it changes every fingerprint",
+			},
+			"state_encryption_passphrase": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "The passphrase field values were encrypted in state with by earlier versions of the provider, which are decrypted to write them back. " +
+					"New values are not encrypted. May also be set with the TSS_STATE_ENCRYPTION_PASSPHRASE environment variable",
+				DeprecationMessage: "Values are no longer encrypted in state, as the encrypted value replaced the real one for every reference to it. " +
+					"Set store_value_in_state = false or itemvalue_wo on the fields to keep their values out of state.",
+			},
+			"profile": schema.StringAttribute{
+				Optional:    true,
+				Description: "The profile of the shared credentials file to read settings from. Defaults to \"default\". May also be set with the TSS_PROFILE environment variable",
+			},
+			"credentials_file": schema.StringAttribute{
+				Optional: true,
+				Description: "The shared credentials file, an INI file of [profile] sections holding server_url, username, password, domain, token, client_id and client_secret. " +
+					"Its settings apply when neither the configuration nor the environment sets them. Defaults to ~/.tss/credentials. May also be set with the TSS_CREDENTIALS_FILE environment variable",
+			},
+			"token": schema.StringAttribute{
+				Optional:    true,
 				Sensitive:   true,
-				Description: "The password of the Secret Server User",
+				Description: "A pre-issued Secret Server REST API access token to authenticate with instead of username and password. Accepts ephemeral values. May also be set with the TSS_TOKEN environment variable",
+			},
+			"platform": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether server_url is a Delinea Platform tenant, e.g. https://example.delinea.app. Platform tenants authenticate " +
+					"username and password as a service user through the platform OAuth endpoint and serve the API from the tenant's default vault. " +
+					"Detected from server_url when not set",
+			},
+			"use_oidc": schema.BoolAttribute{
+				Optional: true,
+				Description: "Authenticate by exchanging the OIDC ID token of the CI job for an access token, instead of with a password. " +
+					"Without oidc_token_file, the token is requested from GitHub Actions. May also be set with the TSS_USE_OIDC environment variable",
+			},
+			"oidc_token_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "A file holding the OIDC ID token to exchange, e.g. one written from a GitLab CI id_tokens variable. Implies use_oidc. May also be set with the TSS_OIDC_TOKEN_FILE environment variable",
+			},
+			"oidc_audience": schema.StringAttribute{
+				Optional:    true,
+				Description: "The audience to request the GitHub Actions ID token for. May also be set with the TSS_OIDC_AUDIENCE environment variable",
+			},
+			"client_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "The client ID of an SDK client account to authenticate as instead of a user. May also be set with the TSS_CLIENT_ID environment variable",
+			},
+			"client_secret": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The client secret of the SDK client account. Accepts ephemeral values. May also be set with the TSS_CLIENT_SECRET environment variable",
+			},
+			"onboarding_rule": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name of an SDK client onboarding rule to register client_id under when no client_secret is given. May also be set with the TSS_ONBOARDING_RULE environment variable",
+			},
+			"onboarding_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The key of the onboarding rule, when the rule requires one. May also be set with the TSS_ONBOARDING_KEY environment variable",
+			},
+			"onboarding_secret_file": schema.StringAttribute{
+				Optional: true,
+				Description: "The file the client secret issued when registering client_id under onboarding_rule is written to, readable by the owner only. " +
+					"Later runs read the secret from it rather than registering the client again. Required with onboarding_rule. " +
+					"May also be set with the TSS_ONBOARDING_SECRET_FILE environment variable",
 			},
 			"domain": schema.StringAttribute{
 				Optional:    true,
-				Description: "Domain of the Secret Server user",
+				Description: "Domain of the Secret Server user. May also be set with the TSS_DOMAIN or SS_DOMAIN environment variable",
+			},
+			"domains": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Domains to try in order when authenticating with a username and password, for users spread over several Active Directory domains. " +
+					"domain, when set, is tried first; the next domain is only tried when one rejects the credentials. " +
+					"May also be set with the TSS_DOMAINS environment variable as a comma separated list",
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "A PEM file of CA certificates to trust in addition to the system trust store, e.g. an internal CA. May also be set with the TSS_CA_CERT_FILE environment variable",
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded CA certificates to trust in addition to the system trust store",
+			},
+			"tls_min_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "The minimum TLS version to connect to Secret Server with: 1.2 or 1.3. Defaults to 1.2. May also be set with the TSS_TLS_MIN_VERSION environment variable",
+			},
+			"tls_cipher_suites": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The TLS 1.2 cipher suites to offer, by their IANA names, e.g. TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384. " +
+					"Only suites considered secure are accepted. TLS 1.3 suites are not configurable. Defaults to the Go defaults",
+			},
+			"tls_skip_verify": schema.BoolAttribute{
+				Optional: true,
+				Description: "Skip verification of the server's TLS certificate. Only for test instances with self-signed certificates; " +
+					"it exposes credentials to anyone who can intercept the connection. May also be set with the TSS_TLS_SKIP_VERIFY environment variable",
+			},
+			"http_proxy": schema.StringAttribute{
+				Optional:    true,
+				Description: "The proxy to send http requests through. Defaults to the HTTP_PROXY environment variable",
+			},
+			"https_proxy": schema.StringAttribute{
+				Optional:    true,
+				Description: "The proxy to send https requests through. Defaults to the HTTPS_PROXY environment variable",
+			},
+			"no_proxy": schema.StringAttribute{
+				Optional:    true,
+				Description: "A comma separated list of hosts, domains and CIDR ranges to connect to directly instead of through the proxy. Defaults to the NO_PROXY environment variable",
+			},
+			"socks5_proxy": schema.StringAttribute{
+				Optional: true,
+				Description: "A SOCKS5 proxy to connect through, as host:port or socks5://[user:password@]host:port. " +
+					"Takes precedence over the HTTP proxy settings. May also be set with the TSS_SOCKS5_PROXY environment variable",
+			},
+			"socks5_username": schema.StringAttribute{
+				Optional:    true,
+				Description: "The username to authenticate to the SOCKS5 proxy with. May also be set with the TSS_SOCKS5_USERNAME environment variable",
+			},
+			"socks5_password": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The password to authenticate to the SOCKS5 proxy with. May also be set with the TSS_SOCKS5_PASSWORD environment variable",
+			},
+			"read_replica_url": schema.StringAttribute{
+				Optional: true,
+				Description: "The base URL of a read-only replica of Secret Server, e.g. https://replica.example.com/SecretServer, to send the reads of data sources to. " +
+					"Resources, including their refresh, still use server_url, as do data sources with an auth_alias. " +
+					"The replica is authenticated to with the provider's credentials. Not supported on the Delinea Platform. " +
+					"May also be set with the TSS_READ_REPLICA_URL environment variable",
+			},
+			"request_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "The maximum time a single Secret Server request may take, e.g. \"2m\". Requests are not limited by default",
+			},
+			"connect_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "The maximum time to wait for a connection to Secret Server, e.g. \"10s\". Defaults to 30s",
+			},
+			"impersonate_user": schema.StringAttribute{
+				Optional: true,
+				Description: "The username of a user to make reads as, so Secret Server's audit attributes them to that user rather than the authenticated account. " +
+					"Writes are still made as the authenticated account, which needs a role allowing impersonation. " +
+					"May also be set with the TSS_IMPERSONATE_USER environment variable",
+			},
+			"otel_endpoint": schema.StringAttribute{
+				Optional: true,
+				Description: "The OTLP/HTTP endpoint of an OpenTelemetry collector, e.g. http://localhost:4318, to export a span for every Secret Server call to, " +
+					"with its path, secret ID, status and retries. Spans are exported each second, and the remaining ones when the provider exits. " +
+					"May also be set with the OTEL_EXPORTER_OTLP_ENDPOINT environment variable",
+			},
+			"operation_timeouts": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Overrides request_timeout for the requests made while reading, creating, updating or deleting, e.g. to allow for large file attachments",
+				Attributes: map[string]schema.Attribute{
+					"read": schema.StringAttribute{
+						Optional:    true,
+						Description: "The request timeout while reading data sources and refreshing resources",
+					},
+					"create": schema.StringAttribute{
+						Optional:    true,
+						Description: "The request timeout while creating resources",
+					},
+					"update": schema.StringAttribute{
+						Optional:    true,
+						Description: "The request timeout while updating resources",
+					},
+					"delete": schema.StringAttribute{
+						Optional:    true,
+						Description: "The request timeout while deleting resources",
+					},
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The maximum number of Secret Server calls in flight at once, across all resources and data sources. Unlimited by default",
+			},
+			"max_api_calls": schema.Int64Attribute{
+				Optional: true,
+				Description: "The maximum number of Secret Server calls this provider configuration makes in a run, counting every retry. " +
+					"Calls beyond it fail, so a runaway configuration stops instead of flooding a shared server. Unlimited by default",
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional:    true,
+				Description: "The maximum rate at which Secret Server calls are started, across all resources and data sources. Unlimited by default",
+			},
+			"log_level": schema.StringAttribute{
+				Optional:    true,
+				Description: "The level of the provider's own log output: trace, debug, info, warn, error or off. Defaults to the level Terraform runs with",
+			},
+			"redact_fields": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Additional log field names whose values are always masked. Credentials and secret values (e.g. password, token, client_secret, itemvalue and value) and identifying values (server_url, username and filename) are always masked, as are bearer tokens, JWTs, private keys and the configured credentials wherever they appear",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"retry":      providerRetryBlock(),
+			"auth_alias": providerAuthAliasBlock(),
+		},
 	}
 }
 
@@ -89,13 +412,100 @@ func (p *TssProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		return
 	}
 
+	// Configure logging first so the rest of Configure honors it
+	logging := defaultLoggingConfig
+	if level, ok := parseLogLevel(data.LogLevel.ValueString()); ok {
+		logging.Level = level
+	} else {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("log_level"),
+			"Invalid Log Level",
+			fmt.Sprintf("%q is not one of trace, debug, info, warn, error or off.", data.LogLevel.ValueString()),
+		)
+	}
+	if !data.RedactFields.IsNull() && !data.RedactFields.IsUnknown() {
+		var redactFields []string
+		resp.Diagnostics.Append(data.RedactFields.ElementsAs(ctx, &redactFields, false)...)
+		logging.RedactFields = append(append([]string{}, defaultRedactFields...), redactFields...)
+	}
+	ctx = logging.context(ctx)
+
+	valueFingerprints := os.Getenv("TSS_VALUE_FINGERPRINTS") == "true"
+	if !data.Fingerprints.IsNull() {
+		valueFingerprints = data.Fingerprints.ValueBool()
+	}
+	fingerprintKey := os.Getenv("TSS_VALUE_FINGERPRINT_KEY")
+	if data.FingerprintKey.ValueString() != "" {
+		fingerprintKey = data.FingerprintKey.ValueString()
+	}
+	var fingerprints []byte
+	if valueFingerprints {
+		if fingerprintKey == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("value_fingerprint_key"),
+				"Missing Value Fingerprint Key",
+				"value_fingerprints is enabled, but no value_fingerprint_key was found in the TSS_VALUE_FINGERPRINT_KEY environment "+
+					"variable or the provider configuration block. Fingerprints are keyed with it so they cannot be checked against guessed values.",
+			)
+			return
+		}
+		fingerprints = []byte(fingerprintKey)
+	}
+
+	statePassphrase := os.Getenv("TSS_STATE_ENCRYPTION_PASSPHRASE")
+	if !data.StatePassphrase.IsNull() {
+		statePassphrase = data.StatePassphrase.ValueString()
+	}
+	var stateEncryption *stateCipher
+	if statePassphrase != "" {
+		var err error
+		if stateEncryption, err = newStateCipher(statePassphrase); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("state_encryption_passphrase"), "Invalid State Encryption Passphrase", err.Error())
+			return
+		}
+	}
+
+	// Connection settings that come from another resource are unknown until
+	// apply. Let Terraform defer everything that depends on the provider when it
+	// can, or plan offline when asked to.
+	if unknown := unknownConnectionAttributes(data); len(unknown) > 0 {
+		skipValidation := os.Getenv("TSS_SKIP_VALIDATION_DURING_PLAN") == "true"
+		if !data.SkipValidation.IsNull() && !data.SkipValidation.IsUnknown() {
+			skipValidation = data.SkipValidation.ValueBool()
+		}
+
+		switch {
+		case req.ClientCapabilities.DeferralAllowed:
+			tflog.Info(ctx, "Provider configuration is unknown, deferring", map[string]interface{}{
+				"unknown": unknown,
+			})
+			resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+			return
+		case skipValidation:
+			tflog.Warn(ctx, "Provider configuration is unknown, planning without contacting Secret Server", map[string]interface{}{
+				"unknown": unknown,
+			})
+			providerData := &TssProviderData{
+				Server:         &server.Server{},
+				API:            newOfflineAPIClient(),
+				Logging:        logging,
+				FingerprintKey: fingerprints,
+				StateCipher:    stateEncryption,
+			}
+			resp.DataSourceData = providerData
+			resp.ResourceData = providerData
+			resp.EphemeralResourceData = providerData
+			return
+		}
+	}
+
 	// Check configuration data provided are known values.
 	if data.ServerURL.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("server_url"),
 			"Unknown TSS API Server URL",
 			"The provider cannot create the TSS API client as there is an unknown configuration value for the TSS API Server URL. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the TSS_SERVER_URL environment variable.",
+				"Either target apply the source of the value first, set skip_validation_during_plan, set the value statically in the configuration, or use the TSS_SERVER_URL environment variable.",
 		)
 	}
 
@@ -104,30 +514,76 @@ func (p *TssProvider) Configure(ctx context.Context, req provider.ConfigureReque
 			path.Root("username"),
 			"Unknown TSS API Username",
 			"The provider cannot create the TSS API client as there is an unknown configuration value for the TSS API Username. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the TSS_USER environment variable.",
+				"Either target apply the source of the value first, set skip_validation_during_plan, set the value statically in the configuration, or use the TSS_USER environment variable.",
 		)
 	}
 
-	if data.Username.IsUnknown() {
+	if data.Password.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("password"),
 			"Unknown TSS API Password",
 			"The provider cannot create the TSS API client as there is an unknown configuration value for the TSS API Password. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the TSS_PASSWORD environment variable.",
+				"Either target apply the source of the value first, set skip_validation_during_plan, set the value statically in the configuration, or use the TSS_PASSWORD environment variable.",
+		)
+	}
+
+	if data.Token.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Unknown TSS API Token",
+			"The provider cannot create the TSS API client as there is an unknown configuration value for the TSS API Token. "+
+				"Either target apply the source of the value first, set skip_validation_during_plan, set the value statically in the configuration, or use the TSS_TOKEN environment variable.",
 		)
 	}
 
 	// Default values to environment variables, but override with provider configuration values if set.
-	serverUrl := os.Getenv("TSS_SERVER_URL")
-	username := os.Getenv("TSS_USER")
-	password := os.Getenv("TSS_PASSWORD")
-	domain := os.Getenv("TSS_DOMAIN")
+	serverUrl, envCredentials := environmentCredentials()
+	username := envCredentials.Username
+	password := envCredentials.Password
+	domain := envCredentials.Domain
+	domains := parseDomains(os.Getenv("TSS_DOMAINS"))
+	token := envCredentials.Token
+	clientID := os.Getenv("TSS_CLIENT_ID")
+	clientSecret := os.Getenv("TSS_CLIENT_SECRET")
+	onboardingRule := os.Getenv("TSS_ONBOARDING_RULE")
+	onboardingKey := os.Getenv("TSS_ONBOARDING_KEY")
+	onboardingSecretFile := os.Getenv("TSS_ONBOARDING_SECRET_FILE")
+	useOIDC := os.Getenv("TSS_USE_OIDC") == "true"
+	oidcTokenFile := os.Getenv("TSS_OIDC_TOKEN_FILE")
+	oidcAudience := os.Getenv("TSS_OIDC_AUDIENCE")
+
+	// The shared credentials file supplies what neither the environment nor the
+	// configuration sets
+	credentialsFile := os.Getenv("TSS_CREDENTIALS_FILE")
+	if data.CredentialsFile.ValueString() != "" {
+		credentialsFile = data.CredentialsFile.ValueString()
+	}
+	profileName := os.Getenv("TSS_PROFILE")
+	if data.Profile.ValueString() != "" {
+		profileName = data.Profile.ValueString()
+	}
+	profile, err := loadCredentialsProfile(credentialsFile, profileName)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Invalid Credentials Profile",
+			fmt.Sprintf("The provider could not read its settings from the shared credentials file: %s", err),
+		)
+		return
+	}
+	if profile != nil {
+		tflog.Debug(ctx, "Using shared credentials file profile", map[string]interface{}{
+			"profile": profileName,
+		})
+	}
 
 	tflog.Debug(ctx, "Checking environment variables", map[string]interface{}{
 		"has_server_url": serverUrl != "",
 		"has_username":   username != "",
 		"has_password":   password != "",
 		"has_domain":     domain != "",
+		"has_token":      token != "",
+		"has_client_id":  clientID != "",
 	})
 
 	// Check configuration data, which should take precedence over environment variable data, if found.
@@ -147,6 +603,57 @@ func (p *TssProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		tflog.Debug(ctx, "Using domain from provider configuration")
 		domain = data.Domain.ValueString()
 	}
+	if !data.Domains.IsNull() && !data.Domains.IsUnknown() {
+		domains = nil
+		resp.Diagnostics.Append(data.Domains.ElementsAs(ctx, &domains, false)...)
+	}
+	if data.Token.ValueString() != "" {
+		tflog.Debug(ctx, "Using token from provider configuration")
+		token = data.Token.ValueString()
+	}
+	if data.ClientID.ValueString() != "" {
+		tflog.Debug(ctx, "Using client ID from provider configuration")
+		clientID = data.ClientID.ValueString()
+	}
+	if data.ClientSecret.ValueString() != "" {
+		clientSecret = data.ClientSecret.ValueString()
+	}
+	if data.OnboardingRule.ValueString() != "" {
+		onboardingRule = data.OnboardingRule.ValueString()
+	}
+	if data.OnboardingKey.ValueString() != "" {
+		onboardingKey = data.OnboardingKey.ValueString()
+	}
+	if data.OnboardingFile.ValueString() != "" {
+		onboardingSecretFile = data.OnboardingFile.ValueString()
+	}
+	if !data.UseOIDC.IsNull() {
+		useOIDC = data.UseOIDC.ValueBool()
+	}
+	if data.OIDCTokenFile.ValueString() != "" {
+		oidcTokenFile = data.OIDCTokenFile.ValueString()
+	}
+	if data.OIDCAudience.ValueString() != "" {
+		oidcAudience = data.OIDCAudience.ValueString()
+	}
+	useOIDC = useOIDC || oidcTokenFile != ""
+
+	// A profile's credentials are only used when no others are set, so that they
+	// never take precedence over credentials of another kind
+	profile.fill("server_url", &serverUrl)
+	if username == "" && password == "" && token == "" && clientID == "" && !useOIDC {
+		profile.fill("username", &username)
+		profile.fill("password", &password)
+		profile.fill("domain", &domain)
+		profile.fill("token", &token)
+		profile.fill("client_id", &clientID)
+		profile.fill("client_secret", &clientSecret)
+	}
+
+	// Mask the credentials wherever they appear in the rest of the log output
+	logging = logging.withSensitiveValues(password, token, clientSecret, onboardingKey, fingerprintKey,
+		os.Getenv("TSS_SOCKS5_PASSWORD"), data.SOCKS5Password.ValueString())
+	ctx = logging.context(ctx)
 
 	// Log the configuration values
 	tflog.Info(ctx, "Provider configuration values retrieved", map[string]interface{}{
@@ -161,30 +668,60 @@ func (p *TssProvider) Configure(ctx context.Context, req provider.ConfigureReque
 			path.Root("server_url"),
 			"Missing Server URL Configuration",
 			"While configuring the provider, the Server URL was not found in "+
-				"the TSS_SERVER_URL environment variable or provider "+
+				"the TSS_SERVER_URL or SS_SERVER_URL environment variable or provider "+
 				"configuration block server_url attribute.",
 		)
 	}
 
-	if username == "" {
+	// A token or an SDK client account replaces the password grant, so the user's
+	// credentials are not needed
+	userless := token != "" || clientID != "" || useOIDC
+	switch {
+	case userless:
+	case username == "" && password == "":
+		tflog.Error(ctx, "Missing credentials configuration")
+		resp.Diagnostics.AddError(
+			"Missing Credentials Configuration",
+			"While configuring the provider, no credentials were found in the provider configuration block or "+
+				"environment variables. Set username and password (TSS_USER and TSS_PASSWORD), token (TSS_TOKEN), "+
+				"client_id (TSS_CLIENT_ID) or use_oidc (TSS_USE_OIDC), or set them in a profile of the shared credentials file.",
+		)
+	case username == "":
 		tflog.Error(ctx, "Missing username configuration")
 		resp.Diagnostics.AddAttributeError(
 			path.Root("username"),
 			"Missing Username Configuration",
-			"While configuring the provider, the username was not found in "+
-				"the TSS_USERNAME environment variable or provider "+
-				"configuration block username attribute.",
+			"While configuring the provider, a password was found but the username was not found in "+
+				"the TSS_USER environment variable or provider configuration block username attribute.",
 		)
-	}
-
-	if password == "" {
+	case password == "":
 		tflog.Error(ctx, "Missing password configuration")
 		resp.Diagnostics.AddAttributeError(
 			path.Root("password"),
 			"Missing Password Configuration",
-			"While configuring the provider, the password was not found in "+
-				"the TSS_PASSWORD environment variable or provider "+
-				"configuration block password attribute.",
+			"While configuring the provider, a username was found but the password was not found in "+
+				"the TSS_PASSWORD environment variable or provider configuration block password attribute.",
+		)
+	}
+
+	if clientID != "" && clientSecret == "" && onboardingRule == "" {
+		tflog.Error(ctx, "Missing client secret configuration")
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_secret"),
+			"Missing Client Secret Configuration",
+			"While configuring the provider, client_id was set but neither the client secret nor an onboarding rule "+
+				"to register the client under was found in the TSS_CLIENT_SECRET or TSS_ONBOARDING_RULE environment "+
+				"variables or the provider configuration block.",
+		)
+	}
+	if clientID != "" && clientSecret == "" && onboardingRule != "" && onboardingSecretFile == "" {
+		tflog.Error(ctx, "Missing onboarding secret file configuration")
+		resp.Diagnostics.AddAttributeError(
+			path.Root("onboarding_secret_file"),
+			"Missing Onboarding Secret File Configuration",
+			"While configuring the provider, client_id is to be registered under an onboarding rule, but no file to keep "+
+				"the issued client secret in was found in the TSS_ONBOARDING_SECRET_FILE environment variable or the provider "+
+				"configuration block. Without it the client would be registered again on every run.",
 		)
 	}
 
@@ -192,6 +729,34 @@ func (p *TssProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		return
 	}
 
+	tlsConfig := tlsSettings{
+		CACertFile: os.Getenv("TSS_CA_CERT_FILE"),
+		CACertPEM:  data.CACertPEM.ValueString(),
+	}
+	if data.CACertFile.ValueString() != "" {
+		tlsConfig.CACertFile = data.CACertFile.ValueString()
+	}
+	tlsConfig.MinVersion = os.Getenv("TSS_TLS_MIN_VERSION")
+	if data.TLSMinVersion.ValueString() != "" {
+		tlsConfig.MinVersion = data.TLSMinVersion.ValueString()
+	}
+	if !data.TLSCiphers.IsNull() && !data.TLSCiphers.IsUnknown() {
+		resp.Diagnostics.Append(data.TLSCiphers.ElementsAs(ctx, &tlsConfig.CipherSuites, false)...)
+	}
+	tlsConfig.SkipVerify = os.Getenv("TSS_TLS_SKIP_VERIFY") == "true"
+	if !data.TLSSkipVerify.IsNull() {
+		tlsConfig.SkipVerify = data.TLSSkipVerify.ValueBool()
+	}
+	if tlsConfig.SkipVerify {
+		tflog.Warn(ctx, "TLS certificate verification is disabled")
+		resp.Diagnostics.AddWarning(
+			"TLS Certificate Verification Disabled",
+			"tls_skip_verify is set, so the provider does not verify the certificate of "+serverUrl+". "+
+				"Anyone able to intercept the connection can read the credentials and secrets it carries. "+
+				"Only use this against test instances; trust a private CA with ca_cert_file or ca_cert_pem instead.",
+		)
+	}
+
 	// Create the server configuration
 	serverConfig := &server.Configuration{
 		ServerURL: serverUrl,
@@ -199,14 +764,108 @@ func (p *TssProvider) Configure(ctx context.Context, req provider.ConfigureReque
 			Username: username,
 			Password: password,
 			Domain:   domain,
+			Token:    token,
 		},
 	}
 
+	// The TLS configuration applies to this configuration's transport only,
+	// rather than to the SDK's default transport
+	if tlsConfig.isSet() {
+		if _, err := tlsConfig.config(); err != nil {
+			tflog.Error(ctx, "Failed to build TLS configuration", map[string]interface{}{
+				"error": err.Error(),
+			})
+			resp.Diagnostics.AddError("Invalid TLS Configuration", err.Error())
+			return
+		}
+	}
+
+	defaults := defaultCallOptions
+	if d, ok := parseDuration(&resp.Diagnostics, path.Root("request_timeout"), data.RequestTimeout); ok {
+		defaults.Timeout = d
+	}
+	if data.Timeouts != nil {
+		defaults.OperationTimeouts = map[string]time.Duration{}
+		for operation, value := range map[string]types.String{
+			"read":   data.Timeouts.Read,
+			"create": data.Timeouts.Create,
+			"update": data.Timeouts.Update,
+			"delete": data.Timeouts.Delete,
+		} {
+			if d, ok := parseDuration(&resp.Diagnostics, path.Root("operation_timeouts").AtName(operation), value); ok {
+				defaults.OperationTimeouts[operation] = d
+			}
+		}
+	}
+	defaults.Retry = defaults.Retry.withOverrides(&resp.Diagnostics, data.Retry)
+	if !data.MaxConcurrent.IsNull() && data.MaxConcurrent.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("max_concurrent_requests"), "Invalid Rate Limit", "max_concurrent_requests must be at least 1.")
+	}
+	if !data.MaxAPICalls.IsNull() && data.MaxAPICalls.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("max_api_calls"), "Invalid Call Budget", "max_api_calls must be at least 1.")
+	}
+	if !data.PageSize.IsNull() && data.PageSize.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("api_page_size"), "Invalid Page Size", "api_page_size must be at least 1.")
+	}
+	if !data.RequestsPerSec.IsNull() && data.RequestsPerSec.ValueFloat64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("requests_per_second"), "Invalid Rate Limit", "requests_per_second must be greater than 0.")
+	}
+	connectTimeout, _ := parseDuration(&resp.Diagnostics, path.Root("connect_timeout"), data.ConnectTimeout)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Like the TLS configuration, the proxy applies to this configuration's
+	// transport, which both the SDK and the api client send requests through
+	proxyConfig := proxySettings{
+		HTTPProxy:      data.HTTPProxy.ValueString(),
+		HTTPSProxy:     data.HTTPSProxy.ValueString(),
+		NoProxy:        data.NoProxy.ValueString(),
+		SOCKS5Proxy:    os.Getenv("TSS_SOCKS5_PROXY"),
+		SOCKS5Username: os.Getenv("TSS_SOCKS5_USERNAME"),
+		SOCKS5Password: os.Getenv("TSS_SOCKS5_PASSWORD"),
+	}
+	if data.SOCKS5Proxy.ValueString() != "" {
+		proxyConfig.SOCKS5Proxy = data.SOCKS5Proxy.ValueString()
+	}
+	if data.SOCKS5Username.ValueString() != "" {
+		proxyConfig.SOCKS5Username = data.SOCKS5Username.ValueString()
+	}
+	if data.SOCKS5Password.ValueString() != "" {
+		proxyConfig.SOCKS5Password = data.SOCKS5Password.ValueString()
+	}
+	transport, err := transports.transport(transportSettings{
+		TLS:            tlsConfig,
+		Proxy:          proxyConfig,
+		ConnectTimeout: connectTimeout,
+	})
+	if err != nil {
+		tflog.Error(ctx, "Failed to configure proxy", map[string]interface{}{
+			"error": err.Error(),
+		})
+		resp.Diagnostics.AddAttributeError(path.Root("socks5_proxy"), "Invalid Proxy Configuration", err.Error())
+		return
+	}
+	if err := transports.route(serverUrl, transport); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("server_url"), "Conflicting Connection Settings", err.Error())
+		return
+	}
+	if proxyConfig.isSet() {
+		tflog.Debug(ctx, "Configured proxy", map[string]interface{}{
+			"http_proxy":           proxyConfig.HTTPProxy,
+			"https_proxy":          proxyConfig.HTTPSProxy,
+			"no_proxy":             proxyConfig.NoProxy,
+			"socks5":               proxyConfig.SOCKS5Proxy != "",
+			"socks5_authenticated": proxyConfig.SOCKS5Username != "",
+		})
+	}
+
 	tflog.Debug(ctx, "Final configuration values", map[string]interface{}{
 		"server_url":   serverUrl,
 		"username":     username,
 		"has_password": password != "",
 		"domain":       domain,
+		"auth_method":  authMethod(token, clientID, useOIDC),
 	})
 
 	// Create the server client
@@ -230,9 +889,210 @@ func (p *TssProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		"username":   username,
 	})
 
-	resp.DataSourceData = tssClient
-	resp.ResourceData = tssClient
-	resp.EphemeralResourceData = tssClient
+	api := newAPIClient(tssClient)
+	api.useTransport(transport)
+	api.defaults = defaults
+	api.limiter = newRequestLimiter(int(data.MaxConcurrent.ValueInt64()), data.RequestsPerSec.ValueFloat64())
+	api.budget = newCallBudget(data.MaxAPICalls.ValueInt64())
+	otelEndpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if !data.OTelEndpoint.IsNull() {
+		otelEndpoint = data.OTelEndpoint.ValueString()
+	}
+	if otelEndpoint != "" {
+		tflog.Debug(ctx, "Exporting traces", map[string]interface{}{
+			"otel_endpoint": otelEndpoint,
+		})
+		tracer, err := newTracer(ctx, otelEndpoint)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("otel_endpoint"), "Invalid OpenTelemetry Endpoint", err.Error())
+			return
+		}
+		api.tracer = tracer
+	}
+	api.pageSize = int(data.PageSize.ValueInt64())
+	if data.AbortUnhealthy.ValueBool() {
+		api.health = &healthGate{}
+	}
+	auditLogPath := os.Getenv("TSS_AUDIT_LOG_PATH")
+	if !data.AuditLogPath.IsNull() {
+		auditLogPath = data.AuditLogPath.ValueString()
+	}
+	if auditLogPath != "" {
+		if api.audit, err = openAuditLog(auditLogPath, logging.RedactFields); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("audit_log_path"),
+				"Unable to Open Audit Log",
+				fmt.Sprintf("Failed to open %s for appending: %s", auditLogPath, err),
+			)
+			return
+		}
+	}
+	if clientID != "" && token == "" {
+		if clientSecret == "" {
+			clientSecret, err = api.onboardedClientSecret(ctx, clientID, onboardingRule, onboardingKey, onboardingSecretFile)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("onboarding_rule"),
+					"Unable to Register SDK Client",
+					fmt.Sprintf("Failed to obtain a client secret for client %q under onboarding rule %q: %s", clientID, onboardingRule, err),
+				)
+				return
+			}
+		}
+		api.useClientCredentials(clientID, clientSecret)
+	}
+
+	if useOIDC && token == "" && clientID == "" {
+		tflog.Debug(ctx, "Authenticating with an OIDC ID token", map[string]interface{}{
+			"token_file": oidcTokenFile,
+		})
+		api.useOIDC(oidcTokenFile, oidcAudience)
+	}
+
+	if len(domains) > 0 {
+		api.domains = authDomains(domain, domains)
+		tflog.Debug(ctx, "Authenticating with the first domain to accept the credentials", map[string]interface{}{
+			"domains": api.domains,
+		})
+	}
+
+	platform := isPlatformURL(serverUrl)
+	if !data.Platform.IsNull() && !data.Platform.IsUnknown() {
+		platform = data.Platform.ValueBool()
+	}
+	if platform {
+		tflog.Debug(ctx, "Authenticating with the Delinea Platform")
+		api.usePlatform()
+	}
+	// Aliases configured against the same server with the same credentials
+	// share one token
+	api.shareSession()
+
+	impersonateUser := os.Getenv("TSS_IMPERSONATE_USER")
+	if !data.ImpersonateUser.IsNull() {
+		impersonateUser = data.ImpersonateUser.ValueString()
+	}
+	if impersonateUser != "" {
+		tflog.Debug(ctx, "Reading as impersonated user", map[string]interface{}{
+			"impersonate_user": impersonateUser,
+		})
+		api.impersonateUser(impersonateUser)
+	}
+
+	for _, alias := range data.AuthAliases {
+		logging = logging.withSensitiveValues(alias.Password.ValueString(), alias.Token.ValueString(), alias.ClientSecret.ValueString())
+	}
+	identities, aliasDiags := authAliasIdentities(data.AuthAliases, *serverConfig, api)
+	resp.Diagnostics.Append(aliasDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateCredentials := os.Getenv("TSS_VALIDATE_CREDENTIALS") == "true"
+	if !data.ValidateCreds.IsNull() {
+		validateCredentials = data.ValidateCreds.ValueBool()
+	}
+	if validateCredentials {
+		if err := api.validateCredentials(ctx); err != nil {
+			tflog.Error(ctx, "Credential check failed", map[string]interface{}{
+				"server_url": serverUrl,
+				"error":      err.Error(),
+			})
+			resp.Diagnostics.AddError(
+				"Invalid Secret Server URL or Credentials",
+				"validate_credentials is set and the provider could not authenticate to Secret Server. "+
+					"Check server_url and the configured credentials.\n\n"+err.Error(),
+			)
+			return
+		}
+	}
+
+	minimumVersion := os.Getenv("TSS_MINIMUM_SERVER_VERSION")
+	if !data.MinVersion.IsNull() {
+		minimumVersion = data.MinVersion.ValueString()
+	}
+	if minimumVersion != "" {
+		if _, err := parseVersion(minimumVersion); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("minimum_server_version"), "Invalid Minimum Server Version", err.Error())
+			return
+		}
+		if err := api.checkServerVersion(ctx, minimumVersion); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("minimum_server_version"), "Unsupported Secret Server Version", err.Error())
+			return
+		}
+	}
+
+	workspace := os.Getenv("TSS_WORKSPACE")
+	if !data.Workspace.IsNull() {
+		workspace = data.Workspace.ValueString()
+	}
+
+	providerData := &TssProviderData{
+		Server:         tssClient,
+		API:            api,
+		Defaults:       defaults,
+		Logging:        logging,
+		FingerprintKey: fingerprints,
+		StateCipher:    stateEncryption,
+		Identities:     identities,
+		Workspace:      workspace,
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+	resp.EphemeralResourceData = providerData
+
+	// Data sources read from the replica when one is set
+	readReplicaURL := os.Getenv("TSS_READ_REPLICA_URL")
+	if !data.ReadReplicaURL.IsNull() {
+		readReplicaURL = data.ReadReplicaURL.ValueString()
+	}
+	if readReplicaURL == "" {
+		return
+	}
+	if platform {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("read_replica_url"),
+			"Unsupported Read Replica",
+			"read_replica_url is not supported on the Delinea Platform, which serves the API from the tenant's default vault.",
+		)
+		return
+	}
+	replicaConfig := *serverConfig
+	replicaConfig.ServerURL = readReplicaURL
+	if err := transports.route(readReplicaURL, transport); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("read_replica_url"), "Conflicting Connection Settings", err.Error())
+		return
+	}
+	replicaServer, err := server.New(replicaConfig)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("read_replica_url"),
+			"Unable to create TSS API Client",
+			"An unexpected error occurred when creating the client of the read replica: "+err.Error(),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Reading data sources from replica", map[string]interface{}{
+		"read_replica_url": readReplicaURL,
+	})
+	replicaData := *providerData
+	replicaData.Server = replicaServer
+	replicaData.API = api.forReplica(replicaServer)
+	resp.DataSourceData = &replicaData
+}
+
+// authMethod describes how the provider authenticates, for logging
+func authMethod(token, clientID string, useOIDC bool) string {
+	switch {
+	case token != "":
+		return "token"
+	case useOIDC:
+		return "oidc"
+	case clientID != "":
+		return "client_credentials"
+	}
+	return "password"
 }
 
 // DataSources returns the data sources supported by the provider
@@ -241,6 +1101,13 @@ func (p *TssProvider) DataSources(ctx context.Context) []func() datasource.DataS
 	return []func() datasource.DataSource{
 		NewTssSecretDataSource,
 		NewTssSecretsDataSource,
+		NewTssDiscoveryResultsDataSource,
+		NewTssEncryptionStatusDataSource,
+		NewTssSecretTemplateLaunchersDataSource,
+		NewTssFolderStatsDataSource,
+		NewTssServerVersionDataSource,
+		NewTssAPICallDataSource,
+		NewTssSecretDependencyTemplatesDataSource,
 	}
 }
 
@@ -249,6 +1116,29 @@ func (p *TssProvider) Resources(ctx context.Context) []func() resource.Resource
 	tflog.Trace(ctx, "Registering TSS resources")
 	return []func() resource.Resource{
 		NewTssSecretResource,
+		NewTssFolderTemplateRestrictionResource,
+		NewTssLauncherSettingsResource,
+		NewTssDiscoverySourceResource,
+		NewTssDiscoveryRuleResource,
+		NewTssJumpboxRouteResource,
+		NewTssJumpboxRouteAssignmentResource,
+		NewTssWorkflowTemplateResource,
+		NewTssUserPasswordResetResource,
+		NewTssSecretPolicyExceptionResource,
+		NewTssFolderPermissionMirrorResource,
+		NewTssSecretTemplateLauncherResource,
+		NewTssFolderExpirationNotificationResource,
+		NewTssAPICallResource,
+		NewTssSecretConnectAsChainResource,
+	}
+}
+
+// Functions returns the functions supported by the provider
+func (p *TssProvider) Functions(ctx context.Context) []func() function.Function {
+	tflog.Trace(ctx, "Registering TSS functions")
+	return []func() function.Function{
+		NewNormalizeSlugFunction,
+		NewParseSecretURLFunction,
 	}
 }
 
@@ -257,6 +1147,7 @@ func (p *TssProvider) EphemeralResources(ctx context.Context) []func() ephemeral
 	return []func() ephemeral.EphemeralResource{
 		NewTssSecretEphemeralResource,
 		NewTssSecretsEphemeralResource,
+		NewTssAPIAccountEphemeralResource,
 	}
 }
 
@@ -268,3 +1159,33 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// NewServer returns the protocol version 6 server of the provider, which adds
+// the Secret Server deprecation notices to the response of each operation
+func NewServer(version string) func() tfprotov6.ProviderServer {
+	return func() tfprotov6.ProviderServer {
+		return deprecationReportingServer{providerserver.NewProtocol6(New(version)())()}
+	}
+}
+
+// environmentCredentials returns the server URL and credentials set in the
+// environment. The SS_* names used by the Delinea SDK and CLIs are read when
+// the TSS_* name is not set.
+func environmentCredentials() (string, server.UserCredential) {
+	return getenv("TSS_SERVER_URL", "SS_SERVER_URL"), server.UserCredential{
+		Username: getenv("TSS_USER", "SS_USERNAME"),
+		Password: getenv("TSS_PASSWORD", "SS_PASSWORD"),
+		Domain:   getenv("TSS_DOMAIN", "SS_DOMAIN"),
+		Token:    os.Getenv("TSS_TOKEN"),
+	}
+}
+
+// getenv returns the value of the first of names that is set in the environment
+func getenv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
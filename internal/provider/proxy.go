@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// proxySettings are the provider settings that route requests through an HTTP
+// or SOCKS5 proxy
+type proxySettings struct {
+	HTTPProxy      string
+	HTTPSProxy     string
+	NoProxy        string
+	SOCKS5Proxy    string
+	SOCKS5Username string
+	SOCKS5Password string
+}
+
+// isSet reports whether any proxy setting was configured on the provider
+func (s proxySettings) isSet() bool {
+	return s.HTTPProxy != "" || s.HTTPSProxy != "" || s.NoProxy != "" || s.SOCKS5Proxy != ""
+}
+
+// apply configures the transport to send requests through the proxy. With a
+// SOCKS5 proxy every connection is dialed through it and HTTP proxies are not used.
+func (s proxySettings) apply(transport *http.Transport) error {
+	if s.SOCKS5Proxy == "" {
+		transport.Proxy = s.proxyFunc()
+		return nil
+	}
+
+	dialContext, err := s.socks5Dialer(transport)
+	if err != nil {
+		return err
+	}
+	transport.Proxy = nil
+	transport.DialContext = dialContext
+	return nil
+}
+
+// socks5Dialer returns a dialer that connects through the SOCKS5 proxy. The
+// proxy is given as host:port or as a socks5:// URL, which may carry the credentials.
+func (s proxySettings) socks5Dialer(transport *http.Transport) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	address := s.SOCKS5Proxy
+	var auth *proxy.Auth
+
+	if strings.Contains(address, "://") {
+		u, err := url.Parse(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid socks5_proxy: %w", err)
+		}
+		if u.Scheme != "socks5" && u.Scheme != "socks5h" {
+			return nil, fmt.Errorf("invalid socks5_proxy: unsupported scheme %q, expected socks5", u.Scheme)
+		}
+		address = u.Host
+		if u.User != nil {
+			password, _ := u.User.Password()
+			auth = &proxy.Auth{User: u.User.Username(), Password: password}
+		}
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, fmt.Errorf("invalid socks5_proxy: %w", err)
+	}
+	if s.SOCKS5Username != "" {
+		auth = &proxy.Auth{User: s.SOCKS5Username, Password: s.SOCKS5Password}
+	}
+
+	// Connect to the proxy the way the transport would connect directly, so the
+	// connect timeout applies
+	var forward proxy.Dialer = proxy.Direct
+	if transport.DialContext != nil {
+		forward = contextDialerFunc(transport.DialContext)
+	}
+
+	dialer, err := proxy.SOCKS5("tcp", address, auth, forward)
+	if err != nil {
+		return nil, fmt.Errorf("invalid socks5_proxy: %w", err)
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("the SOCKS5 dialer does not support contexts")
+	}
+	return contextDialer.DialContext, nil
+}
+
+// contextDialerFunc adapts a transport's DialContext to the proxy package's dialer interfaces
+type contextDialerFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Dial connects without a context
+func (f contextDialerFunc) Dial(network, addr string) (net.Conn, error) {
+	return f(context.Background(), network, addr)
+}
+
+// DialContext connects under the given context
+func (f contextDialerFunc) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return f(ctx, network, addr)
+}
+
+// proxyFunc returns the proxy selection for the HTTP transport. Settings that
+// are not configured fall back to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables.
+func (s proxySettings) proxyFunc() func(*http.Request) (*url.URL, error) {
+	config := httpproxy.FromEnvironment()
+	if s.HTTPProxy != "" {
+		config.HTTPProxy = s.HTTPProxy
+	}
+	if s.HTTPSProxy != "" {
+		config.HTTPSProxy = s.HTTPSProxy
+	}
+	if s.NoProxy != "" {
+		config.NoProxy = s.NoProxy
+	}
+
+	proxyForURL := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyForURL(req.URL)
+	}
+}
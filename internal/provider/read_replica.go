@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+)
+
+// forReplica returns a client of the read-only replica served by s, which
+// authenticates like c and shares its limits and call budget
+func (c *apiClient) forReplica(s *server.Server) *apiClient {
+	replica := c.withServer(s)
+	replica.clientID = c.clientID
+	replica.clientSecret = c.clientSecret
+	replica.oidc = c.oidc
+	replica.domains = c.domains
+	if c.impersonation != nil {
+		replica.impersonateUser(c.impersonation.username)
+	}
+	// The replica issues its own tokens, which its base URL keeps apart from
+	// the primary's session
+	replica.shareSession()
+	return replica
+}
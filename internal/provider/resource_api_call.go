@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &TssAPICallResource{}
+	_ resource.ResourceWithConfigure = &TssAPICallResource{}
+)
+
+// NewTssAPICallResource is a helper function to simplify the provider implementation.
+func NewTssAPICallResource() resource.Resource {
+	return &TssAPICallResource{}
+}
+
+// TssAPICallResource calls a Secret Server REST API endpoint when it is created,
+// and optionally another when it is destroyed, as an escape hatch for features
+// no typed resource covers yet. Like a trigger, it does not track what the call
+// changed, so refresh leaves it as it is.
+type TssAPICallResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// APICallResourceState defines the state structure for the API call resource
+type APICallResourceState struct {
+	ID            types.String `tfsdk:"id"`
+	Method        types.String `tfsdk:"method"`
+	Path          types.String `tfsdk:"path"`
+	Body          types.String `tfsdk:"body"`
+	ResponsePath  types.String `tfsdk:"response_path"`
+	DestroyMethod types.String `tfsdk:"destroy_method"`
+	DestroyPath   types.String `tfsdk:"destroy_path"`
+	DestroyBody   types.String `tfsdk:"destroy_body"`
+	Triggers      types.Map    `tfsdk:"triggers"`
+	Response      types.String `tfsdk:"response"`
+	Result        types.String `tfsdk:"result"`
+}
+
+// Metadata provides the resource type name
+func (r *TssAPICallResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_api_call"
+}
+
+// Schema defines the schema for the resource
+func (r *TssAPICallResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	replace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+	resp.Schema = schema.Schema{
+		Description: "Calls a Secret Server REST API endpoint on create, and optionally another on destroy, as an escape hatch for features the provider does not cover yet. " +
+			"The call is repeated whenever method, path, body or triggers change. What the call changed is not tracked, so drift is not detected. " +
+			"Prefer the typed resources where they exist; this one may be removed once the feature it is used for is supported.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The method and path of the call, e.g. \"POST folders\".",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"method": schema.StringAttribute{
+				Optional:      true,
+				Description:   "The HTTP method of the call made on create. Defaults to POST.",
+				PlanModifiers: replace,
+			},
+			"path": schema.StringAttribute{
+				Required:      true,
+				Description:   "The path of the endpoint relative to /api/v1, including any query string, e.g. \"secrets/42/extended-mappings\".",
+				PlanModifiers: replace,
+			},
+			"body": schema.StringAttribute{
+				Optional:      true,
+				Description:   "The JSON request body, e.g. from jsonencode().",
+				PlanModifiers: replace,
+			},
+			"response_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "A jq style path selecting result from the response, e.g. \".id\". Defaults to the whole response.",
+			},
+			"destroy_method": schema.StringAttribute{
+				Optional:    true,
+				Description: "The HTTP method of the call made on destroy. Defaults to DELETE.",
+			},
+			"destroy_path": schema.StringAttribute{
+				Optional: true,
+				Description: "The path of the endpoint called on destroy, in which {result} is replaced by result, e.g. \"folders/{result}\" with response_path \".id\". " +
+					"When not set, destroying only removes the resource from state. Destroying fails when the path refers to {result} and result is empty.",
+			},
+			"destroy_body": schema.StringAttribute{
+				Optional:    true,
+				Description: "The JSON request body of the call made on destroy.",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that replace the resource, and so repeat the call, when they change.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"response": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The JSON response body of the call made on create.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"result": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The value selected by response_path: strings as they are, other values as JSON. Null when the path selects nothing.",
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssAPICallResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create makes the call
+func (r *TssAPICallResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan APICallResourceState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	method := apiCallMethod(plan.Method, http.MethodPost)
+	response, err := r.api.call(ctx, method, plan.Path.ValueString(), plan.Body)
+	if err != nil {
+		resp.Diagnostics.AddError("API Call Error", fmt.Sprintf("%s %s failed: %s", method, plan.Path.ValueString(), err))
+		return
+	}
+
+	plan.ID = types.StringValue(method + " " + plan.Path.ValueString())
+	plan.Response = types.StringValue(string(response))
+	result, err := extractResponse(response, plan.ResponsePath.ValueString())
+	if err != nil {
+		// The call was made, so keep it in state rather than repeating it
+		resp.Diagnostics.AddAttributeWarning(path.Root("response_path"), "Invalid Response Path", err.Error())
+	}
+	plan.Result = result
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read leaves the state as it is, as what the call changed is not tracked
+func (r *TssAPICallResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	tflog.Trace(r.logging.context(ctx), "API call resources are not refreshed")
+}
+
+// Update selects the result again from the stored response; every other
+// argument either replaces the resource or only applies on destroy
+func (r *TssAPICallResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan, state APICallResourceState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Response = state.Response
+	result, err := extractResponse([]byte(state.Response.ValueString()), plan.ResponsePath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("response_path"), "Invalid Response Path", err.Error())
+		return
+	}
+	plan.Result = result
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete makes the destroy call, when there is one
+func (r *TssAPICallResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state APICallResourceState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.DestroyPath.IsNull() || state.DestroyPath.ValueString() == "" {
+		tflog.Debug(ctx, "Removing API call from state; no destroy call is configured")
+		return
+	}
+
+	// An empty result would turn e.g. "folders/{result}" into a call on the collection
+	if strings.Contains(state.DestroyPath.ValueString(), "{result}") && state.Result.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("destroy_path"), "Missing Result",
+			fmt.Sprintf("destroy_path %q refers to {result}, but response_path selected nothing from the response, so the destroy call is not made. "+
+				"Undo the call by hand and remove the resource with terraform state rm.", state.DestroyPath.ValueString()))
+		return
+	}
+
+	method := apiCallMethod(state.DestroyMethod, http.MethodDelete)
+	destroyPath := strings.ReplaceAll(state.DestroyPath.ValueString(), "{result}", state.Result.ValueString())
+	_, err := r.api.call(ctx, method, destroyPath, state.DestroyBody)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("API Call Error", fmt.Sprintf("%s %s failed: %s", method, destroyPath, err))
+	}
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAPICallDeleteRefusesEmptyResult(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("%s %s was called", r.Method, r.URL.Path)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	r := &TssAPICallResource{api: newTestAPIClient(ts.URL)}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	for _, result := range []interface{}{nil, ""} {
+		attributes := map[string]tftypes.Value{}
+		for name, attributeType := range objectType.AttributeTypes {
+			attributes[name] = tftypes.NewValue(attributeType, nil)
+		}
+		attributes["destroy_path"] = tftypes.NewValue(tftypes.String, "folders/{result}")
+		attributes["result"] = tftypes.NewValue(tftypes.String, result)
+
+		req := resource.DeleteRequest{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)}}
+		var resp resource.DeleteResponse
+		r.Delete(ctx, req, &resp)
+		if !resp.Diagnostics.HasError() {
+			t.Errorf("destroying with result %v succeeded", result)
+		}
+	}
+}
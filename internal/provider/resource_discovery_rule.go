@@ -0,0 +1,322 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &TssDiscoveryRuleResource{}
+	_ resource.ResourceWithConfigure   = &TssDiscoveryRuleResource{}
+	_ resource.ResourceWithImportState = &TssDiscoveryRuleResource{}
+)
+
+// NewTssDiscoveryRuleResource is a helper function to simplify the provider implementation.
+func NewTssDiscoveryRuleResource() resource.Resource {
+	return &TssDiscoveryRuleResource{}
+}
+
+// TssDiscoveryRuleResource manages a scan and import rule of a discovery source
+type TssDiscoveryRuleResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// DiscoveryRuleState defines the state structure for the discovery rule resource
+type DiscoveryRuleState struct {
+	ID                  types.String `tfsdk:"id"`
+	SourceID            types.Int64  `tfsdk:"source_id"`
+	Name                types.String `tfsdk:"name"`
+	Active              types.Bool   `tfsdk:"active"`
+	NetworkRanges       types.List   `tfsdk:"network_ranges"`
+	CredentialSecretIDs types.List   `tfsdk:"credential_secret_ids"`
+	ImportFolderID      types.Int64  `tfsdk:"import_folder_id"`
+	ImportTemplateID    types.Int64  `tfsdk:"import_template_id"`
+}
+
+// discoveryRule is a discovery rule as exchanged with Secret Server
+type discoveryRule struct {
+	ID                     int      `json:"id,omitempty"`
+	Name                   string   `json:"name"`
+	Active                 bool     `json:"active"`
+	NetworkRanges          []string `json:"networkRanges"`
+	SecretIDs              []int    `json:"secretIds"`
+	ImportFolderID         int      `json:"importFolderId,omitempty"`
+	ImportSecretTemplateID int      `json:"importSecretTemplateId,omitempty"`
+}
+
+// Metadata provides the resource type name
+func (r *TssDiscoveryRuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_discovery_rule"
+}
+
+// Schema defines the schema for the resource
+func (r *TssDiscoveryRuleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a rule of a discovery source: the network ranges it scans, the credentials it scans with, " +
+			"and where discovered accounts are imported. Import with \"<source_id>/<rule_id>\".",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the discovery rule.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"source_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the discovery source the rule belongs to.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the discovery rule.",
+			},
+			"active": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether the rule is used during discovery. Defaults to true.",
+			},
+			"network_ranges": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "The network ranges to scan, as CIDR blocks or start-end address ranges.",
+			},
+			"credential_secret_ids": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Description: "The IDs of the secrets whose credentials are used to scan the ranges.",
+			},
+			"import_folder_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The ID of the folder discovered accounts are imported into.",
+			},
+			"import_template_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The ID of the secret template discovered accounts are imported as.",
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssDiscoveryRuleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create creates the discovery rule
+func (r *TssDiscoveryRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan DiscoveryRuleState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, diags := plan.expand(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Creating discovery rule", map[string]interface{}{
+		"source_id": plan.SourceID.ValueInt64(),
+		"name":      rule.Name,
+	})
+
+	var created discoveryRule
+	if err := r.api.do(ctx, "POST", plan.rulesPath(), map[string]interface{}{"data": rule}, &created); err != nil {
+		resp.Diagnostics.AddError("Discovery Rule Error", fmt.Sprintf("Failed to create discovery rule: %s", err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(created.ID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the discovery rule
+func (r *TssDiscoveryRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state DiscoveryRuleState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var rule discoveryRule
+	err := r.api.do(ctx, "GET", state.rulePath(), nil, &rule)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Discovery rule not found, removing from state", map[string]interface{}{
+			"id": state.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Discovery Rule Error", fmt.Sprintf("Failed to read discovery rule %s: %s", state.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(state.flatten(ctx, &rule)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update updates the discovery rule
+func (r *TssDiscoveryRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan DiscoveryRuleState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, diags := plan.expand(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	rule.ID, _ = strconv.Atoi(plan.ID.ValueString())
+
+	if err := r.api.do(ctx, "PUT", plan.rulePath(), map[string]interface{}{"data": rule}, nil); err != nil {
+		resp.Diagnostics.AddError("Discovery Rule Error", fmt.Sprintf("Failed to update discovery rule %s: %s", plan.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the discovery rule
+func (r *TssDiscoveryRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state DiscoveryRuleState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Deleting discovery rule", map[string]interface{}{
+		"id": state.ID.ValueString(),
+	})
+
+	err := r.api.do(ctx, "DELETE", state.rulePath(), nil, nil)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Discovery Rule Error", fmt.Sprintf("Failed to delete discovery rule %s: %s", state.ID.ValueString(), err))
+	}
+}
+
+// ImportState imports a discovery rule by "<source_id>/<rule_id>"
+func (r *TssDiscoveryRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected \"<source_id>/<rule_id>\", got %q.", req.ID))
+		return
+	}
+	sourceID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("The source ID %q is not a number.", parts[0]))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, DiscoveryRuleState{
+		ID:                  types.StringValue(parts[1]),
+		SourceID:            types.Int64Value(sourceID),
+		Name:                types.StringNull(),
+		Active:              types.BoolNull(),
+		NetworkRanges:       types.ListNull(types.StringType),
+		CredentialSecretIDs: types.ListNull(types.Int64Type),
+		ImportFolderID:      types.Int64Null(),
+		ImportTemplateID:    types.Int64Null(),
+	})...)
+}
+
+// rulesPath is the API path of the rules of the state's discovery source
+func (s DiscoveryRuleState) rulesPath() string {
+	return fmt.Sprintf("discovery-sources/%d/rules", s.SourceID.ValueInt64())
+}
+
+// rulePath is the API path of the state's discovery rule
+func (s DiscoveryRuleState) rulePath() string {
+	return s.rulesPath() + "/" + s.ID.ValueString()
+}
+
+// expand converts the state into the API model
+func (s DiscoveryRuleState) expand(ctx context.Context) (*discoveryRule, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rule := &discoveryRule{
+		Name:                   s.Name.ValueString(),
+		Active:                 s.Active.ValueBool(),
+		ImportFolderID:         int(s.ImportFolderID.ValueInt64()),
+		ImportSecretTemplateID: int(s.ImportTemplateID.ValueInt64()),
+		NetworkRanges:          []string{},
+		SecretIDs:              []int{},
+	}
+	if !s.NetworkRanges.IsNull() && !s.NetworkRanges.IsUnknown() {
+		diags.Append(s.NetworkRanges.ElementsAs(ctx, &rule.NetworkRanges, false)...)
+	}
+	if !s.CredentialSecretIDs.IsNull() && !s.CredentialSecretIDs.IsUnknown() {
+		diags.Append(s.CredentialSecretIDs.ElementsAs(ctx, &rule.SecretIDs, false)...)
+	}
+	return rule, diags
+}
+
+// flatten copies the API model into the state
+func (s *DiscoveryRuleState) flatten(ctx context.Context, rule *discoveryRule) diag.Diagnostics {
+	var diags diag.Diagnostics
+	s.Name = types.StringValue(rule.Name)
+	s.Active = types.BoolValue(rule.Active)
+	if rule.ImportFolderID != 0 || !s.ImportFolderID.IsNull() {
+		s.ImportFolderID = types.Int64Value(int64(rule.ImportFolderID))
+	}
+	if rule.ImportSecretTemplateID != 0 || !s.ImportTemplateID.IsNull() {
+		s.ImportTemplateID = types.Int64Value(int64(rule.ImportSecretTemplateID))
+	}
+	if len(rule.NetworkRanges) > 0 || !s.NetworkRanges.IsNull() {
+		ranges, d := types.ListValueFrom(ctx, types.StringType, rule.NetworkRanges)
+		diags.Append(d...)
+		s.NetworkRanges = ranges
+	}
+	if len(rule.SecretIDs) > 0 || !s.CredentialSecretIDs.IsNull() {
+		ids, d := types.ListValueFrom(ctx, types.Int64Type, rule.SecretIDs)
+		diags.Append(d...)
+		s.CredentialSecretIDs = ids
+	}
+	return diags
+}
@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &TssDiscoverySourceResource{}
+	_ resource.ResourceWithConfigure   = &TssDiscoverySourceResource{}
+	_ resource.ResourceWithImportState = &TssDiscoverySourceResource{}
+)
+
+// NewTssDiscoverySourceResource is a helper function to simplify the provider implementation.
+func NewTssDiscoverySourceResource() resource.Resource {
+	return &TssDiscoverySourceResource{}
+}
+
+// TssDiscoverySourceResource manages an account discovery source
+type TssDiscoverySourceResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// DiscoverySourceState defines the state structure for the discovery source resource
+type DiscoverySourceState struct {
+	ID                  types.String `tfsdk:"id"`
+	Name                types.String `tfsdk:"name"`
+	SourceType          types.String `tfsdk:"source_type"`
+	Active              types.Bool   `tfsdk:"active"`
+	CredentialSecretIDs types.List   `tfsdk:"credential_secret_ids"`
+	SiteID              types.Int64  `tfsdk:"site_id"`
+}
+
+// discoverySource is a discovery source as exchanged with Secret Server
+type discoverySource struct {
+	ID                  int    `json:"id,omitempty"`
+	Name                string `json:"name"`
+	DiscoverySourceType string `json:"discoverySourceType"`
+	Active              bool   `json:"active"`
+	SecretIDs           []int  `json:"secretIds"`
+	SiteID              int    `json:"siteId,omitempty"`
+}
+
+// Metadata provides the resource type name
+func (r *TssDiscoverySourceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_discovery_source"
+}
+
+// Schema defines the schema for the resource
+func (r *TssDiscoverySourceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an account discovery source, such as an Active Directory domain or a range of Unix hosts.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the discovery source.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the discovery source.",
+			},
+			"source_type": schema.StringAttribute{
+				Required:    true,
+				Description: "The type of the discovery source, e.g. \"ActiveDirectory\" or \"UnixNonDomain\".",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"active": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether discovery runs for the source. Defaults to true.",
+			},
+			"credential_secret_ids": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+				Description: "The IDs of the secrets whose credentials are used to scan the source.",
+			},
+			"site_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The ID of the distributed engine site that scans the source.",
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssDiscoverySourceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create creates the discovery source
+func (r *TssDiscoverySourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan DiscoverySourceState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, diags := plan.expand(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Creating discovery source", map[string]interface{}{
+		"name": source.Name,
+		"type": source.DiscoverySourceType,
+	})
+
+	var created discoverySource
+	if err := r.api.do(ctx, "POST", "discovery-sources", map[string]interface{}{"data": source}, &created); err != nil {
+		resp.Diagnostics.AddError("Discovery Source Error", fmt.Sprintf("Failed to create discovery source: %s", err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(created.ID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the discovery source
+func (r *TssDiscoverySourceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state DiscoverySourceState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var source discoverySource
+	err := r.api.do(ctx, "GET", "discovery-sources/"+state.ID.ValueString(), nil, &source)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Discovery source not found, removing from state", map[string]interface{}{
+			"id": state.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Discovery Source Error", fmt.Sprintf("Failed to read discovery source %s: %s", state.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(state.flatten(ctx, &source)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update updates the discovery source
+func (r *TssDiscoverySourceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan DiscoverySourceState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	source, diags := plan.expand(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	source.ID, _ = strconv.Atoi(plan.ID.ValueString())
+
+	if err := r.api.do(ctx, "PUT", "discovery-sources/"+plan.ID.ValueString(), map[string]interface{}{"data": source}, nil); err != nil {
+		resp.Diagnostics.AddError("Discovery Source Error", fmt.Sprintf("Failed to update discovery source %s: %s", plan.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the discovery source
+func (r *TssDiscoverySourceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state DiscoverySourceState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Deleting discovery source", map[string]interface{}{
+		"id": state.ID.ValueString(),
+	})
+
+	err := r.api.do(ctx, "DELETE", "discovery-sources/"+state.ID.ValueString(), nil, nil)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Discovery Source Error", fmt.Sprintf("Failed to delete discovery source %s: %s", state.ID.ValueString(), err))
+	}
+}
+
+// ImportState imports a discovery source by ID
+func (r *TssDiscoverySourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// expand converts the state into the API model
+func (s DiscoverySourceState) expand(ctx context.Context) (*discoverySource, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	source := &discoverySource{
+		Name:                s.Name.ValueString(),
+		DiscoverySourceType: s.SourceType.ValueString(),
+		Active:              s.Active.ValueBool(),
+		SiteID:              int(s.SiteID.ValueInt64()),
+		SecretIDs:           []int{},
+	}
+	if !s.CredentialSecretIDs.IsNull() && !s.CredentialSecretIDs.IsUnknown() {
+		diags.Append(s.CredentialSecretIDs.ElementsAs(ctx, &source.SecretIDs, false)...)
+	}
+	return source, diags
+}
+
+// flatten copies the API model into the state
+func (s *DiscoverySourceState) flatten(ctx context.Context, source *discoverySource) diag.Diagnostics {
+	var diags diag.Diagnostics
+	s.Name = types.StringValue(source.Name)
+	s.SourceType = types.StringValue(source.DiscoverySourceType)
+	s.Active = types.BoolValue(source.Active)
+	if source.SiteID != 0 || !s.SiteID.IsNull() {
+		s.SiteID = types.Int64Value(int64(source.SiteID))
+	}
+	if len(source.SecretIDs) > 0 || !s.CredentialSecretIDs.IsNull() {
+		ids, d := types.ListValueFrom(ctx, types.Int64Type, source.SecretIDs)
+		diags.Append(d...)
+		s.CredentialSecretIDs = ids
+	}
+	return diags
+}
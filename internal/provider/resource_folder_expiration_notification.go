@@ -0,0 +1,311 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &TssFolderExpirationNotificationResource{}
+	_ resource.ResourceWithConfigure      = &TssFolderExpirationNotificationResource{}
+	_ resource.ResourceWithImportState    = &TssFolderExpirationNotificationResource{}
+	_ resource.ResourceWithValidateConfig = &TssFolderExpirationNotificationResource{}
+)
+
+// NewTssFolderExpirationNotificationResource is a helper function to simplify the provider implementation.
+func NewTssFolderExpirationNotificationResource() resource.Resource {
+	return &TssFolderExpirationNotificationResource{}
+}
+
+// TssFolderExpirationNotificationResource manages the reminders sent before the
+// secrets of a folder expire
+type TssFolderExpirationNotificationResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// FolderExpirationNotificationState defines the state structure for the folder expiration notification resource
+type FolderExpirationNotificationState struct {
+	ID              types.String `tfsdk:"id"`
+	FolderID        types.Int64  `tfsdk:"folder_id"`
+	DaysBefore      types.Int64  `tfsdk:"days_before"`
+	EmailRecipients types.Set    `tfsdk:"email_recipients"`
+	WebhookURL      types.String `tfsdk:"webhook_url"`
+	IncludeChildren types.Bool   `tfsdk:"include_subfolders"`
+}
+
+// expirationNotification is a folder's expiration reminder as the API represents it
+type expirationNotification struct {
+	DaysBefore        int      `json:"daysBefore"`
+	EmailRecipients   []string `json:"emailRecipients"`
+	WebhookURL        string   `json:"webhookUrl"`
+	IncludeSubfolders bool     `json:"includeSubfolders"`
+}
+
+// Metadata provides the resource type name
+func (r *TssFolderExpirationNotificationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_folder_expiration_notification"
+}
+
+// Schema defines the schema for the resource
+func (r *TssFolderExpirationNotificationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Sends reminders by email, webhook or both a number of days before the secrets of a folder expire, " +
+			"so they can be rotated before they do. A folder has one reminder; destroying the resource removes it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the folder.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"folder_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the folder whose secrets to send reminders for.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"days_before": schema.Int64Attribute{
+				Required:    true,
+				Description: "How many days before a secret expires to send the reminder.",
+			},
+			"email_recipients": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "The email addresses to send the reminder to.",
+			},
+			"webhook_url": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "A URL to post the reminder to. Sensitive, as webhook URLs usually embed a key.",
+			},
+			"include_subfolders": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to also send reminders for the secrets of subfolders. Defaults to false.",
+			},
+		},
+	}
+}
+
+// ValidateConfig checks the reminder period and that the reminder has a recipient
+func (r *TssFolderExpirationNotificationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config FolderExpirationNotificationState
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.DaysBefore.IsUnknown() && !config.DaysBefore.IsNull() && config.DaysBefore.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("days_before"), "Invalid Reminder Period", "days_before must be at least 1.")
+	}
+
+	if config.EmailRecipients.IsUnknown() || config.WebhookURL.IsUnknown() {
+		return
+	}
+	if len(config.EmailRecipients.Elements()) == 0 && config.WebhookURL.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("email_recipients"), "Missing Recipients",
+			"Set email_recipients, webhook_url or both so the reminder is sent somewhere.")
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssFolderExpirationNotificationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create sets the folder's reminder
+func (r *TssFolderExpirationNotificationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan FolderExpirationNotificationState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	notification := plan.notification(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folderID := int(plan.FolderID.ValueInt64())
+	tflog.Info(ctx, "Setting folder expiration reminder", map[string]interface{}{
+		"folder_id":   folderID,
+		"days_before": notification.DaysBefore,
+	})
+
+	if err := r.api.setExpirationNotification(ctx, folderID, notification); err != nil {
+		resp.Diagnostics.AddError("Expiration Notification Error", fmt.Sprintf("Failed to set the expiration reminder of folder %d: %s", folderID, err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(folderID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the folder's reminder
+func (r *TssFolderExpirationNotificationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state FolderExpirationNotificationState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folderID := int(state.FolderID.ValueInt64())
+	notification, err := r.api.expirationNotification(ctx, folderID)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Folder expiration reminder not found, removing from state", map[string]interface{}{
+			"folder_id": folderID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Expiration Notification Error", fmt.Sprintf("Failed to read the expiration reminder of folder %d: %s", folderID, err))
+		return
+	}
+
+	state.ID = types.StringValue(strconv.Itoa(folderID))
+	state.DaysBefore = types.Int64Value(int64(notification.DaysBefore))
+
+	// Keep null attributes null while the server reports their empty value
+	if len(notification.EmailRecipients) > 0 || !state.EmailRecipients.IsNull() {
+		sort.Strings(notification.EmailRecipients)
+		recipients, diags := types.SetValueFrom(ctx, types.StringType, notification.EmailRecipients)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.EmailRecipients = recipients
+	}
+	if notification.WebhookURL != "" || !state.WebhookURL.IsNull() {
+		state.WebhookURL = types.StringValue(notification.WebhookURL)
+	}
+	if notification.IncludeSubfolders || !state.IncludeChildren.IsNull() {
+		state.IncludeChildren = types.BoolValue(notification.IncludeSubfolders)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update replaces the folder's reminder
+func (r *TssFolderExpirationNotificationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan FolderExpirationNotificationState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	notification := plan.notification(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folderID := int(plan.FolderID.ValueInt64())
+	if err := r.api.setExpirationNotification(ctx, folderID, notification); err != nil {
+		resp.Diagnostics.AddError("Expiration Notification Error", fmt.Sprintf("Failed to set the expiration reminder of folder %d: %s", folderID, err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(folderID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the folder's reminder
+func (r *TssFolderExpirationNotificationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state FolderExpirationNotificationState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folderID := int(state.FolderID.ValueInt64())
+	tflog.Info(ctx, "Removing folder expiration reminder", map[string]interface{}{
+		"folder_id": folderID,
+	})
+
+	err := r.api.do(ctx, "DELETE", fmt.Sprintf("folders/%d/expiration-notification", folderID), nil, nil)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Expiration Notification Error", fmt.Sprintf("Failed to remove the expiration reminder of folder %d: %s", folderID, err))
+	}
+}
+
+// ImportState imports a folder's reminder by folder ID
+func (r *TssFolderExpirationNotificationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	folderID, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected a folder ID, got %q.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("folder_id"), folderID)...)
+}
+
+// notification returns the reminder the state describes
+func (s FolderExpirationNotificationState) notification(ctx context.Context, diags *diag.Diagnostics) expirationNotification {
+	notification := expirationNotification{
+		DaysBefore:        int(s.DaysBefore.ValueInt64()),
+		EmailRecipients:   []string{},
+		WebhookURL:        s.WebhookURL.ValueString(),
+		IncludeSubfolders: s.IncludeChildren.ValueBool(),
+	}
+	if !s.EmailRecipients.IsNull() {
+		diags.Append(s.EmailRecipients.ElementsAs(ctx, &notification.EmailRecipients, false)...)
+	}
+	return notification
+}
+
+// expirationNotification returns a folder's expiration reminder
+func (c *apiClient) expirationNotification(ctx context.Context, folderID int) (*expirationNotification, error) {
+	var notification expirationNotification
+	if err := c.do(ctx, "GET", fmt.Sprintf("folders/%d/expiration-notification", folderID), nil, &notification); err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// setExpirationNotification creates or replaces a folder's expiration reminder
+func (c *apiClient) setExpirationNotification(ctx context.Context, folderID int, notification expirationNotification) error {
+	body := map[string]interface{}{"data": notification}
+	return c.do(ctx, "PUT", fmt.Sprintf("folders/%d/expiration-notification", folderID), body, nil)
+}
@@ -0,0 +1,416 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &TssFolderPermissionMirrorResource{}
+	_ resource.ResourceWithConfigure   = &TssFolderPermissionMirrorResource{}
+	_ resource.ResourceWithModifyPlan  = &TssFolderPermissionMirrorResource{}
+	_ resource.ResourceWithImportState = &TssFolderPermissionMirrorResource{}
+)
+
+// NewTssFolderPermissionMirrorResource is a helper function to simplify the provider implementation.
+func NewTssFolderPermissionMirrorResource() resource.Resource {
+	return &TssFolderPermissionMirrorResource{}
+}
+
+// TssFolderPermissionMirrorResource keeps the permissions of a target folder
+// identical to those of a source folder
+type TssFolderPermissionMirrorResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// FolderPermissionMirrorState defines the state structure for the folder permission mirror resource
+type FolderPermissionMirrorState struct {
+	ID             types.String `tfsdk:"id"`
+	SourceFolderID types.Int64  `tfsdk:"source_folder_id"`
+	TargetFolderID types.Int64  `tfsdk:"target_folder_id"`
+	Permissions    types.Set    `tfsdk:"permissions"`
+}
+
+// folderPermission is a permission of a folder as returned by Secret Server,
+// and an element of permissions
+type folderPermission struct {
+	ID                 int `tfsdk:"-"`
+	GroupID            int `tfsdk:"group_id"`
+	FolderAccessRoleID int `tfsdk:"folder_access_role_id"`
+	SecretAccessRoleID int `tfsdk:"secret_access_role_id"`
+}
+
+// folderPermissionAttrTypes are the attribute types of an element of permissions
+var folderPermissionAttrTypes = map[string]attr.Type{
+	"group_id":              types.Int64Type,
+	"folder_access_role_id": types.Int64Type,
+	"secret_access_role_id": types.Int64Type,
+}
+
+// Metadata provides the resource type name
+func (r *TssFolderPermissionMirrorResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_folder_permission_mirror"
+}
+
+// Schema defines the schema for the resource
+func (r *TssFolderPermissionMirrorResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Mirrors the permissions of a source folder onto a target folder, e.g. for DR or multi-site folder structures. " +
+			"Every plan compares the two folders, and apply adds, changes and removes target permissions until they match. " +
+			"The target folder must not inherit permissions. Destroying the resource leaves the target permissions as they are.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the target folder.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"source_folder_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the folder whose permissions are copied.",
+			},
+			"target_folder_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the folder whose permissions are kept identical to the source folder's.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"permissions": schema.SetNestedAttribute{
+				Computed:    true,
+				Description: "The permissions of the target folder. Planned from the source folder, so drift in either folder shows as a change.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"group_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The ID of the group, or of the personal group of a user.",
+						},
+						"folder_access_role_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The ID of the folder access role of the group.",
+						},
+						"secret_access_role_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The ID of the secret access role of the group.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssFolderPermissionMirrorResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// ModifyPlan plans the permissions of the source folder, so a difference from
+// the target folder shows as a change
+func (r *TssFolderPermissionMirrorResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.api == nil || r.api.offline {
+		return
+	}
+	ctx = r.logging.context(ctx)
+
+	var plan FolderPermissionMirrorState
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.SourceFolderID.IsUnknown() {
+		return
+	}
+
+	sourceID := int(plan.SourceFolderID.ValueInt64())
+	permissions, err := r.api.folderPermissions(ctx, sourceID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("source_folder_id"), "Folder Permission Mirror Error",
+			fmt.Sprintf("Failed to read the permissions of source folder %d: %s", sourceID, err))
+		return
+	}
+
+	planned, diags := folderPermissionSet(permissions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("permissions"), planned)...)
+}
+
+// Create copies the permissions of the source folder onto the target folder
+func (r *TssFolderPermissionMirrorResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan FolderPermissionMirrorState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.mirror(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the permissions of the target folder
+func (r *TssFolderPermissionMirrorResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state FolderPermissionMirrorState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	targetID := int(state.TargetFolderID.ValueInt64())
+	permissions, err := r.api.folderPermissions(ctx, targetID)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Folder not found, removing from state", map[string]interface{}{
+			"folder_id": targetID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Folder Permission Mirror Error", fmt.Sprintf("Failed to read the permissions of folder %d: %s", targetID, err))
+		return
+	}
+
+	current, diags := folderPermissionSet(permissions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Permissions = current
+	state.ID = types.StringValue(strconv.Itoa(targetID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update copies the permissions of the source folder onto the target folder again
+func (r *TssFolderPermissionMirrorResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan FolderPermissionMirrorState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.mirror(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete leaves the target folder's permissions as they are
+func (r *TssFolderPermissionMirrorResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	tflog.Debug(ctx, "Removing folder permission mirror from state; folder permissions are left unchanged")
+}
+
+// ImportState imports a mirror by "source/target" folder IDs
+func (r *TssFolderPermissionMirrorResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var sourceID, targetID int64
+	if _, err := fmt.Sscanf(req.ID, "%d/%d", &sourceID, &targetID); err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected source_folder_id/target_folder_id, got %q.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), strconv.FormatInt(targetID, 10))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("source_folder_id"), sourceID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("target_folder_id"), targetID)...)
+}
+
+// mirror adds, changes and removes permissions of the target folder until they
+// match the planned permissions, and records them in state. The source folder
+// is only read when the plan could not read it, e.g. as it was created in the
+// same apply; permissions it gained since the plan are left to the next plan.
+func (r *TssFolderPermissionMirrorResource) mirror(ctx context.Context, state *FolderPermissionMirrorState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	sourceID := int(state.SourceFolderID.ValueInt64())
+	targetID := int(state.TargetFolderID.ValueInt64())
+
+	var source []folderPermission
+	if state.Permissions.IsUnknown() || state.Permissions.IsNull() {
+		var err error
+		if source, err = r.api.folderPermissions(ctx, sourceID); err != nil {
+			diags.AddError("Folder Permission Mirror Error", fmt.Sprintf("Failed to read the permissions of source folder %d: %s", sourceID, err))
+			return diags
+		}
+		permissions, setDiags := folderPermissionSet(source)
+		diags.Append(setDiags...)
+		state.Permissions = permissions
+	} else {
+		diags.Append(state.Permissions.ElementsAs(ctx, &source, false)...)
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	target, err := r.api.folderPermissions(ctx, targetID)
+	if err != nil {
+		diags.AddError("Folder Permission Mirror Error", fmt.Sprintf("Failed to read the permissions of target folder %d: %s", targetID, err))
+		return diags
+	}
+
+	tflog.Info(ctx, "Mirroring folder permissions", map[string]interface{}{
+		"source_folder_id": sourceID,
+		"target_folder_id": targetID,
+	})
+
+	existing := make(map[int]folderPermission, len(target))
+	for _, p := range target {
+		existing[p.GroupID] = p
+	}
+	wanted := make(map[int]bool, len(source))
+	for _, p := range source {
+		wanted[p.GroupID] = true
+
+		current, ok := existing[p.GroupID]
+		switch {
+		case !ok:
+			err = r.api.addFolderPermission(ctx, targetID, p)
+		case current.FolderAccessRoleID != p.FolderAccessRoleID || current.SecretAccessRoleID != p.SecretAccessRoleID:
+			err = r.api.updateFolderPermission(ctx, current.ID, p)
+		}
+		if err != nil {
+			diags.AddError("Folder Permission Mirror Error",
+				fmt.Sprintf("Failed to set the permission of group %d on folder %d: %s", p.GroupID, targetID, err))
+			return diags
+		}
+	}
+	for _, p := range target {
+		if wanted[p.GroupID] {
+			continue
+		}
+		if err := r.api.removeFolderPermission(ctx, p.ID); err != nil {
+			diags.AddError("Folder Permission Mirror Error",
+				fmt.Sprintf("Failed to remove the permission of group %d from folder %d: %s", p.GroupID, targetID, err))
+			return diags
+		}
+	}
+
+	state.ID = types.StringValue(strconv.Itoa(targetID))
+	return diags
+}
+
+// folderPermissionSet converts folder permissions into the value of the permissions attribute
+func folderPermissionSet(permissions []folderPermission) (types.Set, diag.Diagnostics) {
+	objectType := types.ObjectType{AttrTypes: folderPermissionAttrTypes}
+	elements := make([]attr.Value, 0, len(permissions))
+	for _, p := range permissions {
+		element, diags := types.ObjectValue(folderPermissionAttrTypes, map[string]attr.Value{
+			"group_id":              types.Int64Value(int64(p.GroupID)),
+			"folder_access_role_id": types.Int64Value(int64(p.FolderAccessRoleID)),
+			"secret_access_role_id": types.Int64Value(int64(p.SecretAccessRoleID)),
+		})
+		if diags.HasError() {
+			return types.SetNull(objectType), diags
+		}
+		elements = append(elements, element)
+	}
+	return types.SetValue(objectType, elements)
+}
+
+// folderPermissions returns the permissions of a folder, sorted by group, following pagination
+func (c *apiClient) folderPermissions(ctx context.Context, folderID int) ([]folderPermission, error) {
+	pageSize := c.take()
+
+	query := url.Values{}
+	query.Set("filter.folderId", strconv.Itoa(folderID))
+	query.Set("take", strconv.Itoa(pageSize))
+
+	var permissions []folderPermission
+	for skip := 0; ; skip += pageSize {
+		query.Set("skip", strconv.Itoa(skip))
+
+		var page struct {
+			Records []folderPermission
+			HasNext bool
+		}
+		if err := c.do(ctx, "GET", "folder-permissions?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, page.Records...)
+
+		if !page.HasNext || len(page.Records) == 0 {
+			sort.Slice(permissions, func(i, j int) bool { return permissions[i].GroupID < permissions[j].GroupID })
+			return permissions, nil
+		}
+	}
+}
+
+// addFolderPermission grants a group the roles of p on a folder
+func (c *apiClient) addFolderPermission(ctx context.Context, folderID int, p folderPermission) error {
+	tflog.Debug(ctx, "Adding folder permission", map[string]interface{}{
+		"folder_id": folderID,
+		"group_id":  p.GroupID,
+	})
+	body := map[string]interface{}{
+		"folderId":           folderID,
+		"groupId":            p.GroupID,
+		"folderAccessRoleId": p.FolderAccessRoleID,
+		"secretAccessRoleId": p.SecretAccessRoleID,
+	}
+	return c.do(ctx, "POST", "folder-permissions", body, nil)
+}
+
+// updateFolderPermission changes the roles of a folder permission to those of p
+func (c *apiClient) updateFolderPermission(ctx context.Context, permissionID int, p folderPermission) error {
+	tflog.Debug(ctx, "Updating folder permission", map[string]interface{}{
+		"permission_id": permissionID,
+		"group_id":      p.GroupID,
+	})
+	body := map[string]interface{}{
+		"id":                 permissionID,
+		"folderAccessRoleId": p.FolderAccessRoleID,
+		"secretAccessRoleId": p.SecretAccessRoleID,
+	}
+	return c.do(ctx, "PUT", fmt.Sprintf("folder-permissions/%d", permissionID), body, nil)
+}
+
+// removeFolderPermission removes a folder permission
+func (c *apiClient) removeFolderPermission(ctx context.Context, permissionID int) error {
+	tflog.Debug(ctx, "Removing folder permission", map[string]interface{}{
+		"permission_id": permissionID,
+	})
+	return c.do(ctx, "DELETE", fmt.Sprintf("folder-permissions/%d", permissionID), nil, nil)
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestMirrorAppliesPlannedPermissions(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path+"?"+r.URL.Query().Get("filter.folderId"))
+		mu.Unlock()
+		if r.Method == http.MethodGet && r.URL.Query().Get("filter.folderId") == "2" {
+			// The target has group 10 with other roles, and group 30 which is not planned
+			_, _ = w.Write([]byte(`{"Records":[{"ID":100,"GroupID":10,"FolderAccessRoleID":1,"SecretAccessRoleID":1},
+				{"ID":300,"GroupID":30,"FolderAccessRoleID":1,"SecretAccessRoleID":1}]}`))
+			return
+		}
+		// The source gained group 40 after the plan
+		_, _ = w.Write([]byte(`{"Records":[{"ID":1,"GroupID":10,"FolderAccessRoleID":2,"SecretAccessRoleID":3},
+			{"ID":4,"GroupID":40,"FolderAccessRoleID":2,"SecretAccessRoleID":3}]}`))
+	}))
+	defer ts.Close()
+
+	planned, diags := folderPermissionSet([]folderPermission{{GroupID: 10, FolderAccessRoleID: 2, SecretAccessRoleID: 3}})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	state := FolderPermissionMirrorState{
+		SourceFolderID: types.Int64Value(1),
+		TargetFolderID: types.Int64Value(2),
+		Permissions:    planned,
+	}
+
+	r := &TssFolderPermissionMirrorResource{api: newTestAPIClient(ts.URL)}
+	if diags := r.mirror(context.Background(), &state); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	want := []string{
+		"GET /api/v1/folder-permissions?2",
+		"PUT /api/v1/folder-permissions/100?",
+		"DELETE /api/v1/folder-permissions/300?",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+	if !state.Permissions.Equal(planned) {
+		t.Errorf("permissions = %s, want the planned %s", state.Permissions, planned)
+	}
+}
@@ -0,0 +1,299 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &TssFolderTemplateRestrictionResource{}
+	_ resource.ResourceWithConfigure   = &TssFolderTemplateRestrictionResource{}
+	_ resource.ResourceWithImportState = &TssFolderTemplateRestrictionResource{}
+)
+
+// NewTssFolderTemplateRestrictionResource is a helper function to simplify the provider implementation.
+func NewTssFolderTemplateRestrictionResource() resource.Resource {
+	return &TssFolderTemplateRestrictionResource{}
+}
+
+// TssFolderTemplateRestrictionResource manages which secret templates may be used
+// to create secrets in a folder
+type TssFolderTemplateRestrictionResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// FolderTemplateRestrictionState defines the state structure for the folder template restriction resource
+type FolderTemplateRestrictionState struct {
+	ID          types.String `tfsdk:"id"`
+	FolderID    types.Int64  `tfsdk:"folder_id"`
+	TemplateIDs types.Set    `tfsdk:"template_ids"`
+}
+
+// Metadata provides the resource type name
+func (r *TssFolderTemplateRestrictionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_folder_template_restriction"
+}
+
+// Schema defines the schema for the resource
+func (r *TssFolderTemplateRestrictionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Restricts the secret templates that can be used to create secrets in a folder. " +
+			"Restrictions added outside of Terraform are removed on the next apply.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the folder.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"folder_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the folder to restrict.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"template_ids": schema.SetAttribute{
+				ElementType: types.Int64Type,
+				Required:    true,
+				Description: "The IDs of the secret templates allowed in the folder.",
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssFolderTemplateRestrictionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create restricts the folder to the planned templates
+func (r *TssFolderTemplateRestrictionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan FolderTemplateRestrictionState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var templateIDs []int64
+	resp.Diagnostics.Append(plan.TemplateIDs.ElementsAs(ctx, &templateIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folderID := int(plan.FolderID.ValueInt64())
+	tflog.Info(ctx, "Restricting folder templates", map[string]interface{}{
+		"folder_id":    folderID,
+		"template_ids": templateIDs,
+	})
+
+	if err := r.apply(ctx, folderID, templateIDs); err != nil {
+		resp.Diagnostics.AddError("Folder Template Restriction Error", fmt.Sprintf("Failed to restrict templates of folder %d: %s", folderID, err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(folderID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the templates allowed in the folder
+func (r *TssFolderTemplateRestrictionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state FolderTemplateRestrictionState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folderID := int(state.FolderID.ValueInt64())
+	templateIDs, err := r.api.folderTemplateRestrictions(ctx, folderID)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Folder not found, removing from state", map[string]interface{}{
+			"folder_id": folderID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Folder Template Restriction Error", fmt.Sprintf("Failed to read templates of folder %d: %s", folderID, err))
+		return
+	}
+
+	ids, diags := types.SetValueFrom(ctx, types.Int64Type, templateIDs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.TemplateIDs = ids
+	state.ID = types.StringValue(strconv.Itoa(folderID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update adds and removes templates so the folder allows exactly the planned ones
+func (r *TssFolderTemplateRestrictionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan FolderTemplateRestrictionState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var templateIDs []int64
+	resp.Diagnostics.Append(plan.TemplateIDs.ElementsAs(ctx, &templateIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folderID := int(plan.FolderID.ValueInt64())
+	if err := r.apply(ctx, folderID, templateIDs); err != nil {
+		resp.Diagnostics.AddError("Folder Template Restriction Error", fmt.Sprintf("Failed to restrict templates of folder %d: %s", folderID, err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(folderID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete lifts all template restrictions from the folder
+func (r *TssFolderTemplateRestrictionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state FolderTemplateRestrictionState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	folderID := int(state.FolderID.ValueInt64())
+	tflog.Info(ctx, "Removing folder template restrictions", map[string]interface{}{
+		"folder_id": folderID,
+	})
+
+	err := r.apply(ctx, folderID, nil)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Folder Template Restriction Error", fmt.Sprintf("Failed to remove template restrictions of folder %d: %s", folderID, err))
+	}
+}
+
+// ImportState imports a folder's restrictions by folder ID
+func (r *TssFolderTemplateRestrictionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	folderID, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected a folder ID, got %q.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("folder_id"), folderID)...)
+}
+
+// apply adds and removes restrictions so that the folder allows exactly templateIDs
+func (r *TssFolderTemplateRestrictionResource) apply(ctx context.Context, folderID int, templateIDs []int64) error {
+	current, err := r.api.folderTemplateRestrictions(ctx, folderID)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[int64]bool, len(templateIDs))
+	for _, id := range templateIDs {
+		wanted[id] = true
+	}
+	existing := make(map[int64]bool, len(current))
+	for _, id := range current {
+		existing[id] = true
+	}
+
+	for _, id := range templateIDs {
+		if existing[id] {
+			continue
+		}
+		tflog.Debug(ctx, "Allowing template in folder", map[string]interface{}{
+			"folder_id":   folderID,
+			"template_id": id,
+		})
+		if err := r.api.addFolderTemplateRestriction(ctx, folderID, int(id)); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range current {
+		if wanted[id] {
+			continue
+		}
+		tflog.Debug(ctx, "Disallowing template in folder", map[string]interface{}{
+			"folder_id":   folderID,
+			"template_id": id,
+		})
+		if err := r.api.removeFolderTemplateRestriction(ctx, folderID, int(id)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// folderTemplateRestrictions returns the IDs of the templates a folder is restricted to,
+// sorted ascending. An unrestricted folder has none.
+func (c *apiClient) folderTemplateRestrictions(ctx context.Context, folderID int) ([]int64, error) {
+	var folder struct {
+		AllowedTemplates []int64
+	}
+	if err := c.do(ctx, "GET", fmt.Sprintf("folders/%d", folderID), nil, &folder); err != nil {
+		return nil, err
+	}
+	sort.Slice(folder.AllowedTemplates, func(i, j int) bool { return folder.AllowedTemplates[i] < folder.AllowedTemplates[j] })
+	return folder.AllowedTemplates, nil
+}
+
+// addFolderTemplateRestriction allows a template to be used in a folder
+func (c *apiClient) addFolderTemplateRestriction(ctx context.Context, folderID, templateID int) error {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"folderId":         folderID,
+			"secretTemplateId": templateID,
+		},
+	}
+	return c.do(ctx, "POST", fmt.Sprintf("folders/%d/templates", folderID), body, nil)
+}
+
+// removeFolderTemplateRestriction stops a template from being used in a folder
+func (c *apiClient) removeFolderTemplateRestriction(ctx context.Context, folderID, templateID int) error {
+	return c.do(ctx, "DELETE", fmt.Sprintf("folders/%d/templates/%d", folderID, templateID), nil, nil)
+}
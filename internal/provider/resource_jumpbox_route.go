@@ -0,0 +1,268 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &TssJumpboxRouteResource{}
+	_ resource.ResourceWithConfigure   = &TssJumpboxRouteResource{}
+	_ resource.ResourceWithImportState = &TssJumpboxRouteResource{}
+)
+
+// NewTssJumpboxRouteResource is a helper function to simplify the provider implementation.
+func NewTssJumpboxRouteResource() resource.Resource {
+	return &TssJumpboxRouteResource{}
+}
+
+// TssJumpboxRouteResource manages a jumpbox route, the chain of SSH jumpboxes
+// a launcher connects through
+type TssJumpboxRouteResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// JumpboxRouteState defines the state structure for the jumpbox route resource
+type JumpboxRouteState struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Description   types.String `tfsdk:"description"`
+	Active        types.Bool   `tfsdk:"active"`
+	JumpSecretIDs types.List   `tfsdk:"jump_secret_ids"`
+}
+
+// jumpboxRoute is a jumpbox route as exchanged with Secret Server
+type jumpboxRoute struct {
+	ID          int                 `json:"id,omitempty"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Active      bool                `json:"active"`
+	Levels      []jumpboxRouteLevel `json:"levels"`
+}
+
+// jumpboxRouteLevel is one hop of a jumpbox route
+type jumpboxRouteLevel struct {
+	Order    int `json:"order"`
+	SecretID int `json:"secretId"`
+}
+
+// Metadata provides the resource type name
+func (r *TssJumpboxRouteResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_jumpbox_route"
+}
+
+// Schema defines the schema for the resource
+func (r *TssJumpboxRouteResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a jumpbox route: the chain of SSH jumpboxes that sessions launched from a secret connect through.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the jumpbox route.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the jumpbox route.",
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "The description of the jumpbox route.",
+			},
+			"active": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether the route can be used. Defaults to true.",
+			},
+			"jump_secret_ids": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Required:    true,
+				Description: "The IDs of the secrets of the jumpboxes to connect through, starting with the first hop.",
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssJumpboxRouteResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create creates the jumpbox route
+func (r *TssJumpboxRouteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan JumpboxRouteState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	route, diags := plan.expand(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Creating jumpbox route", map[string]interface{}{
+		"name": route.Name,
+		"hops": len(route.Levels),
+	})
+
+	var created jumpboxRoute
+	if err := r.api.do(ctx, "POST", "jumpbox-routes", map[string]interface{}{"data": route}, &created); err != nil {
+		resp.Diagnostics.AddError("Jumpbox Route Error", fmt.Sprintf("Failed to create jumpbox route: %s", err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(created.ID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the jumpbox route
+func (r *TssJumpboxRouteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state JumpboxRouteState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var route jumpboxRoute
+	err := r.api.do(ctx, "GET", "jumpbox-routes/"+state.ID.ValueString(), nil, &route)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Jumpbox route not found, removing from state", map[string]interface{}{
+			"id": state.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Jumpbox Route Error", fmt.Sprintf("Failed to read jumpbox route %s: %s", state.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(state.flatten(ctx, &route)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update updates the jumpbox route
+func (r *TssJumpboxRouteResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan JumpboxRouteState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	route, diags := plan.expand(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	route.ID, _ = strconv.Atoi(plan.ID.ValueString())
+
+	if err := r.api.do(ctx, "PUT", "jumpbox-routes/"+plan.ID.ValueString(), map[string]interface{}{"data": route}, nil); err != nil {
+		resp.Diagnostics.AddError("Jumpbox Route Error", fmt.Sprintf("Failed to update jumpbox route %s: %s", plan.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the jumpbox route
+func (r *TssJumpboxRouteResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state JumpboxRouteState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Deleting jumpbox route", map[string]interface{}{
+		"id": state.ID.ValueString(),
+	})
+
+	err := r.api.do(ctx, "DELETE", "jumpbox-routes/"+state.ID.ValueString(), nil, nil)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Jumpbox Route Error", fmt.Sprintf("Failed to delete jumpbox route %s: %s", state.ID.ValueString(), err))
+	}
+}
+
+// ImportState imports a jumpbox route by ID
+func (r *TssJumpboxRouteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// expand converts the state into the API model
+func (s JumpboxRouteState) expand(ctx context.Context) (*jumpboxRoute, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	route := &jumpboxRoute{
+		Name:        s.Name.ValueString(),
+		Description: s.Description.ValueString(),
+		Active:      s.Active.ValueBool(),
+	}
+
+	var secretIDs []int
+	diags.Append(s.JumpSecretIDs.ElementsAs(ctx, &secretIDs, false)...)
+	for i, secretID := range secretIDs {
+		route.Levels = append(route.Levels, jumpboxRouteLevel{Order: i + 1, SecretID: secretID})
+	}
+	return route, diags
+}
+
+// flatten copies the API model into the state, ordering the hops by level
+func (s *JumpboxRouteState) flatten(ctx context.Context, route *jumpboxRoute) diag.Diagnostics {
+	s.Name = types.StringValue(route.Name)
+	if route.Description != "" || !s.Description.IsNull() {
+		s.Description = types.StringValue(route.Description)
+	}
+	s.Active = types.BoolValue(route.Active)
+
+	secretIDs := make([]int64, len(route.Levels))
+	for _, level := range route.Levels {
+		if level.Order >= 1 && level.Order <= len(secretIDs) {
+			secretIDs[level.Order-1] = int64(level.SecretID)
+		}
+	}
+	ids, diags := types.ListValueFrom(ctx, types.Int64Type, secretIDs)
+	s.JumpSecretIDs = ids
+	return diags
+}
@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &TssJumpboxRouteAssignmentResource{}
+	_ resource.ResourceWithConfigure   = &TssJumpboxRouteAssignmentResource{}
+	_ resource.ResourceWithImportState = &TssJumpboxRouteAssignmentResource{}
+)
+
+// NewTssJumpboxRouteAssignmentResource is a helper function to simplify the provider implementation.
+func NewTssJumpboxRouteAssignmentResource() resource.Resource {
+	return &TssJumpboxRouteAssignmentResource{}
+}
+
+// TssJumpboxRouteAssignmentResource assigns a jumpbox route to a secret
+type TssJumpboxRouteAssignmentResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// JumpboxRouteAssignmentState defines the state structure for the jumpbox route assignment resource
+type JumpboxRouteAssignmentState struct {
+	ID       types.String `tfsdk:"id"`
+	SecretID types.Int64  `tfsdk:"secret_id"`
+	RouteID  types.Int64  `tfsdk:"route_id"`
+}
+
+// Metadata provides the resource type name
+func (r *TssJumpboxRouteAssignmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_jumpbox_route_assignment"
+}
+
+// Schema defines the schema for the resource
+func (r *TssJumpboxRouteAssignmentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Assigns a jumpbox route to a secret, so sessions launched from the secret connect through the route. " +
+			"A secret has at most one route. Import with the secret ID.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the secret.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secret_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the secret to assign the route to.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"route_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the jumpbox route.",
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssJumpboxRouteAssignmentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create assigns the route to the secret
+func (r *TssJumpboxRouteAssignmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan JumpboxRouteAssignmentState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretID := int(plan.SecretID.ValueInt64())
+	routeID := int(plan.RouteID.ValueInt64())
+	if err := r.api.setSecretJumpboxRoute(ctx, secretID, &routeID); err != nil {
+		resp.Diagnostics.AddError("Jumpbox Route Assignment Error", fmt.Sprintf("Failed to assign jumpbox route %d to secret %d: %s", routeID, secretID, err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(secretID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the route assigned to the secret
+func (r *TssJumpboxRouteAssignmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state JumpboxRouteAssignmentState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretID := int(state.SecretID.ValueInt64())
+	routeID, err := r.api.secretJumpboxRoute(ctx, secretID)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Jumpbox Route Assignment Error", fmt.Sprintf("Failed to read the jumpbox route of secret %d: %s", secretID, err))
+		return
+	}
+	if routeID == nil {
+		tflog.Warn(ctx, "Secret no longer has a jumpbox route, removing from state", map[string]interface{}{
+			"secret_id": secretID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.RouteID = types.Int64Value(int64(*routeID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update assigns the planned route to the secret
+func (r *TssJumpboxRouteAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan JumpboxRouteAssignmentState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretID := int(plan.SecretID.ValueInt64())
+	routeID := int(plan.RouteID.ValueInt64())
+	if err := r.api.setSecretJumpboxRoute(ctx, secretID, &routeID); err != nil {
+		resp.Diagnostics.AddError("Jumpbox Route Assignment Error", fmt.Sprintf("Failed to assign jumpbox route %d to secret %d: %s", routeID, secretID, err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the route from the secret
+func (r *TssJumpboxRouteAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state JumpboxRouteAssignmentState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretID := int(state.SecretID.ValueInt64())
+	err := r.api.setSecretJumpboxRoute(ctx, secretID, nil)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Jumpbox Route Assignment Error", fmt.Sprintf("Failed to remove the jumpbox route of secret %d: %s", secretID, err))
+	}
+}
+
+// ImportState imports an assignment by secret ID
+func (r *TssJumpboxRouteAssignmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	secretID, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected a secret ID, got %q.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, JumpboxRouteAssignmentState{
+		ID:       types.StringValue(req.ID),
+		SecretID: types.Int64Value(secretID),
+		RouteID:  types.Int64Null(),
+	})...)
+}
+
+// secretJumpboxRoute returns the ID of the jumpbox route of a secret, or nil when it has none
+func (c *apiClient) secretJumpboxRoute(ctx context.Context, secretID int) (*int, error) {
+	var selection struct {
+		JumpboxRouteID *int
+	}
+	if err := c.do(ctx, "GET", fmt.Sprintf("secrets/%d/jumpbox-route-selection", secretID), nil, &selection); err != nil {
+		return nil, err
+	}
+	return selection.JumpboxRouteID, nil
+}
+
+// setSecretJumpboxRoute assigns a jumpbox route to a secret. A nil routeID removes the route.
+func (c *apiClient) setSecretJumpboxRoute(ctx context.Context, secretID int, routeID *int) error {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"jumpboxRouteId": dirtyValue(routeID),
+		},
+	}
+	return c.do(ctx, "PATCH", fmt.Sprintf("secrets/%d/jumpbox-route-selection", secretID), body, nil)
+}
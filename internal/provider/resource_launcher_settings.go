@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// launcherSettingsID is the ID of the launcher settings, of which each Secret Server has one
+const launcherSettingsID = "launcher_settings"
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &TssLauncherSettingsResource{}
+	_ resource.ResourceWithConfigure   = &TssLauncherSettingsResource{}
+	_ resource.ResourceWithImportState = &TssLauncherSettingsResource{}
+)
+
+// NewTssLauncherSettingsResource is a helper function to simplify the provider implementation.
+func NewTssLauncherSettingsResource() resource.Resource {
+	return &TssLauncherSettingsResource{}
+}
+
+// TssLauncherSettingsResource manages the global protocol handler (launcher) settings
+type TssLauncherSettingsResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// LauncherSettingsState defines the state structure for the launcher settings resource
+type LauncherSettingsState struct {
+	ID                types.String `tfsdk:"id"`
+	AutoUpdateEnabled types.Bool   `tfsdk:"auto_update_enabled"`
+	AllowedVersions   types.Set    `tfsdk:"allowed_versions"`
+}
+
+// protocolHandlerSettings are the launcher settings of the general configuration
+type protocolHandlerSettings struct {
+	EnableAutoUpdate bool
+	AllowedVersions  []string
+}
+
+// Metadata provides the resource type name
+func (r *TssLauncherSettingsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_launcher_settings"
+}
+
+// Schema defines the schema for the resource
+func (r *TssLauncherSettingsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the global protocol handler (launcher) settings of an on-premises Secret Server. " +
+			"Secret Server has a single set of these settings, so declare this resource at most once per server. " +
+			"Destroying the resource leaves the settings as they are.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Always \"launcher_settings\".",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"auto_update_enabled": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether workstations automatically update the protocol handler.",
+			},
+			"allowed_versions": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Description: "The protocol handler versions allowed to launch sessions. Empty allows every version.",
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssLauncherSettingsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create applies the planned settings
+func (r *TssLauncherSettingsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan LauncherSettingsState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the settings from the server
+func (r *TssLauncherSettingsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state LauncherSettingsState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, err := r.api.launcherSettings(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Launcher Settings Error", fmt.Sprintf("Failed to read launcher settings: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.flatten(ctx, settings, &state)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update applies the planned settings
+func (r *TssLauncherSettingsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan LauncherSettingsState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete only removes the settings from state, since they cannot be deleted
+func (r *TssLauncherSettingsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	tflog.Info(ctx, "Launcher settings removed from state, the server keeps its current settings")
+}
+
+// ImportState imports the settings. Any import ID is accepted since there is only one set.
+func (r *TssLauncherSettingsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.Set(ctx, LauncherSettingsState{
+		ID:                types.StringValue(launcherSettingsID),
+		AutoUpdateEnabled: types.BoolNull(),
+		AllowedVersions:   types.SetNull(types.StringType),
+	})...)
+}
+
+// apply sends the known planned settings to the server and fills plan with the result
+func (r *TssLauncherSettingsResource) apply(ctx context.Context, plan *LauncherSettingsState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	update := map[string]interface{}{}
+	if !plan.AutoUpdateEnabled.IsUnknown() && !plan.AutoUpdateEnabled.IsNull() {
+		update["enableAutoUpdate"] = dirtyValue(plan.AutoUpdateEnabled.ValueBool())
+	}
+	if !plan.AllowedVersions.IsUnknown() && !plan.AllowedVersions.IsNull() {
+		var versions []string
+		diags.Append(plan.AllowedVersions.ElementsAs(ctx, &versions, false)...)
+		update["allowedVersions"] = dirtyValue(versions)
+	}
+
+	tflog.Info(ctx, "Updating launcher settings", map[string]interface{}{
+		"settings": len(update),
+	})
+
+	settings, err := r.api.updateLauncherSettings(ctx, update)
+	if err != nil {
+		diags.AddError("Launcher Settings Error", fmt.Sprintf("Failed to update launcher settings: %s", err))
+		return diags
+	}
+
+	diags.Append(r.flatten(ctx, settings, plan)...)
+	return diags
+}
+
+// flatten copies the server settings into state
+func (r *TssLauncherSettingsResource) flatten(ctx context.Context, settings *protocolHandlerSettings, state *LauncherSettingsState) diag.Diagnostics {
+	versions := settings.AllowedVersions
+	if versions == nil {
+		versions = []string{}
+	}
+	allowed, diags := types.SetValueFrom(ctx, types.StringType, versions)
+
+	state.ID = types.StringValue(launcherSettingsID)
+	state.AutoUpdateEnabled = types.BoolValue(settings.EnableAutoUpdate)
+	state.AllowedVersions = allowed
+	return diags
+}
+
+// dirtyValue wraps a value for the configuration endpoints, which only change fields marked dirty
+func dirtyValue(value interface{}) map[string]interface{} {
+	return map[string]interface{}{"dirty": true, "value": value}
+}
+
+// launcherSettings returns the protocol handler settings
+func (c *apiClient) launcherSettings(ctx context.Context) (*protocolHandlerSettings, error) {
+	var config struct {
+		ProtocolHandlerSettings protocolHandlerSettings
+	}
+	if err := c.do(ctx, "GET", "configuration/general", nil, &config); err != nil {
+		return nil, err
+	}
+	return &config.ProtocolHandlerSettings, nil
+}
+
+// updateLauncherSettings patches the protocol handler settings and returns the result
+func (c *apiClient) updateLauncherSettings(ctx context.Context, update map[string]interface{}) (*protocolHandlerSettings, error) {
+	if len(update) > 0 {
+		body := map[string]interface{}{
+			"data": map[string]interface{}{
+				"protocolHandlerSettings": update,
+			},
+		}
+		if err := c.do(ctx, "PATCH", "configuration/general", body, nil); err != nil {
+			return nil, err
+		}
+	}
+	return c.launcherSettings(ctx)
+}
@@ -2,7 +2,10 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -11,6 +14,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -22,6 +27,7 @@ var (
 	_ resource.Resource                = &TssSecretResource{}
 	_ resource.ResourceWithConfigure   = &TssSecretResource{}
 	_ resource.ResourceWithImportState = &TssSecretResource{}
+	_ resource.ResourceWithModifyPlan  = &TssSecretResource{}
 )
 
 // NewTssecretResource is a helper function to simplify the provider implementation.
@@ -31,50 +37,98 @@ func NewTssSecretResource() resource.Resource {
 
 // TssSecretResource defines the resource implementation
 type TssSecretResource struct {
-	client *server.Server
+	client   *server.Server
+	api      *apiClient
+	defaults callOptions
+	logging  loggingConfig
+	// fingerprintKey keys the value_fingerprint of fields; nil disables them
+	fingerprintKey []byte
+	// stateCipher decrypts the field values encrypted in state by earlier versions, if set
+	stateCipher *stateCipher
+	// identities are the provider's auth_alias credentials
+	identities map[string]providerIdentity
+	// workspace is the workspace the secrets are marked as managed by, if any
+	workspace string
 }
 
 // SecretResourceState defines the state structure for the secret resource
 type SecretResourceState struct {
-	ID                               types.String  `tfsdk:"id"`
-	Name                             types.String  `tfsdk:"name"`
-	FolderID                         types.String  `tfsdk:"folderid"`
-	SiteID                           types.String  `tfsdk:"siteid"`
-	SecretTemplateID                 types.String  `tfsdk:"secrettemplateid"`
-	Fields                           []SecretField `tfsdk:"fields"`
-	SshKeyArgs                       *SshKeyArgs   `tfsdk:"sshkeyargs"`
-	Active                           types.Bool    `tfsdk:"active"`
-	SecretPolicyID                   types.Int64   `tfsdk:"secretpolicyid"`
-	PasswordTypeWebScriptID          types.Int64   `tfsdk:"passwordtypewebscriptid"`
-	LauncherConnectAsSecretID        types.Int64   `tfsdk:"launcherconnectassecretid"`
-	CheckOutIntervalMinutes          types.Int64   `tfsdk:"checkoutintervalminutes"`
-	CheckedOut                       types.Bool    `tfsdk:"checkedout"`
-	CheckOutEnabled                  types.Bool    `tfsdk:"checkoutenabled"`
-	AutoChangeEnabled                types.Bool    `tfsdk:"autochangenabled"`
-	CheckOutChangePasswordEnabled    types.Bool    `tfsdk:"checkoutchangepasswordenabled"`
-	DelayIndexing                    types.Bool    `tfsdk:"delayindexing"`
-	EnableInheritPermissions         types.Bool    `tfsdk:"enableinheritpermissions"`
-	EnableInheritSecretPolicy        types.Bool    `tfsdk:"enableinheritsecretpolicy"`
-	ProxyEnabled                     types.Bool    `tfsdk:"proxyenabled"`
-	RequiresComment                  types.Bool    `tfsdk:"requirescomment"`
-	SessionRecordingEnabled          types.Bool    `tfsdk:"sessionrecordingenabled"`
-	WebLauncherRequiresIncognitoMode types.Bool    `tfsdk:"weblauncherrequiresincognitomode"`
+	ID                               types.String        `tfsdk:"id"`
+	Name                             types.String        `tfsdk:"name"`
+	NamePattern                      types.String        `tfsdk:"name_pattern"`
+	FolderID                         types.String        `tfsdk:"folderid"`
+	FolderPath                       types.String        `tfsdk:"folderpath"`
+	SiteID                           types.String        `tfsdk:"siteid"`
+	SecretTemplateID                 types.String        `tfsdk:"secrettemplateid"`
+	Fields                           []SecretField       `tfsdk:"fields"`
+	Field                            []SecretFieldBlock  `tfsdk:"field"`
+	SshKeyArgs                       *SshKeyArgs         `tfsdk:"sshkeyargs"`
+	Active                           types.Bool          `tfsdk:"active"`
+	SecretPolicyID                   types.Int64         `tfsdk:"secretpolicyid"`
+	PasswordTypeWebScriptID          types.Int64         `tfsdk:"passwordtypewebscriptid"`
+	LauncherConnectAsSecretID        types.Int64         `tfsdk:"launcherconnectassecretid"`
+	CheckOutIntervalMinutes          types.Int64         `tfsdk:"checkoutintervalminutes"`
+	CheckedOut                       types.Bool          `tfsdk:"checkedout"`
+	CheckOutEnabled                  types.Bool          `tfsdk:"checkoutenabled"`
+	AutoChangeEnabled                types.Bool          `tfsdk:"autochangenabled"`
+	CheckOutChangePasswordEnabled    types.Bool          `tfsdk:"checkoutchangepasswordenabled"`
+	DelayIndexing                    types.Bool          `tfsdk:"delayindexing"`
+	EnableInheritPermissions         types.Bool          `tfsdk:"enableinheritpermissions"`
+	EnableInheritSecretPolicy        types.Bool          `tfsdk:"enableinheritsecretpolicy"`
+	ProxyEnabled                     types.Bool          `tfsdk:"proxyenabled"`
+	RequiresComment                  types.Bool          `tfsdk:"requirescomment"`
+	SessionRecordingEnabled          types.Bool          `tfsdk:"sessionrecordingenabled"`
+	WebLauncherRequiresIncognitoMode types.Bool          `tfsdk:"weblauncherrequiresincognitomode"`
+	Retry                            *RetryModel         `tfsdk:"retry"`
+	Timeout                          types.String        `tfsdk:"timeout"`
+	OnAccessDenied                   types.String        `tfsdk:"on_access_denied"`
+	OnAttachmentDrift                types.String        `tfsdk:"on_attachment_drift"`
+	PreventDestroyUnlessInactive     types.Bool          `tfsdk:"prevent_destroy_unless_inactive"`
+	RotationTriggers                 types.Map           `tfsdk:"rotation_triggers"`
+	ExpireOnUpdate                   types.Bool          `tfsdk:"expire_on_update"`
+	AwaitApproval                    *AwaitApprovalModel `tfsdk:"await_approval"`
+	OutOfSync                        types.Bool          `tfsdk:"out_of_sync"`
+	LastRPCError                     types.String        `tfsdk:"last_rpc_error"`
+	FailIfOutOfSync                  types.Bool          `tfsdk:"fail_if_out_of_sync"`
+	Security                         types.Object        `tfsdk:"security"`
+	DoubleLockID                     types.Int64         `tfsdk:"double_lock_id"`
+	SessionRecording                 types.Object        `tfsdk:"session_recording"`
+	PopulateDefaults                 types.Bool          `tfsdk:"populate_template_defaults"`
+	AuthAlias                        types.String        `tfsdk:"auth_alias"`
+	FieldValues                      types.Map           `tfsdk:"field_values"`
+	PasswordGenerator                types.String        `tfsdk:"password_generator"`
+	AllowTakeover                    types.Bool          `tfsdk:"allow_takeover"`
+	SyncNameFromServer               types.Bool          `tfsdk:"sync_name_from_server"`
+	ReadFieldHistory                 types.Bool          `tfsdk:"read_field_history"`
+	ServerName                       types.String        `tfsdk:"server_name"`
+	FieldsMeta                       types.List          `tfsdk:"fields_meta"`
 }
 
 type SecretField struct {
-	FieldName        types.String `tfsdk:"fieldname"`
-	ItemValue        types.String `tfsdk:"itemvalue"`
-	ItemID           types.Int64  `tfsdk:"itemid"`
-	FieldID          types.Int64  `tfsdk:"fieldid"`
-	FileAttachmentID types.Int64  `tfsdk:"fileattachmentid"`
-	Slug             types.String `tfsdk:"slug"`
-	FieldDescription types.String `tfsdk:"fielddescription"`
-	Filename         types.String `tfsdk:"filename"`
-	IsFile           types.Bool   `tfsdk:"isfile"`
-	IsNotes          types.Bool   `tfsdk:"isnotes"`
-	IsPassword       types.Bool   `tfsdk:"ispassword"`
-	IsList           types.Bool   `tfsdk:"islist"`
-	ListType         types.String `tfsdk:"listtype"`
+	FieldName          types.String    `tfsdk:"fieldname"`
+	ItemValue          types.String    `tfsdk:"itemvalue"`
+	ItemValueWO        types.String    `tfsdk:"itemvalue_wo"`
+	ItemValueWOVersion types.String    `tfsdk:"itemvalue_wo_version"`
+	ItemID             types.Int64     `tfsdk:"itemid"`
+	FieldID            types.Int64     `tfsdk:"fieldid"`
+	FileAttachmentID   types.Int64     `tfsdk:"fileattachmentid"`
+	Slug               types.String    `tfsdk:"slug"`
+	FieldDescription   types.String    `tfsdk:"fielddescription"`
+	Filename           types.String    `tfsdk:"filename"`
+	IsFile             types.Bool      `tfsdk:"isfile"`
+	IsNotes            types.Bool      `tfsdk:"isnotes"`
+	IsPassword         types.Bool      `tfsdk:"ispassword"`
+	IsList             types.Bool      `tfsdk:"islist"`
+	ListType           types.String    `tfsdk:"listtype"`
+	SourcePath         types.String    `tfsdk:"source_path"`
+	ContentBase64      types.String    `tfsdk:"content_base64"`
+	ContentSHA256      types.String    `tfsdk:"content_sha256"`
+	HistoryLength      types.Int64     `tfsdk:"historylength"`
+	HistoryCount       types.Int64     `tfsdk:"historycount"`
+	ValueFingerprint   types.String    `tfsdk:"value_fingerprint"`
+	StoreValueInState  types.Bool      `tfsdk:"store_value_in_state"`
+	ValueHash          types.String    `tfsdk:"value_hash"`
+	PasswordPolicy     *PasswordPolicy `tfsdk:"password_policy"`
 }
 
 type SshKeyArgs struct {
@@ -95,6 +149,7 @@ func (r *TssSecretResource) Schema(ctx context.Context, req resource.SchemaReque
 	tflog.Trace(ctx, "Defining schema for TssSecretResource")
 
 	resp.Schema = schema.Schema{
+		Version: secretSchemaVersion,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:    true,
@@ -102,13 +157,35 @@ func (r *TssSecretResource) Schema(ctx context.Context, req resource.SchemaReque
 				Description: "The ID of the secret.",
 			},
 			"name": schema.StringAttribute{
-				Required:    true,
-				Description: "The name of the secret.",
+				Optional: true,
+				Computed: true,
+				Description: "The name of the secret. Required unless the template has a secret name pattern; " +
+					"then it is derived from the fields when unset, and must match the pattern when set.",
+			},
+			"sync_name_from_server": schema.BoolAttribute{
+				Optional: true,
+				Description: "Keep the name given to the secret in Secret Server, e.g. by an administrator in the UI, instead of renaming it back to name. " +
+					"The secret is still renamed when name changes in configuration.",
+			},
+			"server_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the secret in Secret Server. Differs from name when the secret was renamed outside Terraform and sync_name_from_server is set.",
+			},
+			"name_pattern": schema.StringAttribute{
+				Computed:    true,
+				Description: "The template's secret name pattern, e.g. \"{machine}\\{username}\", whose {field} placeholders refer to fields by slug or name. Empty when names are chosen freely.",
 			},
 			"folderid": schema.StringAttribute{ // Changed to string for backward compatibility
 				Required:    true,
 				Description: "The folder ID of the secret.",
 			},
+			"folderpath": schema.StringAttribute{
+				Computed:    true,
+				Description: "The full path of the secret's folder, e.g. \\IT\\Prod\\DBs.",
+				PlanModifiers: []planmodifier.String{
+					folderPathPlanModifier{},
+				},
+			},
 			"siteid": schema.StringAttribute{ // Changed to string for backward compatibility
 				Required:    true,
 				Description: "The site ID where the secret will be created.",
@@ -197,8 +274,92 @@ func (r *TssSecretResource) Schema(ctx context.Context, req resource.SchemaReque
 				Computed:    true,
 				Description: "Whether the web launcher requires incognito mode.",
 			},
+			"timeout":           resourceTimeoutAttribute(),
+			"security":          secretSecurityAttribute(),
+			"session_recording": secretSessionRecordingAttribute(),
+			"out_of_sync": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether Remote Password Changing last failed to sync the password with the target system.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_rpc_error": schema.StringAttribute{
+				Computed:    true,
+				Description: "Why the secret is out of sync, as reported by the last Remote Password Changing attempt.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"double_lock_id": schema.Int64Attribute{
+				Optional: true,
+				Description: "The ID of a DoubleLock protecting the secret. The lock is applied when the secret is created, so the secret is never unlocked; " +
+					"changing it recreates the secret. File fields cannot be set on double locked secrets.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"fields_meta":  fieldsMetaAttribute(),
+			"field_values": fieldValuesAttribute(),
+			"auth_alias": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name of a provider auth_alias block whose credentials to manage the secret with, instead of the provider's own.",
+			},
+			"allow_takeover": schema.BoolAttribute{
+				Optional: true,
+				Description: "Update the secret even when it is marked as managed by another workspace, and mark it as managed by this one. " +
+					"Only used when the provider's workspace is set.",
+			},
+			"read_field_history": schema.BoolAttribute{
+				Optional: true,
+				Description: "Read the number of previous values retained for each field into historycount. " +
+					"This takes a request per field on every refresh, so historycount is null unless set.",
+			},
+			"password_generator": schema.StringAttribute{
+				Optional: true,
+				Description: "How passwords are generated for password fields created without a value: \"server\" (default) asks Secret Server to generate them, " +
+					"\"local\" generates them with the provider's cryptographic random number generator, following the length and character rules of the field's password requirement. " +
+					"Use \"local\" where the generate-password endpoint is disabled by policy.",
+			},
+			"populate_template_defaults": schema.BoolAttribute{
+				Optional: true,
+				Description: "Include the required fields of the template that no fields block declares, so only the fields of interest need declaring. " +
+					"They are created empty, or with a generated password for password fields, and keep their value on update.",
+			},
+			"fail_if_out_of_sync": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Fail refresh, and therefore plan, when the secret is out of sync with the target system.",
+			},
+			"on_access_denied": schema.StringAttribute{
+				Optional: true,
+				Description: "What refresh does when the provider is no longer allowed to view the secret: " +
+					"\"error\" (default) fails the refresh, \"warn\" keeps the last known state and reports a warning for review.",
+			},
+			"prevent_destroy_unless_inactive": schema.BoolAttribute{
+				Optional: true,
+				Description: "Refuse to destroy the secret while it is active or checked out, so it must be deactivated in Secret Server first. " +
+					"Checked when planning and again when deleting.",
+			},
+			"expire_on_update": schema.BoolAttribute{
+				Optional: true,
+				Description: "Expire the secret in Secret Server after every update, so heartbeat and Remote Password Changing act on the new values " +
+					"right away rather than on the secret's expiration schedule. Failing to expire the secret is reported as a warning.",
+			},
+			"rotation_triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that regenerate the secret's generated values when any of them changes, like the keepers of random_password, " +
+					"e.g. { rotated = \"2026-10\" } set by a scheduled pipeline. Password fields and, with sshkeyargs.generatesshkeys, SSH key fields " +
+					"of fields blocks without a configured value get a new value on the next apply.",
+			},
+			"on_attachment_drift": schema.StringAttribute{
+				Optional: true,
+				Description: "What refresh does when a file attachment was replaced or removed outside of Terraform, so its checksum no longer matches content_sha256. Refresh only downloads an attachment when its attachment ID has changed: " +
+					"\"warn\" (default) reports a warning, \"error\" fails the refresh.",
+			},
 		},
 		Blocks: map[string]schema.Block{
+			"field": fieldBlock(),
 			"fields": schema.ListNestedBlock{
 				Description: "List of fields for the secret.",
 				NestedObject: schema.NestedBlockObject{
@@ -212,30 +373,45 @@ func (r *TssSecretResource) Schema(ctx context.Context, req resource.SchemaReque
 							Sensitive:   true,
 							Description: "The value of the field. For SSH key generation, this will be computed by the server.",
 							PlanModifiers: []planmodifier.String{
-								stringplanmodifier.UseStateForUnknown(),
 								sshKeyFieldPlanModifier{},
 								passwordFieldPlanModifier{},
 							},
 						},
+						"itemvalue_wo": schema.StringAttribute{
+							Optional:  true,
+							Sensitive: true,
+							WriteOnly: true,
+							Description: "The value of the field, sent to Secret Server but never stored in state, e.g. a password from an ephemeral resource. " +
+								"Written on create and whenever itemvalue_wo_version changes; itemvalue stays null. Requires Terraform 1.11 or later.",
+						},
+						"itemvalue_wo_version": schema.StringAttribute{
+							Optional:    true,
+							Description: "Required with itemvalue_wo. Change this value to write the current itemvalue_wo again.",
+						},
 						"itemid": schema.Int64Attribute{
-							Optional: true,
-							Computed: true,
+							Optional:           true,
+							Computed:           true,
+							DeprecationMessage: fieldMetaDeprecation,
 						},
 						"fieldid": schema.Int64Attribute{
-							Optional: true,
-							Computed: true,
+							Optional:           true,
+							Computed:           true,
+							DeprecationMessage: fieldMetaDeprecation,
 						},
 						"fileattachmentid": schema.Int64Attribute{
-							Optional: true,
-							Computed: true,
+							Optional:           true,
+							Computed:           true,
+							DeprecationMessage: fieldMetaDeprecation,
 						},
 						"slug": schema.StringAttribute{
-							Optional: true,
-							Computed: true,
+							Optional:           true,
+							Computed:           true,
+							DeprecationMessage: fieldMetaDeprecation,
 						},
 						"fielddescription": schema.StringAttribute{
-							Optional: true,
-							Computed: true,
+							Optional:           true,
+							Computed:           true,
+							DeprecationMessage: fieldMetaDeprecation,
 						},
 						"filename": schema.StringAttribute{
 							Optional: true,
@@ -261,6 +437,54 @@ func (r *TssSecretResource) Schema(ctx context.Context, req resource.SchemaReque
 							Optional: true,
 							Computed: true,
 						},
+						"source_path": schema.StringAttribute{
+							Optional: true,
+							Description: "The path of a local file uploaded as the attachment of a file field, instead of itemvalue. " +
+								"The content is not stored in state; it is uploaded again whenever its checksum changes. filename defaults to the file's name.",
+						},
+						"content_base64": schema.StringAttribute{
+							Optional:  true,
+							Sensitive: true,
+							Description: "The base64 encoded content uploaded as the attachment of a file field, instead of itemvalue, e.g. from base64encode(templatefile(...)). " +
+								"Up to 10 MiB; it is uploaded again whenever it changes.",
+						},
+						"content_sha256": schema.StringAttribute{
+							Optional: true,
+							Computed: true,
+							Description: "SHA-256 checksum of the file attachment content. Only set for file fields. " +
+								"With source_path it defaults to the checksum of the file; set it, e.g. to the content_sha256 of a local_file, when the file is only created on apply.",
+						},
+						"historylength": schema.Int64Attribute{
+							Computed: true,
+							Description: "The number of previous values the secret template retains for this field. " +
+								"Secret Server only sets retention on the template, for every secret using it, so it cannot be set per secret.",
+						},
+						"historycount": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The number of previous values currently retained for this field. Null unless read_field_history is set, or when the server does not expose field history.",
+							PlanModifiers: []planmodifier.Int64{
+								int64planmodifier.UseStateForUnknown(),
+							},
+						},
+						"value_fingerprint": schema.StringAttribute{
+							Computed:    true,
+							Sensitive:   true,
+							Description: "A hash of itemvalue keyed with the provider's value_fingerprint_key, which plans show as changed when the value changes. Only set when the provider's value_fingerprints is enabled.",
+						},
+						"store_value_in_state": schema.BoolAttribute{
+							Optional: true,
+							Description: "Set to false to keep the value of the field out of state, e.g. a generated password. itemvalue stays null and value_hash " +
+								"holds a salted hash of the value instead, which detects changes made outside of Terraform on refresh. A changed field written from itemvalue_wo " +
+								"then plans to write itemvalue_wo again. " +
+								"Conflicts with itemvalue; set the value with itemvalue_wo. Defaults to true.",
+						},
+						"value_hash": schema.StringAttribute{
+							Computed:    true,
+							Description: "The salted SHA-256 hash of the value of a field with store_value_in_state = false, as the hex salt and hash separated by a colon",
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"password_policy": passwordPolicyBlock(),
 					},
 				},
 			},
@@ -279,6 +503,8 @@ func (r *TssSecretResource) Schema(ctx context.Context, req resource.SchemaReque
 					},
 				},
 			},
+			"retry":          resourceRetryBlock(),
+			"await_approval": awaitApprovalBlock(),
 		},
 	}
 	tflog.Debug(ctx, "Schema definition complete for TssSecretResource")
@@ -292,12 +518,12 @@ func (r *TssSecretResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 
-	tflog.Debug(ctx, "Attempting to cast provider data to *server.Server")
-	client, ok := req.ProviderData.(*server.Server)
+	tflog.Debug(ctx, "Attempting to cast provider data to *TssProviderData")
+	providerData, ok := req.ProviderData.(*TssProviderData)
 
 	if !ok {
 		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
-			"expected_type": "*server.Server",
+			"expected_type": "*TssProviderData",
 			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
 		})
 		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
@@ -305,12 +531,52 @@ func (r *TssSecretResource) Configure(ctx context.Context, req resource.Configur
 	}
 
 	// Store the provider configuration in the resource
-	r.client = client
+	r.client = providerData.Server
+	r.logging = providerData.Logging
+	r.api = providerData.API
+	r.defaults = providerData.Defaults
+	r.fingerprintKey = providerData.FingerprintKey
+	r.stateCipher = providerData.StateCipher
+	r.identities = providerData.Identities
+	r.workspace = providerData.Workspace
 	tflog.Info(ctx, "Configuring TssSecretResource completed successfully")
 }
 
+// ModifyPlan aligns the planned fields with the prior state of the same fields,
+// plans their value fingerprints, and derives the name of secrets whose template
+// has a secret name pattern, so the planned name matches the one Secret Server
+// assigns
+func (r *TssSecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx = r.logging.context(ctx)
+	if req.Plan.Raw.IsNull() {
+		r.planDestroy(ctx, req, resp)
+		return
+	}
+
+	var plan SecretResourceState
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(alignPlannedFields(ctx, req, &plan)...)
+	resp.Diagnostics.Append(planSourceFiles(ctx, req, &plan)...)
+	resp.Diagnostics.Append(planRotation(ctx, req, resp, &plan)...)
+	resp.Diagnostics.Append(planValueHashes(ctx, req, &plan)...)
+	resp.Diagnostics.Append(planFieldHistory(ctx, req, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("fields"), plan.Fields)...)
+	resp.Diagnostics.Append(planValueFingerprints(ctx, &resp.Plan, plan.Fields, r.fingerprintKey)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.planName(ctx, req, resp, &plan)
+}
+
 // Create creates the resource
 func (r *TssSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
 	tflog.Info(ctx, "Creating TssSecretResource")
 	var plan SecretResourceState
 
@@ -335,6 +601,12 @@ func (r *TssSecretResource) Create(ctx context.Context, req resource.CreateReque
 		"has_ssh_key_args": plan.SshKeyArgs != nil,
 	})
 
+	r, aliasDiags := r.withAuthAlias(plan.AuthAlias)
+	resp.Diagnostics.Append(aliasDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Ensure the client configuration is set
 	if r.client == nil {
 		tflog.Error(ctx, "TSS client is not configured")
@@ -342,9 +614,49 @@ func (r *TssSecretResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
+	opts, optsDiags := r.defaults.forOperation("create").withOverrides(plan.Retry, plan.Timeout)
+	resp.Diagnostics.Append(optsDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withCallOptions(ctx, opts)
+
+	// Fields configured with field_values are written as fields blocks
+	fieldValues, fieldValuesDiags := configuredFieldValues(ctx, req.Config)
+	resp.Diagnostics.Append(fieldValuesDiags...)
+	writePlan := withFieldValues(&plan, fieldValues)
+	resp.Diagnostics.Append(r.resolveName(ctx, writePlan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.Name, plan.NamePattern = writePlan.Name, writePlan.NamePattern
+	client := newSDKClient(ctx, r.client, r.api, opts)
+
+	// Write-only values are only in the configuration
+	configFields, configDiags := configuredWriteOnlyFields(ctx, req.Config)
+	resp.Diagnostics.Append(configDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	writePlan, err := r.withWriteOnlyValues(ctx, client, writePlan, configFields, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Data Error", fmt.Sprintf("Failed to prepare write-only field values: %s", err))
+		return
+	}
+	if writePlan, err = withSourceFiles(ctx, writePlan); err != nil {
+		resp.Diagnostics.AddError("Secret Data Error", err.Error())
+		return
+	}
+
+	wait, waitDiags := parseAwaitApproval(plan.AwaitApproval)
+	resp.Diagnostics.Append(waitDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get the secret data
 	tflog.Debug(ctx, "Preparing secret data for creation")
-	newSecret, err := r.generatePassword(ctx, &plan, r.client)
+	newSecret, err := r.generatePassword(ctx, writePlan, client)
 	if err != nil {
 		tflog.Error(ctx, "Failed to prepare secret data", map[string]interface{}{
 			"error": err.Error(),
@@ -362,7 +674,20 @@ func (r *TssSecretResource) Create(ctx context.Context, req resource.CreateReque
 	})
 
 	// Use the client to create the secret
-	createdSecret, err := r.client.CreateSecret(*newSecret)
+	createdSecret, err := writeWithApproval(ctx, r.api, wait, func() (*server.Secret, error) {
+		if !plan.DoubleLockID.IsNull() {
+			return r.api.writeDoubleLockedSecret(ctx, client, *newSecret, int(plan.DoubleLockID.ValueInt64()))
+		}
+		return client.CreateSecret(*newSecret)
+	})
+	if approvalErr, ok := asApprovalRequired(err); ok {
+		tflog.Error(ctx, "Secret creation requires approval", map[string]interface{}{
+			"request_id": approvalErr.RequestID,
+			"name":       newSecret.Name,
+		})
+		resp.Diagnostics.Append(approvalRequiredDiagnostic("create", approvalErr))
+		return
+	}
 	if err != nil {
 		tflog.Error(ctx, "Failed to create secret in TSS", map[string]interface{}{
 			"error":       err.Error(),
@@ -380,9 +705,19 @@ func (r *TssSecretResource) Create(ctx context.Context, req resource.CreateReque
 		"name": createdSecret.Name,
 	})
 
+	// Security details are not part of the secret model, so they are applied separately
+	if r.api != nil {
+		resp.Diagnostics.Append(r.api.applySecretSecurity(ctx, createdSecret.ID, plan.Security)...)
+		resp.Diagnostics.Append(r.api.applySecretSessionRecording(ctx, createdSecret.ID, plan.SessionRecording)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(r.markWorkspace(ctx, createdSecret.ID)...)
+
 	// Refresh state - let Terraform accept the computed values from the server
 	tflog.Debug(ctx, "Refreshing state with created secret data")
-	newState, readDiags := r.readSecretByID(ctx, stringCreatedSecret)
+	newState, readDiags := r.readSecretByID(ctx, client, stringCreatedSecret, nil, plan.ReadFieldHistory.ValueBool())
 	resp.Diagnostics.Append(readDiags...)
 	if resp.Diagnostics.HasError() {
 		tflog.Error(ctx, "Failed to refresh state after creation", map[string]interface{}{
@@ -395,6 +730,9 @@ func (r *TssSecretResource) Create(ctx context.Context, req resource.CreateReque
 	tflog.Debug(ctx, "Reordering fields to match original state order")
 	newState.Fields = r.reorderFieldsToMatchPlan(ctx, plan.Fields, newState.Fields)
 
+	preserveConfigOnlyAttributes(newState, &plan)
+	newState.ServerName = newState.Name
+
 	// Preserve the SSH key args from the plan since the server doesn't return them
 	if plan.SshKeyArgs != nil {
 		newState.SshKeyArgs = plan.SshKeyArgs
@@ -410,9 +748,13 @@ func (r *TssSecretResource) Create(ctx context.Context, req resource.CreateReque
 			// Find the matching field in the plan
 			for _, planField := range plan.Fields {
 				if planField.FieldName.ValueString() == field.FieldName.ValueString() && planField.IsFile.ValueBool() {
-					// Preserve FileAttachmentID and Filename
-					newState.Fields[i].FileAttachmentID = planField.FileAttachmentID
-					newState.Fields[i].Filename = planField.Filename
+					// Preserve FileAttachmentID and Filename, unless only known now
+					if !planField.FileAttachmentID.IsUnknown() {
+						newState.Fields[i].FileAttachmentID = planField.FileAttachmentID
+					}
+					if !planField.Filename.IsUnknown() {
+						newState.Fields[i].Filename = planField.Filename
+					}
 					tflog.Trace(ctx, "Preserved file attachment info", map[string]interface{}{
 						"field":              field.FieldName.ValueString(),
 						"file_attachment_id": planField.FileAttachmentID.ValueInt64(),
@@ -425,6 +767,12 @@ func (r *TssSecretResource) Create(ctx context.Context, req resource.CreateReque
 	}
 
 	// Set the state
+	clearWriteOnlyValues(newState.Fields, plan.Fields)
+	clearSourceFileValues(newState.Fields, plan.Fields)
+	keepPasswordPolicies(newState.Fields, plan.Fields)
+	resp.Diagnostics.Append(hashFieldValues(ctx, newState.ID.ValueString(), newState.Fields, plan.Fields, false)...)
+	resp.Diagnostics.Append(setFieldValues(newState, &plan, !fieldValues.IsNull())...)
+	setValueFingerprints(newState.Fields, r.fingerprintKey)
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -441,6 +789,10 @@ func (r *TssSecretResource) Create(ctx context.Context, req resource.CreateReque
 }
 
 func (r *TssSecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
 	tflog.Debug(ctx, "Reading TssSecretResource")
 	var state SecretResourceState
 
@@ -464,6 +816,12 @@ func (r *TssSecretResource) Read(ctx context.Context, req resource.ReadRequest,
 	// Store the original field order from the current state
 	originalFields := state.Fields
 
+	r, aliasDiags := r.withAuthAlias(state.AuthAlias)
+	resp.Diagnostics.Append(aliasDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Ensure the client configuration is set
 	if r.client == nil {
 		tflog.Error(ctx, "TSS client is not configured")
@@ -471,12 +829,40 @@ func (r *TssSecretResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	opts, optsDiags := r.defaults.forOperation("read").withOverrides(state.Retry, state.Timeout)
+	resp.Diagnostics.Append(optsDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withCallOptions(ctx, opts)
+
+	// Data sources and ephemeral resources may be reading the same secret
+	unlock, err := lockSecretID(ctx, secretID)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Lock Error", fmt.Sprintf("Gave up waiting for another operation on secret %s: %s", secretID, err))
+		return
+	}
+	defer unlock()
+
 	tflog.Info(ctx, "Reading secret from TSS", map[string]interface{}{
 		"id": secretID,
 	})
 
 	// Retrieve the secret
-	newState, readDiags := r.readSecretByID(ctx, state.ID.ValueString())
+	newState, readDiags := r.readSecretByID(ctx, newSDKClient(ctx, r.client, r.api, opts), state.ID.ValueString(), state.Fields, state.ReadFieldHistory.ValueBool())
+
+	// A secret whose permissions were revoked still exists, so optionally keep it
+	// in state for review instead of failing the whole refresh
+	if readDiags.Contains(accessDeniedDiagnostic(secretID)) && state.OnAccessDenied.ValueString() == "warn" {
+		tflog.Warn(ctx, "Access to secret denied, keeping last known state", map[string]interface{}{
+			"id": secretID,
+		})
+		resp.Diagnostics.AddWarning("Secret Access Denied",
+			fmt.Sprintf("The provider is no longer allowed to view secret %s, so its last known state was kept. "+
+				"Review the secret's permissions; the provider's user needs at least View permission on the secret or its folder.", secretID))
+		return
+	}
+
 	resp.Diagnostics.Append(readDiags...)
 	if resp.Diagnostics.HasError() {
 		tflog.Error(ctx, "Failed to read secret from TSS", map[string]interface{}{
@@ -494,6 +880,14 @@ func (r *TssSecretResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	tflog.Debug(ctx, "Reordering fields to match original state order")
 	newState.Fields = r.reorderFieldsToMatchPlan(ctx, originalFields, newState.Fields)
+	clearWriteOnlyValues(newState.Fields, originalFields)
+	clearSourceFileValues(newState.Fields, originalFields)
+	keepPasswordPolicies(newState.Fields, originalFields)
+	resp.Diagnostics.Append(hashFieldValues(ctx, newState.ID.ValueString(), newState.Fields, originalFields, true)...)
+	resp.Diagnostics.Append(r.keepEncryptedValues(ctx, newState.Fields, originalFields)...)
+
+	preserveConfigOnlyAttributes(newState, &state)
+	resp.Diagnostics.Append(trackRename(ctx, newState, &state)...)
 
 	// Preserve the SSH key args from the current state since the server doesn't return them
 	if state.SshKeyArgs != nil {
@@ -540,13 +934,42 @@ func (r *TssSecretResource) Read(ctx context.Context, req resource.ReadRequest,
 		}
 	}
 
+	// Detect file attachments that were replaced outside of Terraform
+	resp.Diagnostics.Append(attachmentDrift(ctx, secretID, newState.Fields, state.Fields, state.OnAttachmentDrift)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Refresh the field values held in state. A secret configured with
+	// field_values or field blocks has no fields blocks to refresh.
+	fieldValues, fieldValuesDiags := fieldValuesValue(newState.Fields, state.FieldValues)
+	resp.Diagnostics.Append(fieldValuesDiags...)
+	newState.FieldValues = fieldValues
+	newState.Field = fieldBlocksValue(newState.Fields, state.Field)
+	if len(originalFields) == 0 && (!state.FieldValues.IsNull() || len(state.Field) > 0) {
+		newState.Fields = originalFields
+	}
+
 	// Set the state
+	setValueFingerprints(newState.Fields, r.fingerprintKey)
 	diags = resp.State.Set(ctx, newState)
 	resp.Diagnostics.Append(diags...)
+
+	// Catch broken rotation before anything is planned against the secret
+	if newState.FailIfOutOfSync.ValueBool() && newState.OutOfSync.ValueBool() {
+		tflog.Error(ctx, "Secret is out of sync", map[string]interface{}{
+			"id":    secretID,
+			"error": newState.LastRPCError.ValueString(),
+		})
+		resp.Diagnostics.AddError("Secret Out Of Sync",
+			fmt.Sprintf("Secret %s is out of sync with its target system: %s. Fix Remote Password Changing for the secret, "+
+				"or unset fail_if_out_of_sync to plan anyway.", secretID, newState.LastRPCError.ValueString()))
+	}
 }
 
 // Update updates the resource
 func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
 	tflog.Info(ctx, "Updating TssSecretResource")
 	var plan SecretResourceState
 	var state SecretResourceState
@@ -564,6 +987,21 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	// Values encrypted in state by earlier versions are written to Secret Server
+	// decrypted, and kept encrypted while they do not change
+	planned := plan.Fields
+	var decryptDiags diag.Diagnostics
+	plan.Fields, decryptDiags = r.decryptedFields(plan.Fields)
+	resp.Diagnostics.Append(decryptDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Fields, decryptDiags = r.decryptedFields(state.Fields)
+	resp.Diagnostics.Append(decryptDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	secretID := state.ID.ValueString()
 	tflog.Debug(ctx, "Update configuration", map[string]interface{}{
 		"id":           secretID,
@@ -572,6 +1010,12 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 		"field_count":  len(plan.Fields),
 	})
 
+	r, aliasDiags := r.withAuthAlias(plan.AuthAlias)
+	resp.Diagnostics.Append(aliasDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Ensure the client configuration is set
 	if r.client == nil {
 		tflog.Error(ctx, "TSS client is not configured")
@@ -579,10 +1023,105 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	opts, optsDiags := r.defaults.forOperation("update").withOverrides(plan.Retry, plan.Timeout)
+	resp.Diagnostics.Append(optsDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withCallOptions(ctx, opts)
+
+	// Fields configured with field_values are written as fields blocks
+	fieldValues, fieldValuesDiags := configuredFieldValues(ctx, req.Config)
+	resp.Diagnostics.Append(fieldValuesDiags...)
+	writePlan := withFieldValues(&plan, fieldValues)
+	resp.Diagnostics.Append(r.resolveName(ctx, writePlan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.Name, plan.NamePattern = writePlan.Name, writePlan.NamePattern
+	client := newSDKClient(ctx, r.client, r.api, opts)
+
+	wait, waitDiags := parseAwaitApproval(plan.AwaitApproval)
+	resp.Diagnostics.Append(waitDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	unlock, err := lockSecretID(ctx, secretID)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Lock Error", fmt.Sprintf("Gave up waiting for another operation on secret %s: %s", secretID, err))
+		return
+	}
+	defer unlock()
+
+	// Refuse to update a secret that another workspace manages
+	if id, err := strconv.Atoi(secretID); err == nil {
+		resp.Diagnostics.Append(r.claimWorkspace(ctx, id, plan.AllowTakeover.ValueBool())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// When the update only fills fields that were empty, write those fields
+	// alone so items changed outside Terraform are left as they are
+	// A changed itemvalue_wo_version or source file needs the full update to
+	// write the value
+	if writeOnlyVersionChanged(&plan, &state) {
+		tflog.Debug(ctx, "Write-only field version changed, updating all fields")
+	} else if sourceFilesChanged(&plan, &state) {
+		tflog.Debug(ctx, "Source file changed, updating all fields")
+	} else if rotationRequested(&plan, &state) {
+		tflog.Debug(ctx, "Rotation triggers changed, updating all fields")
+	} else if added := r.addedFields(ctx, req, client, &plan, &state); added != nil {
+		id, _ := strconv.Atoi(secretID)
+		err := r.addFields(ctx, id, wait, added)
+		if approvalErr, ok := asApprovalRequired(err); ok {
+			resp.Diagnostics.Append(approvalRequiredDiagnostic("update", approvalErr))
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Secret Update Error", fmt.Sprintf("Failed to add field to secret: %s", err))
+			return
+		}
+		resp.Diagnostics.Append(r.expireAfterUpdate(ctx, id, plan.ExpireOnUpdate)...)
+		hasSshKeyArgs := state.SshKeyArgs != nil &&
+			(state.SshKeyArgs.GenerateSshKeys.ValueBool() || state.SshKeyArgs.GeneratePassphrase.ValueBool())
+		r.refreshAfterUpdate(ctx, client, &plan, &state, planned, hasSshKeyArgs, !fieldValues.IsNull(), resp)
+		return
+	}
+
+	// Write-only values are only in the configuration; unchanged ones are
+	// read from the secret, as state does not hold them
+	configFields, configDiags := configuredWriteOnlyFields(ctx, req.Config)
+	resp.Diagnostics.Append(configDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	writePlan, err = r.withWriteOnlyValues(ctx, client, writePlan, configFields, &state)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Data Error", fmt.Sprintf("Failed to prepare write-only field values: %s", err))
+		return
+	}
+	if writePlan, err = r.withHashedValues(ctx, client, writePlan, &state); err != nil {
+		resp.Diagnostics.AddError("Secret Data Error", fmt.Sprintf("Failed to read the values of fields kept out of state: %s", err))
+		return
+	}
+	if len(plan.Field) > 0 {
+		if writePlan, err = r.keepUnsetFieldBlockValues(ctx, client, writePlan, &state); err != nil {
+			resp.Diagnostics.AddError("Secret Data Error", fmt.Sprintf("Failed to read the current field values: %s", err))
+			return
+		}
+	}
+	if writePlan, err = withSourceFiles(ctx, writePlan); err != nil {
+		resp.Diagnostics.AddError("Secret Data Error", err.Error())
+		return
+	}
+	writePlan = withServerName(writePlan, &state)
+
 	// Get the secret data
 	// During update, we shouldn't send SSH key generation parameters
 	// because the server doesn't support SSH key generation during update
-	updatePlan := plan
+	updatePlan := *writePlan
 
 	// Check if SSH key generation was requested in the original creation
 	hasSshKeyArgs := false
@@ -598,7 +1137,7 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 
 	// Prepare the updated secret data
 	tflog.Debug(ctx, "Preparing updated secret data")
-	updatedSecret, err := r.getSecretData(ctx, &updatePlan, r.client)
+	updatedSecret, err := r.getSecretData(ctx, &updatePlan, client)
 	if err != nil {
 		tflog.Error(ctx, "Failed to prepare secret data for update", map[string]interface{}{
 			"error": err.Error(),
@@ -607,9 +1146,13 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	// If we have SSH key fields, preserve the existing values from the current state
+	// If we have SSH key fields, preserve the existing values from the current state.
+	// Write-only fields and fields kept out of state already hold their value.
 	for i, field := range updatedSecret.Fields {
 		fieldName := field.FieldName
+		if isWriteOnlyField(plan.Fields, fieldName) || isHashedField(plan.Fields, fieldName) {
+			continue
+		}
 
 		isSSHKeyField := hasSshKeyArgs && (strings.Contains(strings.ToLower(fieldName), "key") ||
 			strings.Contains(strings.ToLower(fieldName), "passphrase"))
@@ -672,6 +1215,16 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 		}
 	}
 
+	// Regenerate the values rotation_triggers planned as unknown
+	if err := r.rotateGeneratedValues(ctx, client, &plan, updatedSecret); err != nil {
+		resp.Diagnostics.AddError("Secret Rotation Error", fmt.Sprintf("Failed to rotate the generated values of the secret: %s", err))
+		return
+	}
+
+	if plan.PopulateDefaults.ValueBool() {
+		keepUndeclaredValues(updatedSecret, plan.Fields, state.Fields)
+	}
+
 	us := state.ID.ValueString()
 	ustoi, err := strconv.Atoi(us)
 	if err != nil {
@@ -690,7 +1243,21 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 		"name": updatedSecret.Name,
 	})
 
-	_, err = r.client.UpdateSecret(*updatedSecret)
+	_, err = writeWithApproval(ctx, r.api, wait, func() (*server.Secret, error) {
+		if !plan.DoubleLockID.IsNull() {
+			// An update through the SDK would not carry the lock
+			return r.api.writeDoubleLockedSecret(ctx, client, *updatedSecret, int(plan.DoubleLockID.ValueInt64()))
+		}
+		return client.UpdateSecret(*updatedSecret)
+	})
+	if approvalErr, ok := asApprovalRequired(err); ok {
+		tflog.Error(ctx, "Secret update requires approval", map[string]interface{}{
+			"request_id": approvalErr.RequestID,
+			"id":         ustoi,
+		})
+		resp.Diagnostics.Append(approvalRequiredDiagnostic("update", approvalErr))
+		return
+	}
 	if err != nil {
 		tflog.Error(ctx, "Failed to update secret in TSS", map[string]interface{}{
 			"id":    ustoi,
@@ -706,12 +1273,28 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 		"name": updatedSecret.Name,
 	})
 
+	if r.api != nil {
+		resp.Diagnostics.Append(r.api.applySecretSecurity(ctx, ustoi, plan.Security)...)
+		resp.Diagnostics.Append(r.api.applySecretSessionRecording(ctx, ustoi, plan.SessionRecording)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(r.expireAfterUpdate(ctx, ustoi, plan.ExpireOnUpdate)...)
+
+	r.refreshAfterUpdate(ctx, client, &plan, &state, planned, hasSshKeyArgs, !fieldValues.IsNull(), resp)
+}
+
+// refreshAfterUpdate reads the updated secret back into state, keeping the
+// attributes the server does not return. planned are the planned fields, whose
+// values may be encrypted where plan holds them decrypted.
+func (r *TssSecretResource) refreshAfterUpdate(ctx context.Context, client sdkClient, plan, state *SecretResourceState, planned []SecretField, hasSshKeyArgs, fieldValuesSet bool, resp *resource.UpdateResponse) {
 	// Refresh state
-	newState, readDiags := r.readSecretByID(ctx, us)
+	newState, readDiags := r.readSecretByID(ctx, client, state.ID.ValueString(), nil, plan.ReadFieldHistory.ValueBool())
 	resp.Diagnostics.Append(readDiags...)
 	if resp.Diagnostics.HasError() {
 		tflog.Error(ctx, "Failed to refresh state after update", map[string]interface{}{
-			"id":          secretID,
+			"id":          state.ID.ValueString(),
 			"diagnostics": resp.Diagnostics.Errors(),
 		})
 		return
@@ -720,6 +1303,9 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 	tflog.Debug(ctx, "Reordering fields to match original state order")
 	newState.Fields = r.reorderFieldsToMatchPlan(ctx, plan.Fields, newState.Fields)
 
+	preserveConfigOnlyAttributes(newState, plan)
+	resp.Diagnostics.Append(trackRename(ctx, newState, plan)...)
+
 	// Preserve the SSH key args from the plan since the server doesn't return them
 	if plan.SshKeyArgs != nil {
 		newState.SshKeyArgs = plan.SshKeyArgs
@@ -767,12 +1353,19 @@ func (r *TssSecretResource) Update(ctx context.Context, req resource.UpdateReque
 	}
 
 	// Set the state
-	diags = resp.State.Set(ctx, newState)
-	resp.Diagnostics.Append(diags...)
+	clearWriteOnlyValues(newState.Fields, plan.Fields)
+	clearSourceFileValues(newState.Fields, plan.Fields)
+	keepPasswordPolicies(newState.Fields, plan.Fields)
+	resp.Diagnostics.Append(hashFieldValues(ctx, newState.ID.ValueString(), newState.Fields, plan.Fields, false)...)
+	resp.Diagnostics.Append(r.keepEncryptedValues(ctx, newState.Fields, planned)...)
+	resp.Diagnostics.Append(setFieldValues(newState, plan, fieldValuesSet)...)
+	setValueFingerprints(newState.Fields, r.fingerprintKey)
+	resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
 }
 
 // Delete deletes the resource
 func (r *TssSecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
 	tflog.Info(ctx, "Deleting TSS secret")
 	var state SecretResourceState
 
@@ -793,6 +1386,12 @@ func (r *TssSecretResource) Delete(ctx context.Context, req resource.DeleteReque
 		"name": name,
 	})
 
+	r, aliasDiags := r.withAuthAlias(state.AuthAlias)
+	resp.Diagnostics.Append(aliasDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Ensure the client configuration is set
 	if r.client == nil {
 		tflog.Error(ctx, "TSS client is not configured")
@@ -813,8 +1412,27 @@ func (r *TssSecretResource) Delete(ctx context.Context, req resource.DeleteReque
 		"name": name,
 	})
 
+	unlock, err := lockSecretID(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Lock Error", fmt.Sprintf("Gave up waiting for another operation on secret %s: %s", id, err))
+		return
+	}
+	defer unlock()
+
+	resp.Diagnostics.Append(r.checkDeletionAllowed(ctx, id, state.PreventDestroyUnlessInactive)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	opts, optsDiags := r.defaults.forOperation("delete").withOverrides(state.Retry, state.Timeout)
+	resp.Diagnostics.Append(optsDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx = withCallOptions(ctx, opts)
+
 	// Delete the secret
-	err = r.client.DeleteSecret(idtoi)
+	err = newSDKClient(ctx, r.client, r.api, opts).DeleteSecret(idtoi)
 	if err != nil {
 		tflog.Error(ctx, "Failed to delete secret from TSS", map[string]interface{}{
 			"id":    idtoi,
@@ -881,6 +1499,7 @@ func (r *TssSecretResource) reorderFieldsToMatchPlan(ctx context.Context, planFi
 
 // Support import of Secret Resources via ID
 func (r *TssSecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = r.logging.context(ctx)
 	tflog.Trace(ctx, "Starting ImportState", map[string]interface{}{
 		"import id": req.ID,
 	})
@@ -889,7 +1508,7 @@ func (r *TssSecretResource) ImportState(ctx context.Context, req resource.Import
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func (r *TssSecretResource) generatePassword(ctx context.Context, state *SecretResourceState, client *server.Server) (*server.Secret, error) {
+func (r *TssSecretResource) generatePassword(ctx context.Context, state *SecretResourceState, client sdkClient) (*server.Secret, error) {
 	tflog.Debug(ctx, "Preparing secret data with password generation")
 
 	secret, err := r.getSecretData(ctx, state, client)
@@ -921,7 +1540,7 @@ func (r *TssSecretResource) generatePassword(ctx context.Context, state *SecretR
 
 		if templateField != nil && templateField.IsPassword {
 			if field.ItemValue == "" {
-				generatedPassword, err := client.GeneratePassword(templateField.FieldSlugName, template)
+				generatedPassword, err := r.newPassword(ctx, state, client, template, templateField)
 				if err != nil {
 					tflog.Error(ctx, "Failed to generate password", map[string]interface{}{
 						"field": field.FieldName,
@@ -945,7 +1564,11 @@ func (r *TssSecretResource) generatePassword(ctx context.Context, state *SecretR
 	return secret, nil
 }
 
-func (r *TssSecretResource) readSecretByID(ctx context.Context, id string) (*SecretResourceState, diag.Diagnostics) {
+// readSecretByID reads secret id into a new state. prior are the fields of the
+// state being refreshed, whose unchanged attachments are not downloaded again,
+// or nil to download every attachment. historyCounts also reads the number of
+// retained values of each field.
+func (r *TssSecretResource) readSecretByID(ctx context.Context, client sdkClient, id string, prior []SecretField, historyCounts bool) (*SecretResourceState, diag.Diagnostics) {
 	tflog.Debug(ctx, "Reading secret by ID", map[string]interface{}{
 		"id": id,
 	})
@@ -961,13 +1584,23 @@ func (r *TssSecretResource) readSecretByID(ctx context.Context, id string) (*Sec
 		}
 	}
 
-	// Retrieve the secret using the provided client
-	secret, err := r.client.Secret(secretID)
+	// Retrieve the secret using the provided client. On refresh only the
+	// attachments replaced since the prior state are downloaded.
+	var secret *server.Secret
+	var unchanged map[string]SecretField
+	if prior == nil {
+		secret, err = client.Secret(secretID)
+	} else if secret, err = client.SecretMetadata(secretID); err == nil {
+		unchanged, err = loadChangedAttachments(ctx, client, secret, prior)
+	}
 	if err != nil {
 		tflog.Error(ctx, "Failed to retrieve secret", map[string]interface{}{
 			"id":    secretID,
 			"error": err.Error(),
 		})
+		if errorStatusCode(err) == http.StatusForbidden {
+			return nil, diag.Diagnostics{accessDeniedDiagnostic(id)}
+		}
 		return nil, diag.Diagnostics{
 			diag.NewErrorDiagnostic("Secret Retrieval Error", fmt.Sprintf("Failed to retrieve secret: %s", err)),
 		}
@@ -989,10 +1622,111 @@ func (r *TssSecretResource) readSecretByID(ctx context.Context, id string) (*Sec
 		}
 	}
 
+	keepUnchangedAttachments(state.Fields, unchanged)
+
+	r.populateFieldHistory(withCallOptions(ctx, client.opts), secret, state, historyCounts)
+
+	fieldsMeta, diags := fieldsMetaValue(state.Fields)
+	if diags.HasError() {
+		return nil, diags
+	}
+	state.FieldsMeta = fieldsMeta
+
+	state.Security = types.ObjectNull(secretSecurityAttrTypes)
+	state.SessionRecording = types.ObjectNull(secretSessionRecordingAttrTypes)
+	if r.api != nil {
+		details, err := r.api.secretSecurity(ctx, secretID)
+		if err != nil {
+			tflog.Debug(ctx, "Unable to read secret security details", map[string]interface{}{
+				"id":    secretID,
+				"error": err.Error(),
+			})
+		} else {
+			security, diags := secretSecurityObject(details)
+			if diags.HasError() {
+				return nil, diags
+			}
+			state.Security = security
+
+			recording, diags := secretSessionRecordingObject(details)
+			if diags.HasError() {
+				return nil, diags
+			}
+			state.SessionRecording = recording
+		}
+	}
+
+	// RPC status is informational, so it is left null when the server does not report it
+	state.OutOfSync = types.BoolNull()
+	state.LastRPCError = types.StringNull()
+	if r.api != nil {
+		status, err := r.api.secretStatus(ctx, secretID)
+		if err != nil {
+			tflog.Debug(ctx, "Unable to read secret RPC status", map[string]interface{}{
+				"id":    secretID,
+				"error": err.Error(),
+			})
+		} else {
+			state.OutOfSync = types.BoolValue(status.OutOfSync)
+			state.LastRPCError = types.StringValue(status.OutOfSyncReason)
+		}
+	}
+
+	state.FolderPath = types.StringNull()
+	if r.api != nil {
+		state.FolderPath = r.api.folderPathValue(ctx, secret.FolderID)
+	}
+
 	return state, nil
 }
 
-func (r *TssSecretResource) getSecretData(ctx context.Context, state *SecretResourceState, client *server.Server) (*server.Secret, error) {
+// populateFieldHistory fills in the history retention settings of the template and,
+// with counts, the number of retained values for each field. Counts take a request per
+// field, so they are only read when asked for; the template is read once per run.
+// History is informational, so failures are logged and leave the attributes null
+// rather than failing the read.
+func (r *TssSecretResource) populateFieldHistory(ctx context.Context, secret *server.Secret, state *SecretResourceState, counts bool) {
+	if r.api == nil {
+		return
+	}
+
+	historyLengths, err := r.api.templateHistoryLengths(ctx, secret.SecretTemplateID)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to read field history settings from template", map[string]interface{}{
+			"template_id": secret.SecretTemplateID,
+			"error":       err.Error(),
+		})
+	}
+
+	for i, field := range state.Fields {
+		if length, ok := historyLengths[int(field.FieldID.ValueInt64())]; ok {
+			state.Fields[i].HistoryLength = types.Int64Value(int64(length))
+		}
+		if !counts || field.Slug.ValueString() == "" {
+			continue
+		}
+		// Stop once the read's timeout has passed rather than failing every remaining field
+		if ctx.Err() != nil {
+			return
+		}
+
+		var history struct {
+			Total int
+		}
+		path := fmt.Sprintf("secrets/%d/fields/%s/history?take=1", secret.ID, field.Slug.ValueString())
+		if err := r.api.do(ctx, "GET", path, nil, &history); err != nil {
+			tflog.Debug(ctx, "Unable to read field history", map[string]interface{}{
+				"id":    secret.ID,
+				"field": field.FieldName.ValueString(),
+				"error": err.Error(),
+			})
+			continue
+		}
+		state.Fields[i].HistoryCount = types.Int64Value(int64(history.Total))
+	}
+}
+
+func (r *TssSecretResource) getSecretData(ctx context.Context, state *SecretResourceState, client sdkClient) (*server.Secret, error) {
 	tflog.Debug(ctx, "Preparing secret data from state")
 
 	// Convert string attributes to integers
@@ -1127,6 +1861,10 @@ func (r *TssSecretResource) getSecretData(ctx context.Context, state *SecretReso
 		fields = append(fields, secretField)
 	}
 
+	if state.PopulateDefaults.ValueBool() {
+		fields = append(fields, templateDefaultFields(ctx, template, fields)...)
+	}
+
 	// Populate the secret object
 	secret := &server.Secret{
 		Name:             state.Name.ValueString(),
@@ -1243,6 +1981,8 @@ func flattenSecret(secret *server.Secret) (*SecretResourceState, error) {
 			IsFile:           types.BoolValue(f.IsFile),
 			IsNotes:          types.BoolValue(f.IsNotes),
 			IsPassword:       types.BoolValue(f.IsPassword),
+			HistoryLength:    types.Int64Null(),
+			HistoryCount:     types.Int64Null(),
 		}
 
 		// Handle file fields and potential SSH key fields
@@ -1251,6 +1991,11 @@ func flattenSecret(secret *server.Secret) (*SecretResourceState, error) {
 			if f.Filename != "" {
 				field.Filename = types.StringValue(f.Filename)
 			}
+			// The attachment has been downloaded into ItemValue, unless it is
+			// unchanged, when keepUnchangedAttachments replaces the checksum
+			field.ContentSHA256 = types.StringValue(attachmentChecksum(f.ItemValue))
+		} else {
+			field.ContentSHA256 = types.StringNull()
 		}
 
 		// Special handling for SSH key fields - ensure they have filename if provided by server
@@ -1320,6 +2065,73 @@ func flattenSecret(secret *server.Secret) (*SecretResourceState, error) {
 	return state, nil
 }
 
+// accessDeniedDiagnostic is reported when the provider may not view a secret that still exists
+func accessDeniedDiagnostic(id string) diag.Diagnostic {
+	return diag.NewErrorDiagnostic("Secret Access Denied",
+		fmt.Sprintf("Secret Server denied access to secret %s. The secret exists, but the provider's user is missing the View permission on it "+
+			"or its folder. Restore the permission, or set on_access_denied = \"warn\" to keep the last known state while it is reviewed.", id))
+}
+
+// preserveConfigOnlyAttributes copies the attributes that only exist in configuration,
+// and are therefore never returned by the server, into a freshly read state
+func preserveConfigOnlyAttributes(dst *SecretResourceState, src *SecretResourceState) {
+	dst.Retry = src.Retry
+	dst.Timeout = src.Timeout
+	dst.OnAccessDenied = src.OnAccessDenied
+	dst.OnAttachmentDrift = src.OnAttachmentDrift
+	dst.PreventDestroyUnlessInactive = src.PreventDestroyUnlessInactive
+	dst.RotationTriggers = src.RotationTriggers
+	dst.ExpireOnUpdate = src.ExpireOnUpdate
+	dst.AwaitApproval = src.AwaitApproval
+	dst.FailIfOutOfSync = src.FailIfOutOfSync
+	dst.NamePattern = src.NamePattern
+	dst.DoubleLockID = src.DoubleLockID
+	dst.PopulateDefaults = src.PopulateDefaults
+	dst.AuthAlias = src.AuthAlias
+	dst.PasswordGenerator = src.PasswordGenerator
+	dst.AllowTakeover = src.AllowTakeover
+	dst.SyncNameFromServer = src.SyncNameFromServer
+	dst.ReadFieldHistory = src.ReadFieldHistory
+}
+
+// attachmentChecksum returns the hex encoded SHA-256 of a file attachment's content.
+// An empty attachment has no checksum.
+func attachmentChecksum(content string) string {
+	if content == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// folderPathPlanModifier keeps the known folder path while the secret stays in the same folder
+type folderPathPlanModifier struct{}
+
+func (m folderPathPlanModifier) Description(ctx context.Context) string {
+	return "Uses the folder path from state unless the folder ID changes."
+}
+
+func (m folderPathPlanModifier) MarkdownDescription(ctx context.Context) string {
+	return "Uses the folder path from state unless the folder ID changes."
+}
+
+func (m folderPathPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || req.StateValue.IsNull() {
+		return
+	}
+
+	var planFolderID, stateFolderID types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("folderid"), &planFolderID)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("folderid"), &stateFolderID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if planFolderID.Equal(stateFolderID) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
 // sshKeyFieldPlanModifier is a custom plan modifier for SSH key fields
 type sshKeyFieldPlanModifier struct{}
 
@@ -1469,3 +2281,15 @@ func shouldComputePasswordValue(req planmodifier.StringRequest) bool {
 
 	return true
 }
+
+// withAuthAlias returns the resource acting with the credentials of alias
+func (r *TssSecretResource) withAuthAlias(alias types.String) (*TssSecretResource, diag.Diagnostics) {
+	s, api, diags := selectIdentity(r.identities, alias, r.client, r.api)
+	if diags.HasError() || api == r.api {
+		return r, diags
+	}
+	copied := *r
+	copied.client = s
+	copied.api = api
+	return &copied, diags
+}
@@ -0,0 +1,384 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxConnectAsHops bounds how far Read follows connect-as links, in case they
+// form a loop on the server
+const maxConnectAsHops = 32
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &TssSecretConnectAsChainResource{}
+	_ resource.ResourceWithConfigure      = &TssSecretConnectAsChainResource{}
+	_ resource.ResourceWithImportState    = &TssSecretConnectAsChainResource{}
+	_ resource.ResourceWithValidateConfig = &TssSecretConnectAsChainResource{}
+)
+
+// NewTssSecretConnectAsChainResource is a helper function to simplify the provider implementation.
+func NewTssSecretConnectAsChainResource() resource.Resource {
+	return &TssSecretConnectAsChainResource{}
+}
+
+// TssSecretConnectAsChainResource links secrets into a connect-as chain: each
+// secret launches by connecting as the next one, e.g. a launcher secret
+// through a jump credential to a bastion target. The links are the
+// launcherconnectassecretid of the secrets.
+type TssSecretConnectAsChainResource struct {
+	client   *server.Server
+	api      *apiClient
+	defaults callOptions
+	logging  loggingConfig
+}
+
+// SecretConnectAsChainState defines the state structure for the connect-as chain resource
+type SecretConnectAsChainState struct {
+	ID        types.String `tfsdk:"id"`
+	SecretIDs types.List   `tfsdk:"secret_ids"`
+}
+
+// Metadata provides the resource type name
+func (r *TssSecretConnectAsChainResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_secret_connect_as_chain"
+}
+
+// Schema defines the schema for the resource
+func (r *TssSecretConnectAsChainResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Links secrets into a multi-hop connect-as chain, e.g. launcher secret, jump credential, target. " +
+			"Each secret launches by connecting as the next one in secret_ids, and the last one connects as no other secret. " +
+			"Do not also set launcherconnectassecretid on tss_resource_secret resources of the same secrets. " +
+			"Destroying the resource removes the links. Import with the ID of the first secret; the chain is followed from it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the first secret of the chain.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secret_ids": schema.ListAttribute{
+				ElementType: types.Int64Type,
+				Required:    true,
+				Description: "The IDs of the secrets of the chain, in the order they are connected, starting with the secret that is launched. At least two.",
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssSecretConnectAsChainResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.client = providerData.Server
+	r.api = providerData.API
+	r.defaults = providerData.Defaults
+	r.logging = providerData.Logging
+}
+
+// ValidateConfig checks the chain has at least two secrets and no loops
+func (r *TssSecretConnectAsChainResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config SecretConnectAsChainState
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.SecretIDs.IsUnknown() {
+		return
+	}
+
+	var ids []types.Int64
+	resp.Diagnostics.Append(config.SecretIDs.ElementsAs(ctx, &ids, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(ids) < 2 {
+		resp.Diagnostics.AddAttributeError(path.Root("secret_ids"), "Invalid Connect-As Chain",
+			"secret_ids must list at least two secrets: the secret that is launched and the secret it connects as.")
+		return
+	}
+	known := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !id.IsUnknown() && !id.IsNull() {
+			known = append(known, int(id.ValueInt64()))
+		}
+	}
+	if id := duplicateConnectAsSecret(known); id != 0 {
+		resp.Diagnostics.Append(duplicateConnectAsDiagnostic(id))
+	}
+}
+
+// duplicateConnectAsSecret returns a secret listed more than once in a chain, or 0
+func duplicateConnectAsSecret(ids []int) int {
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return id
+		}
+		seen[id] = true
+	}
+	return 0
+}
+
+// duplicateConnectAsDiagnostic reports a secret listed more than once in a chain
+func duplicateConnectAsDiagnostic(id int) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(path.Root("secret_ids"), "Invalid Connect-As Chain",
+		fmt.Sprintf("Secret %d is listed more than once, which would make the chain a loop.", id))
+}
+
+// Create links the secrets
+func (r *TssSecretConnectAsChainResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan SecretConnectAsChainState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var ids []int
+	resp.Diagnostics.Append(plan.SecretIDs.ElementsAs(ctx, &ids, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// IDs unknown during validation are only known now
+	if id := duplicateConnectAsSecret(ids); id != 0 {
+		resp.Diagnostics.Append(duplicateConnectAsDiagnostic(id))
+		return
+	}
+
+	if err := r.link(ctx, connectAsLinks(ids)); err != nil {
+		resp.Diagnostics.AddError("Connect-As Chain Error", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(ids[0]))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read follows the chain from its first secret
+func (r *TssSecretConnectAsChainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state SecretConnectAsChainState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	first, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Connect-As Chain ID", fmt.Sprintf("Expected a secret ID, got %q.", state.ID.ValueString()))
+		return
+	}
+
+	if _, err := r.api.lookupSecret(ctx, first); isNotFound(err) {
+		tflog.Warn(ctx, "First secret of the connect-as chain no longer exists, removing from state", map[string]interface{}{
+			"secret_id": first,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	client := newSDKClient(ctx, r.client, r.api, r.defaults.forOperation("read"))
+	ids := []int64{int64(first)}
+	seen := map[int]bool{first: true}
+	for id := first; len(ids) <= maxConnectAsHops; {
+		secret, err := client.SecretMetadata(id)
+		if err != nil {
+			resp.Diagnostics.AddError("Connect-As Chain Error", fmt.Sprintf("Failed to read secret %d of the chain: %s", id, err))
+			return
+		}
+		next := secret.LauncherConnectAsSecretID
+		if next == 0 || seen[next] {
+			break
+		}
+		seen[next] = true
+		ids = append(ids, int64(next))
+		id = next
+	}
+
+	secretIDs, diags := types.ListValueFrom(ctx, types.Int64Type, ids)
+	resp.Diagnostics.Append(diags...)
+	state.SecretIDs = secretIDs
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update links the secrets as planned, and unlinks the secrets dropped from the chain
+func (r *TssSecretConnectAsChainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan, state SecretConnectAsChainState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var ids, priorIDs []int
+	resp.Diagnostics.Append(plan.SecretIDs.ElementsAs(ctx, &ids, false)...)
+	resp.Diagnostics.Append(state.SecretIDs.ElementsAs(ctx, &priorIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if id := duplicateConnectAsSecret(ids); id != 0 {
+		resp.Diagnostics.Append(duplicateConnectAsDiagnostic(id))
+		return
+	}
+
+	// Secrets dropped from the chain are unlinked after the chain is linked
+	links := connectAsLinks(ids)
+	for _, id := range priorIDs {
+		if !slices.Contains(ids, id) {
+			links = append(links, connectAsLink{SecretID: id})
+		}
+	}
+	if err := r.link(ctx, links); err != nil {
+		resp.Diagnostics.AddError("Connect-As Chain Error", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(ids[0]))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete unlinks the secrets of the chain
+func (r *TssSecretConnectAsChainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state SecretConnectAsChainState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var ids []int
+	resp.Diagnostics.Append(state.SecretIDs.ElementsAs(ctx, &ids, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	links := make([]connectAsLink, 0, len(ids))
+	for _, id := range ids {
+		links = append(links, connectAsLink{SecretID: id})
+	}
+	if err := r.link(ctx, links); err != nil {
+		resp.Diagnostics.AddError("Connect-As Chain Error", err.Error())
+	}
+}
+
+// ImportState imports a chain by the ID of its first secret
+func (r *TssSecretConnectAsChainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	first, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected a secret ID, got %q.", req.ID))
+		return
+	}
+
+	secretIDs, diags := types.ListValueFrom(ctx, types.Int64Type, []int64{first})
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, SecretConnectAsChainState{
+		ID:        types.StringValue(req.ID),
+		SecretIDs: secretIDs,
+	})...)
+}
+
+// connectAsLink is the connect-as secret of a secret of a chain, where 0 is none
+type connectAsLink struct {
+	SecretID    int
+	ConnectAsID int
+}
+
+// connectAsLinks returns the links of a chain in its order; the last secret
+// connects as none
+func connectAsLinks(ids []int) []connectAsLink {
+	links := make([]connectAsLink, 0, len(ids))
+	for i, id := range ids {
+		link := connectAsLink{SecretID: id}
+		if i+1 < len(ids) {
+			link.ConnectAsID = ids[i+1]
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// link sets the connect-as secret of each secret in links, in their order.
+// Only the connect-as secret is changed, so launcher settings changed at the
+// same time, e.g. by a tss_resource_secret, are not overwritten. Secrets
+// already linked as planned are left as they are, and secrets that no longer
+// exist need no unlinking.
+func (r *TssSecretConnectAsChainResource) link(ctx context.Context, links []connectAsLink) error {
+	client := newSDKClient(ctx, r.client, r.api, r.defaults.forOperation("update"))
+	for _, link := range links {
+		if err := func() error {
+			unlock, err := lockSecret(ctx, link.SecretID)
+			if err != nil {
+				return fmt.Errorf("gave up waiting for another operation on secret %d: %w", link.SecretID, err)
+			}
+			defer unlock()
+
+			secret, err := client.SecretMetadata(link.SecretID)
+			if link.ConnectAsID == 0 && isNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read secret %d: %w", link.SecretID, err)
+			}
+			if secret.LauncherConnectAsSecretID == link.ConnectAsID {
+				return nil
+			}
+
+			tflog.Debug(ctx, "Setting the connect-as secret of secret", map[string]interface{}{
+				"secret_id":            link.SecretID,
+				"connect_as_secret_id": link.ConnectAsID,
+			})
+			if err := r.api.setConnectAsSecret(withCallOptions(ctx, client.opts), link.SecretID, link.ConnectAsID); err != nil {
+				return fmt.Errorf("failed to set the connect-as secret of secret %d to %d: %w", link.SecretID, link.ConnectAsID, err)
+			}
+			return nil
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setConnectAsSecret sets the launcher connect-as secret of a secret, leaving
+// its other settings as they are. A connectAsID of 0 removes it.
+func (c *apiClient) setConnectAsSecret(ctx context.Context, secretID, connectAsID int) error {
+	var value *int
+	if connectAsID != 0 {
+		value = &connectAsID
+	}
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"launcherConnectAsSecretId": dirtyValue(value),
+		},
+	}
+	return c.do(ctx, "PATCH", fmt.Sprintf("secrets/%d/general", secretID), body, nil)
+}
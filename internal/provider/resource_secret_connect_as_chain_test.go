@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+)
+
+func TestLinkUpdatesOnlyConnectAsSecretInOrder(t *testing.T) {
+	connectAs := map[string]int{"1": 0, "2": 3, "3": 9, "4": 3}
+	var patches []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/secrets/"), "/")[0]
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]int{"launcherConnectAsSecretId": connectAs[id]})
+		case http.MethodPatch:
+			if !strings.HasSuffix(r.URL.Path, "/general") {
+				t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			}
+			var body struct {
+				Data map[string]struct {
+					Dirty bool
+					Value *int
+				}
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Data) != 1 {
+				t.Errorf("secret %s was sent %d settings, want only its connect-as secret", id, len(body.Data))
+			}
+			value := "none"
+			if v := body.Data["launcherConnectAsSecretId"].Value; v != nil {
+				value = strconv.Itoa(*v)
+			}
+			patches = append(patches, id+"->"+value)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	r := &TssSecretConnectAsChainResource{client: &server.Server{}, api: newTestAPIClient(ts.URL)}
+	links := append(connectAsLinks([]int{1, 2, 3}), connectAsLink{SecretID: 4})
+	if err := r.link(context.Background(), links); err != nil {
+		t.Fatal(err)
+	}
+
+	// Secret 2 needs no change
+	want := []string{"1->2", "3->none", "4->none"}
+	if !reflect.DeepEqual(patches, want) {
+		t.Errorf("patches = %q, want %q", patches, want)
+	}
+}
+
+func TestDuplicateConnectAsSecret(t *testing.T) {
+	if id := duplicateConnectAsSecret([]int{1, 2, 3}); id != 0 {
+		t.Errorf("a chain without duplicates reported secret %d", id)
+	}
+	if id := duplicateConnectAsSecret([]int{1, 2, 1}); id != 1 {
+		t.Errorf("duplicate = %d, want 1", id)
+	}
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPopulateFieldHistoryReadsTemplateOnce(t *testing.T) {
+	requests := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/api/v1/secret-templates/6003":
+			_, _ = w.Write([]byte(`{"Fields":[{"SecretTemplateFieldID":1,"HistoryLength":5},{"SecretTemplateFieldID":2,"HistoryLength":10}]}`))
+		case "/api/v1/secrets/7/fields/username/history":
+			_, _ = w.Write([]byte(`{"Total":1}`))
+		case "/api/v1/secrets/7/fields/password/history":
+			_, _ = w.Write([]byte(`{"Total":4}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	r := &TssSecretResource{api: newTestAPIClient(ts.URL)}
+	newState := func() *SecretResourceState {
+		return &SecretResourceState{Fields: []SecretField{
+			{FieldID: types.Int64Value(1), Slug: types.StringValue("username"), HistoryLength: types.Int64Null(), HistoryCount: types.Int64Null()},
+			{FieldID: types.Int64Value(2), Slug: types.StringValue("password"), HistoryLength: types.Int64Null(), HistoryCount: types.Int64Null()},
+		}}
+	}
+
+	state := newState()
+	r.populateFieldHistory(context.Background(), &server.Secret{ID: 7, SecretTemplateID: 6003}, state, true)
+	for i, want := range []struct{ length, count int64 }{{5, 1}, {10, 4}} {
+		field := state.Fields[i]
+		if field.HistoryLength.ValueInt64() != want.length || field.HistoryCount.ValueInt64() != want.count {
+			t.Errorf("field %s has history %s of %s, want %d of %d",
+				field.Slug.ValueString(), field.HistoryCount, field.HistoryLength, want.count, want.length)
+		}
+	}
+
+	// Without counts only the cached template is used
+	state = newState()
+	r.populateFieldHistory(context.Background(), &server.Secret{ID: 7, SecretTemplateID: 6003}, state, false)
+	if n := requests["/api/v1/secret-templates/6003"]; n != 1 {
+		t.Errorf("the template was read %d times", n)
+	}
+	if n := requests["/api/v1/secrets/7/fields/password/history"]; n != 1 {
+		t.Errorf("the field history was read %d times", n)
+	}
+	for _, field := range state.Fields {
+		if field.HistoryLength.IsNull() || !field.HistoryCount.IsNull() {
+			t.Errorf("field %s has history %s of %s, want no count", field.Slug.ValueString(), field.HistoryCount, field.HistoryLength)
+		}
+	}
+}
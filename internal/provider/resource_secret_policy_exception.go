@@ -0,0 +1,336 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// policyExemptions are the policy requirements a group can be exempted from,
+// mapped to their names in the Secret Server API
+var policyExemptions = map[string]string{
+	"checkout":   "RequireCheckout",
+	"approval":   "RequireApproval",
+	"comment":    "RequireComment",
+	"doublelock": "DoubleLock",
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &TssSecretPolicyExceptionResource{}
+	_ resource.ResourceWithConfigure      = &TssSecretPolicyExceptionResource{}
+	_ resource.ResourceWithImportState    = &TssSecretPolicyExceptionResource{}
+	_ resource.ResourceWithValidateConfig = &TssSecretPolicyExceptionResource{}
+)
+
+// NewTssSecretPolicyExceptionResource is a helper function to simplify the provider implementation.
+func NewTssSecretPolicyExceptionResource() resource.Resource {
+	return &TssSecretPolicyExceptionResource{}
+}
+
+// TssSecretPolicyExceptionResource exempts a group from access requirements of a secret
+type TssSecretPolicyExceptionResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// SecretPolicyExceptionState defines the state structure for the secret policy exception resource
+type SecretPolicyExceptionState struct {
+	ID         types.String `tfsdk:"id"`
+	SecretID   types.Int64  `tfsdk:"secret_id"`
+	GroupID    types.Int64  `tfsdk:"group_id"`
+	Exemptions types.Set    `tfsdk:"exemptions"`
+	Reason     types.String `tfsdk:"reason"`
+}
+
+// policyException is a policy exception as exchanged with Secret Server
+type policyException struct {
+	GroupID    int      `json:"groupId"`
+	Exemptions []string `json:"exemptions"`
+	Reason     string   `json:"reason"`
+}
+
+// Metadata provides the resource type name
+func (r *TssSecretPolicyExceptionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_secret_policy_exception"
+}
+
+// Schema defines the schema for the resource
+func (r *TssSecretPolicyExceptionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exempts a group from access requirements of a secret, such as checkout or approval, e.g. for a break-glass group. " +
+			"Import with \"<secret_id>/<group_id>\".",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the exception, \"<secret_id>/<group_id>\".",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secret_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the secret.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"group_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the exempted group.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"exemptions": schema.SetAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Description: "The requirements the group is exempt from: checkout, approval, comment or doublelock.",
+			},
+			"reason": schema.StringAttribute{
+				Required:    true,
+				Description: "Why the group is exempt, recorded with the exception for review.",
+			},
+		},
+	}
+}
+
+// ValidateConfig checks the exemptions and that a reason is given
+func (r *TssSecretPolicyExceptionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config SecretPolicyExceptionState
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Reason.IsUnknown() && !config.Reason.IsNull() && strings.TrimSpace(config.Reason.ValueString()) == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("reason"), "Missing Reason", "Give the reason for the exception so it can be reviewed.")
+	}
+
+	if config.Exemptions.IsUnknown() || config.Exemptions.IsNull() {
+		return
+	}
+	var exemptions []string
+	resp.Diagnostics.Append(config.Exemptions.ElementsAs(ctx, &exemptions, false)...)
+	if len(exemptions) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("exemptions"), "Invalid Exemptions", "Set at least one exemption.")
+	}
+	for _, exemption := range exemptions {
+		if _, ok := policyExemptions[exemption]; !ok {
+			resp.Diagnostics.AddAttributeError(path.Root("exemptions"), "Invalid Exemptions",
+				fmt.Sprintf("%q is not one of checkout, approval, comment or doublelock.", exemption))
+		}
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssSecretPolicyExceptionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create adds the exception
+func (r *TssSecretPolicyExceptionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan SecretPolicyExceptionState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	exception := plan.expand(ctx)
+	secretID := int(plan.SecretID.ValueInt64())
+
+	tflog.Info(ctx, "Adding secret policy exception", map[string]interface{}{
+		"secret_id":  secretID,
+		"group_id":   exception.GroupID,
+		"exemptions": exception.Exemptions,
+	})
+
+	if err := r.api.do(ctx, "POST", fmt.Sprintf("secrets/%d/policy-exceptions", secretID), map[string]interface{}{"data": exception}, nil); err != nil {
+		resp.Diagnostics.AddError("Policy Exception Error", fmt.Sprintf("Failed to add the policy exception of group %d to secret %d: %s", exception.GroupID, secretID, err))
+		return
+	}
+
+	plan.ID = types.StringValue(fmt.Sprintf("%d/%d", secretID, exception.GroupID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the exception
+func (r *TssSecretPolicyExceptionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state SecretPolicyExceptionState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretID := int(state.SecretID.ValueInt64())
+	groupID := int(state.GroupID.ValueInt64())
+
+	exception, err := r.api.policyException(ctx, secretID, groupID)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Policy Exception Error", fmt.Sprintf("Failed to read the policy exceptions of secret %d: %s", secretID, err))
+		return
+	}
+	if exception == nil {
+		tflog.Warn(ctx, "Policy exception not found, removing from state", map[string]interface{}{
+			"secret_id": secretID,
+			"group_id":  groupID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	var exemptions []string
+	for name, apiName := range policyExemptions {
+		for _, e := range exception.Exemptions {
+			if strings.EqualFold(e, apiName) {
+				exemptions = append(exemptions, name)
+			}
+		}
+	}
+	sort.Strings(exemptions)
+
+	set, diags := types.SetValueFrom(ctx, types.StringType, exemptions)
+	resp.Diagnostics.Append(diags...)
+	state.Exemptions = set
+	state.Reason = types.StringValue(exception.Reason)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update changes the exemptions or reason of the exception
+func (r *TssSecretPolicyExceptionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan SecretPolicyExceptionState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	exception := plan.expand(ctx)
+	secretID := int(plan.SecretID.ValueInt64())
+
+	endpoint := fmt.Sprintf("secrets/%d/policy-exceptions/%d", secretID, exception.GroupID)
+	if err := r.api.do(ctx, "PUT", endpoint, map[string]interface{}{"data": exception}, nil); err != nil {
+		resp.Diagnostics.AddError("Policy Exception Error", fmt.Sprintf("Failed to update the policy exception of group %d on secret %d: %s", exception.GroupID, secretID, err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the exception
+func (r *TssSecretPolicyExceptionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state SecretPolicyExceptionState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretID := int(state.SecretID.ValueInt64())
+	groupID := int(state.GroupID.ValueInt64())
+
+	tflog.Info(ctx, "Removing secret policy exception", map[string]interface{}{
+		"secret_id": secretID,
+		"group_id":  groupID,
+	})
+
+	err := r.api.do(ctx, "DELETE", fmt.Sprintf("secrets/%d/policy-exceptions/%d", secretID, groupID), nil, nil)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Policy Exception Error", fmt.Sprintf("Failed to remove the policy exception of group %d from secret %d: %s", groupID, secretID, err))
+	}
+}
+
+// ImportState imports an exception by "<secret_id>/<group_id>"
+func (r *TssSecretPolicyExceptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected \"<secret_id>/<group_id>\", got %q.", req.ID))
+		return
+	}
+	secretID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("The secret ID %q is not a number.", parts[0]))
+		return
+	}
+	groupID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("The group ID %q is not a number.", parts[1]))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("secret_id"), secretID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_id"), groupID)...)
+}
+
+// expand converts the state into the API model
+func (s SecretPolicyExceptionState) expand(ctx context.Context) policyException {
+	var exemptions []string
+	s.Exemptions.ElementsAs(ctx, &exemptions, false)
+
+	exception := policyException{
+		GroupID:    int(s.GroupID.ValueInt64()),
+		Exemptions: make([]string, 0, len(exemptions)),
+		Reason:     s.Reason.ValueString(),
+	}
+	for _, exemption := range exemptions {
+		exception.Exemptions = append(exception.Exemptions, policyExemptions[exemption])
+	}
+	sort.Strings(exception.Exemptions)
+	return exception
+}
+
+// policyException returns the exception of a group on a secret, or nil when it has none
+func (c *apiClient) policyException(ctx context.Context, secretID, groupID int) (*policyException, error) {
+	var exceptions struct {
+		Records []policyException
+	}
+	if err := c.do(ctx, "GET", fmt.Sprintf("secrets/%d/policy-exceptions", secretID), nil, &exceptions); err != nil {
+		return nil, err
+	}
+	for _, exception := range exceptions.Records {
+		if exception.GroupID == groupID {
+			return &exception, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,347 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &TssSecretTemplateLauncherResource{}
+	_ resource.ResourceWithConfigure   = &TssSecretTemplateLauncherResource{}
+	_ resource.ResourceWithImportState = &TssSecretTemplateLauncherResource{}
+)
+
+// NewTssSecretTemplateLauncherResource is a helper function to simplify the provider implementation.
+func NewTssSecretTemplateLauncherResource() resource.Resource {
+	return &TssSecretTemplateLauncherResource{}
+}
+
+// TssSecretTemplateLauncherResource maps a launcher to a secret template, so
+// secrets of the template can launch sessions
+type TssSecretTemplateLauncherResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// SecretTemplateLauncherState defines the state structure for the secret template launcher resource
+type SecretTemplateLauncherState struct {
+	ID             types.String `tfsdk:"id"`
+	TemplateID     types.Int64  `tfsdk:"template_id"`
+	LauncherTypeID types.Int64  `tfsdk:"launcher_type_id"`
+	LauncherName   types.String `tfsdk:"launcher_name"`
+	FieldMappings  types.Map    `tfsdk:"field_mappings"`
+}
+
+// templateLauncher is a launcher of a secret template as returned by Secret Server
+type templateLauncher struct {
+	LauncherTypeID   int
+	LauncherTypeName string
+	FieldMappings    []launcherFieldMapping
+}
+
+// launcherFieldMapping maps an argument of a launcher to a field of the secret template
+type launcherFieldMapping struct {
+	LauncherField           string
+	SecretTemplateFieldSlug string
+}
+
+// Metadata provides the resource type name
+func (r *TssSecretTemplateLauncherResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_secret_template_launcher"
+}
+
+// Schema defines the schema for the resource
+func (r *TssSecretTemplateLauncherResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Maps a launcher, e.g. Remote Desktop or PuTTY, to a secret template and sets which template fields supply the launcher's arguments, " +
+			"so secrets of the template can launch sessions.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The template ID and launcher type ID, as template_id/launcher_type_id.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"template_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the secret template.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"launcher_type_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the launcher type to map to the template.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"launcher_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the launcher type.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"field_mappings": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Description: "The template field slug supplying each launcher argument, keyed by argument, e.g. { Machine = \"machine\", Username = \"username\" }. " +
+					"Defaults to the mappings Secret Server chooses.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssSecretTemplateLauncherResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create maps the launcher to the template
+func (r *TssSecretTemplateLauncherResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan SecretTemplateLauncherState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateID, launcherTypeID := int(plan.TemplateID.ValueInt64()), int(plan.LauncherTypeID.ValueInt64())
+	mappings, diags := expandLauncherFieldMappings(ctx, plan.FieldMappings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Adding launcher to secret template", map[string]interface{}{
+		"template_id":      templateID,
+		"launcher_type_id": launcherTypeID,
+	})
+
+	launcher, err := r.api.addTemplateLauncher(ctx, templateID, launcherTypeID, mappings)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Template Launcher Error",
+			fmt.Sprintf("Failed to add launcher type %d to template %d: %s", launcherTypeID, templateID, err))
+		return
+	}
+
+	resp.Diagnostics.Append(flattenTemplateLauncher(ctx, launcher, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the launcher of the template
+func (r *TssSecretTemplateLauncherResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state SecretTemplateLauncherState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateID, launcherTypeID := int(state.TemplateID.ValueInt64()), int(state.LauncherTypeID.ValueInt64())
+	launcher, err := r.api.templateLauncher(ctx, templateID, launcherTypeID)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Secret template launcher not found, removing from state", map[string]interface{}{
+			"template_id":      templateID,
+			"launcher_type_id": launcherTypeID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Template Launcher Error",
+			fmt.Sprintf("Failed to read launcher type %d of template %d: %s", launcherTypeID, templateID, err))
+		return
+	}
+
+	resp.Diagnostics.Append(flattenTemplateLauncher(ctx, launcher, &state)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update changes the field mappings of the launcher
+func (r *TssSecretTemplateLauncherResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan SecretTemplateLauncherState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateID, launcherTypeID := int(plan.TemplateID.ValueInt64()), int(plan.LauncherTypeID.ValueInt64())
+	mappings, diags := expandLauncherFieldMappings(ctx, plan.FieldMappings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	launcher, err := r.api.updateTemplateLauncher(ctx, templateID, launcherTypeID, mappings)
+	if err != nil {
+		resp.Diagnostics.AddError("Secret Template Launcher Error",
+			fmt.Sprintf("Failed to update launcher type %d of template %d: %s", launcherTypeID, templateID, err))
+		return
+	}
+
+	resp.Diagnostics.Append(flattenTemplateLauncher(ctx, launcher, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the launcher from the template
+func (r *TssSecretTemplateLauncherResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state SecretTemplateLauncherState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateID, launcherTypeID := int(state.TemplateID.ValueInt64()), int(state.LauncherTypeID.ValueInt64())
+	err := r.api.removeTemplateLauncher(ctx, templateID, launcherTypeID)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Secret Template Launcher Error",
+			fmt.Sprintf("Failed to remove launcher type %d from template %d: %s", launcherTypeID, templateID, err))
+	}
+}
+
+// ImportState imports a launcher by "template_id/launcher_type_id"
+func (r *TssSecretTemplateLauncherResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var templateID, launcherTypeID int64
+	if _, err := fmt.Sscanf(req.ID, "%d/%d", &templateID, &launcherTypeID); err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected template_id/launcher_type_id, got %q.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("template_id"), templateID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("launcher_type_id"), launcherTypeID)...)
+}
+
+// expandLauncherFieldMappings converts the field_mappings attribute into the
+// mappings sent to Secret Server. Unknown or null mappings return nil, so the
+// server chooses them.
+func expandLauncherFieldMappings(ctx context.Context, value types.Map) ([]launcherFieldMapping, diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return nil, nil
+	}
+
+	var fields map[string]string
+	diags := value.ElementsAs(ctx, &fields, false)
+
+	mappings := make([]launcherFieldMapping, 0, len(fields))
+	for launcherField, slug := range fields {
+		mappings = append(mappings, launcherFieldMapping{LauncherField: launcherField, SecretTemplateFieldSlug: slug})
+	}
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].LauncherField < mappings[j].LauncherField })
+	return mappings, diags
+}
+
+// launcherFieldMappingsValue converts the mappings of a launcher into the value of field_mappings
+func launcherFieldMappingsValue(ctx context.Context, mappings []launcherFieldMapping) (types.Map, diag.Diagnostics) {
+	fields := make(map[string]string, len(mappings))
+	for _, m := range mappings {
+		fields[m.LauncherField] = m.SecretTemplateFieldSlug
+	}
+	return types.MapValueFrom(ctx, types.StringType, fields)
+}
+
+// flattenTemplateLauncher copies a launcher of a template into state
+func flattenTemplateLauncher(ctx context.Context, launcher *templateLauncher, state *SecretTemplateLauncherState) diag.Diagnostics {
+	mappings, diags := launcherFieldMappingsValue(ctx, launcher.FieldMappings)
+
+	state.ID = types.StringValue(strconv.FormatInt(state.TemplateID.ValueInt64(), 10) + "/" + strconv.Itoa(launcher.LauncherTypeID))
+	state.LauncherName = types.StringValue(launcher.LauncherTypeName)
+	state.FieldMappings = mappings
+	return diags
+}
+
+// templateLaunchers returns the launchers mapped to a secret template
+func (c *apiClient) templateLaunchers(ctx context.Context, templateID int) ([]templateLauncher, error) {
+	var launchers []templateLauncher
+	if err := c.do(ctx, "GET", fmt.Sprintf("secret-templates/%d/launchers", templateID), nil, &launchers); err != nil {
+		return nil, err
+	}
+	return launchers, nil
+}
+
+// templateLauncher returns a launcher of a secret template
+func (c *apiClient) templateLauncher(ctx context.Context, templateID, launcherTypeID int) (*templateLauncher, error) {
+	var launcher templateLauncher
+	if err := c.do(ctx, "GET", fmt.Sprintf("secret-templates/%d/launchers/%d", templateID, launcherTypeID), nil, &launcher); err != nil {
+		return nil, err
+	}
+	return &launcher, nil
+}
+
+// addTemplateLauncher maps a launcher to a secret template and returns the result
+func (c *apiClient) addTemplateLauncher(ctx context.Context, templateID, launcherTypeID int, mappings []launcherFieldMapping) (*templateLauncher, error) {
+	body := map[string]interface{}{
+		"launcherTypeId": launcherTypeID,
+	}
+	if mappings != nil {
+		body["fieldMappings"] = mappings
+	}
+	if err := c.do(ctx, "POST", fmt.Sprintf("secret-templates/%d/launchers", templateID), body, nil); err != nil {
+		return nil, err
+	}
+	return c.templateLauncher(ctx, templateID, launcherTypeID)
+}
+
+// updateTemplateLauncher replaces the field mappings of a launcher of a secret
+// template and returns the result. Nil mappings leave them unchanged.
+func (c *apiClient) updateTemplateLauncher(ctx context.Context, templateID, launcherTypeID int, mappings []launcherFieldMapping) (*templateLauncher, error) {
+	if mappings != nil {
+		body := map[string]interface{}{
+			"fieldMappings": mappings,
+		}
+		if err := c.do(ctx, "PUT", fmt.Sprintf("secret-templates/%d/launchers/%d", templateID, launcherTypeID), body, nil); err != nil {
+			return nil, err
+		}
+	}
+	return c.templateLauncher(ctx, templateID, launcherTypeID)
+}
+
+// removeTemplateLauncher removes a launcher from a secret template
+func (c *apiClient) removeTemplateLauncher(ctx context.Context, templateID, launcherTypeID int) error {
+	return c.do(ctx, "DELETE", fmt.Sprintf("secret-templates/%d/launchers/%d", templateID, launcherTypeID), nil, nil)
+}
@@ -0,0 +1,254 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &TssUserPasswordResetResource{}
+	_ resource.ResourceWithConfigure = &TssUserPasswordResetResource{}
+)
+
+// NewTssUserPasswordResetResource is a helper function to simplify the provider implementation.
+func NewTssUserPasswordResetResource() resource.Resource {
+	return &TssUserPasswordResetResource{}
+}
+
+// TssUserPasswordResetResource resets the password of a local Secret Server user.
+// It is a trigger: the reset happens on create and whenever the password version
+// changes, and destroying the resource leaves the password as it is.
+type TssUserPasswordResetResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// UserPasswordResetState defines the state structure for the user password reset resource
+type UserPasswordResetState struct {
+	ID                types.String `tfsdk:"id"`
+	UserID            types.Int64  `tfsdk:"user_id"`
+	PasswordWO        types.String `tfsdk:"password_wo"`
+	PasswordWOVersion types.String `tfsdk:"password_wo_version"`
+	Triggers          types.Map    `tfsdk:"triggers"`
+	LastReset         types.String `tfsdk:"last_reset"`
+}
+
+// Metadata provides the resource type name
+func (r *TssUserPasswordResetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_user_password_reset"
+}
+
+// Schema defines the schema for the resource
+func (r *TssUserPasswordResetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Resets the password of a local Secret Server user, e.g. for break-glass rotation. " +
+			"The password is write-only and never stored in state; generate it with an ephemeral resource. " +
+			"The password is reset on create and whenever password_wo_version or triggers change. " +
+			"Destroying the resource leaves the password as it is. Requires Terraform 1.11 or later.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the user.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The ID of the local user whose password is reset.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"password_wo": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Description: "The new password. Write-only: it is sent to Secret Server but not stored in state.",
+			},
+			"password_wo_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Change this value to reset the password again with the current password_wo.",
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Arbitrary values that replace the resource, and so reset the password, when they change.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"last_reset": schema.StringAttribute{
+				Computed:    true,
+				Description: "When the provider last reset the password, in RFC3339 format.",
+			},
+		},
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssUserPasswordResetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create resets the password
+func (r *TssUserPasswordResetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan UserPasswordResetState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only values are only available in the configuration
+	var password types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &password)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userID := int(plan.UserID.ValueInt64())
+	if err := r.api.resetUserPassword(ctx, userID, password.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Password Reset Error", fmt.Sprintf("Failed to reset the password of user %d: %s", userID, err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(userID))
+	plan.PasswordWO = types.StringNull()
+	plan.LastReset = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read checks that the user still exists
+func (r *TssUserPasswordResetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state UserPasswordResetState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userID := int(state.UserID.ValueInt64())
+	_, err := r.api.user(ctx, userID)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "User not found, removing password reset from state", map[string]interface{}{
+			"user_id": userID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Password Reset Error", fmt.Sprintf("Failed to read user %d: %s", userID, err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update resets the password again when password_wo_version changes
+func (r *TssUserPasswordResetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan, state UserPasswordResetState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.PasswordWO = types.StringNull()
+	plan.LastReset = state.LastReset
+
+	if !plan.PasswordWOVersion.Equal(state.PasswordWOVersion) {
+		var password types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &password)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		userID := int(plan.UserID.ValueInt64())
+		if err := r.api.resetUserPassword(ctx, userID, password.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Password Reset Error", fmt.Sprintf("Failed to reset the password of user %d: %s", userID, err))
+			return
+		}
+		plan.LastReset = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete only removes the resource from state; the password is left as it is
+func (r *TssUserPasswordResetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Debug(r.logging.context(ctx), "Removing user password reset from state; the password is unchanged")
+}
+
+// user is a Secret Server user as far as the provider needs it
+type user struct {
+	ID       int
+	UserName string
+	DomainID int
+}
+
+// user returns the user with the given ID
+func (c *apiClient) user(ctx context.Context, userID int) (*user, error) {
+	var u user
+	if err := c.do(ctx, "GET", fmt.Sprintf("users/%d", userID), nil, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// resetUserPassword sets the password of a local user. Domain users authenticate
+// against their directory, so their password cannot be reset here.
+func (c *apiClient) resetUserPassword(ctx context.Context, userID int, password string) error {
+	u, err := c.user(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.DomainID > 0 {
+		return fmt.Errorf("%s is a domain user; only the passwords of local users can be reset", u.UserName)
+	}
+
+	tflog.Info(ctx, "Resetting user password", map[string]interface{}{
+		"user_id": userID,
+	})
+
+	body := map[string]interface{}{
+		"userId":   userID,
+		"password": password,
+	}
+	return c.do(ctx, "POST", fmt.Sprintf("users/%d/password-reset", userID), body, nil)
+}
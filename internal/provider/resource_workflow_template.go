@@ -0,0 +1,360 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &TssWorkflowTemplateResource{}
+	_ resource.ResourceWithConfigure      = &TssWorkflowTemplateResource{}
+	_ resource.ResourceWithImportState    = &TssWorkflowTemplateResource{}
+	_ resource.ResourceWithValidateConfig = &TssWorkflowTemplateResource{}
+)
+
+// NewTssWorkflowTemplateResource is a helper function to simplify the provider implementation.
+func NewTssWorkflowTemplateResource() resource.Resource {
+	return &TssWorkflowTemplateResource{}
+}
+
+// TssWorkflowTemplateResource manages a multi-step access request workflow
+type TssWorkflowTemplateResource struct {
+	api     *apiClient
+	logging loggingConfig
+}
+
+// WorkflowTemplateState defines the state structure for the workflow template resource
+type WorkflowTemplateState struct {
+	ID          types.String        `tfsdk:"id"`
+	Name        types.String        `tfsdk:"name"`
+	Description types.String        `tfsdk:"description"`
+	Steps       []WorkflowStepModel `tfsdk:"steps"`
+}
+
+// WorkflowStepModel is a single approval step of a workflow
+type WorkflowStepModel struct {
+	Name              types.String `tfsdk:"name"`
+	RequiredApprovals types.Int64  `tfsdk:"required_approvals"`
+	ApproverUserIDs   types.Set    `tfsdk:"approver_user_ids"`
+	ApproverGroupIDs  types.Set    `tfsdk:"approver_group_ids"`
+}
+
+// workflowTemplate is a workflow template as exchanged with Secret Server
+type workflowTemplate struct {
+	ID          int            `json:"id,omitempty"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Type        string         `json:"workflowType"`
+	Steps       []workflowStep `json:"steps"`
+}
+
+// workflowStep is one level of a workflow template. Approvers are referenced by
+// user or group ID; a step is complete once MinimumApprovals of them approve.
+type workflowStep struct {
+	Name             string `json:"name"`
+	Order            int    `json:"order"`
+	MinimumApprovals int    `json:"minimumApprovals"`
+	UserIDs          []int  `json:"userIds"`
+	GroupIDs         []int  `json:"groupIds"`
+}
+
+// Metadata provides the resource type name
+func (r *TssWorkflowTemplateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "dept-tss_workflow_template"
+}
+
+// Schema defines the schema for the resource
+func (r *TssWorkflowTemplateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a multi-step access request workflow. Each step must be approved by a quorum of its approvers before the next one starts.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the workflow template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the workflow template.",
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "The description of the workflow template.",
+			},
+			"steps": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "The approval steps, in the order they are run.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "The name of the step.",
+						},
+						"required_approvals": schema.Int64Attribute{
+							Required:    true,
+							Description: "The number of approvers that must approve the step.",
+						},
+						"approver_user_ids": schema.SetAttribute{
+							ElementType: types.Int64Type,
+							Optional:    true,
+							Description: "The IDs of the users that can approve the step.",
+						},
+						"approver_group_ids": schema.SetAttribute{
+							ElementType: types.Int64Type,
+							Optional:    true,
+							Description: "The IDs of the groups whose members can approve the step.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that every step has approvers and an achievable quorum
+func (r *TssWorkflowTemplateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config WorkflowTemplateState
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Steps != nil && len(config.Steps) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("steps"), "Invalid Workflow", "A workflow needs at least one step.")
+	}
+
+	for i, step := range config.Steps {
+		if step.ApproverUserIDs.IsUnknown() || step.ApproverGroupIDs.IsUnknown() || step.RequiredApprovals.IsUnknown() {
+			continue
+		}
+		stepPath := path.Root("steps").AtListIndex(i)
+
+		if len(step.ApproverUserIDs.Elements()) == 0 && len(step.ApproverGroupIDs.Elements()) == 0 {
+			resp.Diagnostics.AddAttributeError(stepPath, "Invalid Workflow Step",
+				"Set approver_user_ids or approver_group_ids so the step can be approved.")
+		}
+		if step.RequiredApprovals.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(stepPath.AtName("required_approvals"), "Invalid Workflow Step",
+				"required_approvals must be at least 1.")
+		}
+		// Groups can have any number of members, so the quorum can only be checked
+		// against users
+		if len(step.ApproverGroupIDs.Elements()) == 0 && step.RequiredApprovals.ValueInt64() > int64(len(step.ApproverUserIDs.Elements())) {
+			resp.Diagnostics.AddAttributeError(stepPath.AtName("required_approvals"), "Invalid Workflow Step",
+				fmt.Sprintf("required_approvals is %d but the step only has %d approvers.",
+					step.RequiredApprovals.ValueInt64(), len(step.ApproverUserIDs.Elements())))
+		}
+	}
+}
+
+// Configure initializes the resource with the provider configuration
+func (r *TssWorkflowTemplateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TssProviderData)
+	if !ok {
+		tflog.Error(ctx, "Failed to cast provider data", map[string]interface{}{
+			"expected_type": "*TssProviderData",
+			"actual_type":   fmt.Sprintf("%T", req.ProviderData),
+		})
+		resp.Diagnostics.AddError("Configuration Error", "Failed to retrieve provider configuration")
+		return
+	}
+
+	r.api = providerData.API
+	r.logging = providerData.Logging
+}
+
+// Create creates the workflow template
+func (r *TssWorkflowTemplateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan WorkflowTemplateState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workflow, diags := plan.expand(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Creating workflow template", map[string]interface{}{
+		"name":  workflow.Name,
+		"steps": len(workflow.Steps),
+	})
+
+	var created workflowTemplate
+	if err := r.api.do(ctx, "POST", "workflows/templates", map[string]interface{}{"data": workflow}, &created); err != nil {
+		resp.Diagnostics.AddError("Workflow Template Error", fmt.Sprintf("Failed to create workflow template: %s", err))
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(created.ID))
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the workflow template
+func (r *TssWorkflowTemplateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.logging.context(ctx)
+	if r.api.keepStateOffline(ctx) {
+		return
+	}
+	var state WorkflowTemplateState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var workflow workflowTemplate
+	err := r.api.do(ctx, "GET", "workflows/templates/"+state.ID.ValueString(), nil, &workflow)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "Workflow template not found, removing from state", map[string]interface{}{
+			"id": state.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Workflow Template Error", fmt.Sprintf("Failed to read workflow template %s: %s", state.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(state.flatten(ctx, &workflow)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update updates the workflow template
+func (r *TssWorkflowTemplateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.logging.context(ctx)
+	var plan WorkflowTemplateState
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workflow, diags := plan.expand(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	workflow.ID, _ = strconv.Atoi(plan.ID.ValueString())
+
+	if err := r.api.do(ctx, "PUT", "workflows/templates/"+plan.ID.ValueString(), map[string]interface{}{"data": workflow}, nil); err != nil {
+		resp.Diagnostics.AddError("Workflow Template Error", fmt.Sprintf("Failed to update workflow template %s: %s", plan.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the workflow template
+func (r *TssWorkflowTemplateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.logging.context(ctx)
+	var state WorkflowTemplateState
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Deleting workflow template", map[string]interface{}{
+		"id": state.ID.ValueString(),
+	})
+
+	err := r.api.do(ctx, "DELETE", "workflows/templates/"+state.ID.ValueString(), nil, nil)
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Workflow Template Error", fmt.Sprintf("Failed to delete workflow template %s: %s", state.ID.ValueString(), err))
+	}
+}
+
+// ImportState imports a workflow template by ID
+func (r *TssWorkflowTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// expand converts the state into the API model
+func (s WorkflowTemplateState) expand(ctx context.Context) (*workflowTemplate, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	workflow := &workflowTemplate{
+		Name:        s.Name.ValueString(),
+		Description: s.Description.ValueString(),
+		Type:        "SecretAccess",
+	}
+
+	for i, step := range s.Steps {
+		apiStep := workflowStep{
+			Name:             step.Name.ValueString(),
+			Order:            i + 1,
+			MinimumApprovals: int(step.RequiredApprovals.ValueInt64()),
+			UserIDs:          []int{},
+			GroupIDs:         []int{},
+		}
+		if !step.ApproverUserIDs.IsNull() {
+			diags.Append(step.ApproverUserIDs.ElementsAs(ctx, &apiStep.UserIDs, false)...)
+		}
+		if !step.ApproverGroupIDs.IsNull() {
+			diags.Append(step.ApproverGroupIDs.ElementsAs(ctx, &apiStep.GroupIDs, false)...)
+		}
+		workflow.Steps = append(workflow.Steps, apiStep)
+	}
+	return workflow, diags
+}
+
+// flatten copies the API model into the state. Empty approver sets stay null
+// when they were not configured.
+func (s *WorkflowTemplateState) flatten(ctx context.Context, workflow *workflowTemplate) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	s.Name = types.StringValue(workflow.Name)
+	if workflow.Description != "" || !s.Description.IsNull() {
+		s.Description = types.StringValue(workflow.Description)
+	}
+
+	sort.SliceStable(workflow.Steps, func(i, j int) bool {
+		return workflow.Steps[i].Order < workflow.Steps[j].Order
+	})
+
+	steps := make([]WorkflowStepModel, 0, len(workflow.Steps))
+	for _, step := range workflow.Steps {
+		model := WorkflowStepModel{
+			Name:              types.StringValue(step.Name),
+			RequiredApprovals: types.Int64Value(int64(step.MinimumApprovals)),
+			ApproverUserIDs:   types.SetNull(types.Int64Type),
+			ApproverGroupIDs:  types.SetNull(types.Int64Type),
+		}
+		if len(step.UserIDs) > 0 {
+			set, d := types.SetValueFrom(ctx, types.Int64Type, step.UserIDs)
+			diags.Append(d...)
+			model.ApproverUserIDs = set
+		}
+		if len(step.GroupIDs) > 0 {
+			set, d := types.SetValueFrom(ctx, types.Int64Type, step.GroupIDs)
+			diags.Append(d...)
+			model.ApproverGroupIDs = set
+		}
+		steps = append(steps, model)
+	}
+	s.Steps = steps
+	return diags
+}
@@ -0,0 +1,379 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// retryConfig controls how failed Secret Server calls are retried
+type retryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Jitter randomizes each delay by up to this fraction, so that concurrent
+	// calls throttled together do not retry together
+	Jitter float64
+}
+
+// callOptions is the retry and timeout policy applied to a Secret Server call
+type callOptions struct {
+	Retry   retryConfig
+	Timeout time.Duration
+	// OperationTimeouts replace Timeout for calls made while reading, creating,
+	// updating or deleting, keyed by operation
+	OperationTimeouts map[string]time.Duration
+}
+
+// defaultCallOptions retries throttled and failed calls a few times, without a timeout
+var defaultCallOptions = callOptions{
+	Retry: retryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+	},
+}
+
+// RetryModel is the retry block accepted by the provider, data sources and resources
+type RetryModel struct {
+	MaxAttempts types.Int64   `tfsdk:"max_attempts"`
+	BaseDelay   types.String  `tfsdk:"base_delay"`
+	MaxDelay    types.String  `tfsdk:"max_delay"`
+	Jitter      types.Float64 `tfsdk:"jitter"`
+}
+
+var retryBlockDescription = "Overrides how failed Secret Server calls are retried."
+
+var retryAttributeDescriptions = map[string]string{
+	"max_attempts": "The maximum number of attempts, including the first. Defaults to 3.",
+	"base_delay":   "The delay before the first retry, doubled on each further retry, e.g. \"1s\". Defaults to 1s.",
+	"max_delay":    "The maximum delay between retries, e.g. \"30s\". Defaults to 30s.",
+	"jitter":       "The fraction, between 0 and 1, by which each delay is randomly shortened or lengthened. Defaults to 0.2.",
+}
+
+var timeoutAttributeDescription = "The maximum time a single Secret Server call may take, e.g. \"5m\"."
+
+// dataSourceRetryBlock returns the retry block schema for data sources
+func dataSourceRetryBlock() datasourceschema.SingleNestedBlock {
+	return datasourceschema.SingleNestedBlock{
+		Description: retryBlockDescription,
+		Attributes: map[string]datasourceschema.Attribute{
+			"max_attempts": datasourceschema.Int64Attribute{Optional: true, Description: retryAttributeDescriptions["max_attempts"]},
+			"base_delay":   datasourceschema.StringAttribute{Optional: true, Description: retryAttributeDescriptions["base_delay"]},
+			"max_delay":    datasourceschema.StringAttribute{Optional: true, Description: retryAttributeDescriptions["max_delay"]},
+			"jitter":       datasourceschema.Float64Attribute{Optional: true, Description: retryAttributeDescriptions["jitter"]},
+		},
+	}
+}
+
+// dataSourceTimeoutAttribute returns the timeout attribute schema for data sources
+func dataSourceTimeoutAttribute() datasourceschema.StringAttribute {
+	return datasourceschema.StringAttribute{Optional: true, Description: timeoutAttributeDescription}
+}
+
+// providerRetryBlock returns the retry block schema for the provider
+func providerRetryBlock() providerschema.SingleNestedBlock {
+	return providerschema.SingleNestedBlock{
+		Description: "How failed Secret Server calls are retried. Throttled (429) and failed (5xx) calls and network errors are retried " +
+			"with exponential backoff, waiting at least as long as a Retry-After header asks. Calls that create something, such as a new secret, " +
+			"are only retried when they were throttled or could not connect, so they are never made twice. Data sources and resources can override these settings.",
+		Attributes: map[string]providerschema.Attribute{
+			"max_attempts": providerschema.Int64Attribute{Optional: true, Description: retryAttributeDescriptions["max_attempts"]},
+			"base_delay":   providerschema.StringAttribute{Optional: true, Description: retryAttributeDescriptions["base_delay"]},
+			"max_delay":    providerschema.StringAttribute{Optional: true, Description: retryAttributeDescriptions["max_delay"]},
+			"jitter":       providerschema.Float64Attribute{Optional: true, Description: retryAttributeDescriptions["jitter"]},
+		},
+	}
+}
+
+// resourceRetryBlock returns the retry block schema for resources
+func resourceRetryBlock() resourceschema.SingleNestedBlock {
+	return resourceschema.SingleNestedBlock{
+		Description: retryBlockDescription,
+		Attributes: map[string]resourceschema.Attribute{
+			"max_attempts": resourceschema.Int64Attribute{Optional: true, Description: retryAttributeDescriptions["max_attempts"]},
+			"base_delay":   resourceschema.StringAttribute{Optional: true, Description: retryAttributeDescriptions["base_delay"]},
+			"max_delay":    resourceschema.StringAttribute{Optional: true, Description: retryAttributeDescriptions["max_delay"]},
+			"jitter":       resourceschema.Float64Attribute{Optional: true, Description: retryAttributeDescriptions["jitter"]},
+		},
+	}
+}
+
+// resourceTimeoutAttribute returns the timeout attribute schema for resources
+func resourceTimeoutAttribute() resourceschema.StringAttribute {
+	return resourceschema.StringAttribute{Optional: true, Description: timeoutAttributeDescription}
+}
+
+// forOperation returns a copy of the options with the provider's timeout for
+// the operation applied
+func (o callOptions) forOperation(operation string) callOptions {
+	if d, ok := o.OperationTimeouts[operation]; ok {
+		o.Timeout = d
+	}
+	return o
+}
+
+// callOptionsKey is the context key of the call options of an operation
+type callOptionsKey struct{}
+
+// withCallOptions returns ctx carrying the call options of the operation it is
+// for. The api client's calls made with it apply them, including those made
+// by helpers that are not given the options themselves.
+func withCallOptions(ctx context.Context, opts callOptions) context.Context {
+	return context.WithValue(ctx, callOptionsKey{}, opts)
+}
+
+// callOptionsFrom returns the call options ctx carries, or fallback when it
+// carries none
+func callOptionsFrom(ctx context.Context, fallback callOptions) callOptions {
+	if opts, ok := ctx.Value(callOptionsKey{}).(callOptions); ok {
+		return opts
+	}
+	return fallback
+}
+
+// parseDuration parses an optional duration attribute, adding an error to diags
+// when it is invalid. It reports false when the attribute is not set or invalid.
+func parseDuration(diags *diag.Diagnostics, attr path.Path, value types.String) (time.Duration, bool) {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(value.ValueString())
+	if err != nil || d < 0 {
+		diags.AddAttributeError(attr, "Invalid Duration", fmt.Sprintf("%q is not a valid duration such as \"30s\" or \"5m\".", value.ValueString()))
+		return 0, false
+	}
+	return d, true
+}
+
+// withOverrides returns a copy of the options with any per data source or resource
+// overrides applied
+func (o callOptions) withOverrides(retry *RetryModel, timeout types.String) (callOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if d, ok := parseDuration(&diags, path.Root("timeout"), timeout); ok {
+		o.Timeout = d
+	}
+	o.Retry = o.Retry.withOverrides(&diags, retry)
+
+	return o, diags
+}
+
+// withOverrides returns a copy of the retry configuration with the settings of
+// a retry block applied
+func (c retryConfig) withOverrides(diags *diag.Diagnostics, retry *RetryModel) retryConfig {
+	if retry == nil {
+		return c
+	}
+
+	if !retry.MaxAttempts.IsNull() && !retry.MaxAttempts.IsUnknown() {
+		if retry.MaxAttempts.ValueInt64() < 1 {
+			diags.AddAttributeError(path.Root("retry").AtName("max_attempts"), "Invalid Retry Configuration", "max_attempts must be at least 1.")
+		} else {
+			c.MaxAttempts = int(retry.MaxAttempts.ValueInt64())
+		}
+	}
+	if d, ok := parseDuration(diags, path.Root("retry").AtName("base_delay"), retry.BaseDelay); ok {
+		c.BaseDelay = d
+	}
+	if d, ok := parseDuration(diags, path.Root("retry").AtName("max_delay"), retry.MaxDelay); ok {
+		c.MaxDelay = d
+	}
+	if !retry.Jitter.IsNull() && !retry.Jitter.IsUnknown() {
+		if j := retry.Jitter.ValueFloat64(); j < 0 || j > 1 {
+			diags.AddAttributeError(path.Root("retry").AtName("jitter"), "Invalid Retry Configuration", "jitter must be between 0 and 1.")
+		} else {
+			c.Jitter = j
+		}
+	}
+	return c
+}
+
+// statusCodePattern extracts the HTTP status code the SDK puts at the start of its errors
+var statusCodePattern = regexp.MustCompile(`^(\d{3}) `)
+
+// errorStatusCode returns the HTTP status code of a failed Secret Server call, or 0
+func errorStatusCode(err error) int {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	if m := statusCodePattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+	return 0
+}
+
+// retryAfterError is a failed SDK call with the delay a throttled Secret Server
+// asked for, which the SDK's own errors do not carry
+type retryAfterError struct {
+	error
+	after time.Duration
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.error
+}
+
+// retryAfter returns the delay a throttled Secret Server asked for with a
+// Retry-After header, or 0
+func retryAfter(err error) time.Duration {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	var afterErr *retryAfterError
+	if errors.As(err, &afterErr) {
+		return afterErr.after
+	}
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// jittered randomizes d by up to the given fraction in either direction
+func jittered(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + jitter*(2*rand.Float64()-1)))
+}
+
+// isDialError reports whether err is a failure to connect, so the request was
+// never sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "dial" || opErr.Op == "proxyconnect")
+}
+
+// isRetryableError reports whether a call that failed may succeed when
+// repeated. Calls that are safe to repeat, GET, PUT and DELETE, are retried
+// after network errors and throttled (429) or failed (5xx) responses. A POST
+// may have taken effect before it failed, so it is only retried when it was
+// throttled or never sent. A write whose attempt was abandoned may still
+// complete, so it is never retried.
+func isRetryableError(method string, err error) bool {
+	var abandoned *abandonedCallError
+	if errors.As(err, &abandoned) {
+		return method == http.MethodGet
+	}
+	code := errorStatusCode(err)
+	if code == http.StatusTooManyRequests || isDialError(err) {
+		return true
+	}
+	if method == http.MethodPost {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return code >= 500
+}
+
+// withRetry runs op, a call with the given HTTP method, under the given policy. Each attempt is bounded by the timeout;
+// since the SDK does not accept a context, an attempt that times out is abandoned
+// rather than cancelled.
+func withRetry[T any](ctx context.Context, method string, opts callOptions, op func() (T, error)) (T, error) {
+	var zero T
+	delay := opts.Retry.BaseDelay
+
+	for attempt := 1; ; attempt++ {
+		result, err := callWithTimeout(ctx, opts.Timeout, op)
+		if err == nil {
+			return result, nil
+		}
+
+		if attempt >= opts.Retry.MaxAttempts || !isRetryableError(method, err) {
+			return zero, err
+		}
+
+		// A server asking for a longer pause is honored, even beyond MaxDelay
+		wait := jittered(delay, opts.Retry.Jitter)
+		if after := retryAfter(err); after > wait {
+			wait = after
+		}
+
+		tflog.Debug(ctx, "Retrying failed Secret Server call", map[string]interface{}{
+			"attempt": attempt,
+			"delay":   wait.String(),
+			"error":   err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if opts.Retry.MaxDelay > 0 && delay > opts.Retry.MaxDelay {
+			delay = opts.Retry.MaxDelay
+		}
+	}
+}
+
+// abandonedCallError is a call that did not complete within its timeout. The
+// SDK takes no context, so the call may still be running.
+type abandonedCallError struct {
+	timeout time.Duration
+}
+
+func (e *abandonedCallError) Error() string {
+	return fmt.Sprintf("Secret Server call did not complete within %s", e.timeout)
+}
+
+// callWithTimeout runs op, giving up once timeout has elapsed. A zero timeout waits indefinitely.
+func callWithTimeout[T any](ctx context.Context, timeout time.Duration, op func() (T, error)) (T, error) {
+	if timeout <= 0 {
+		return op()
+	}
+
+	type outcome struct {
+		result T
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := op()
+		done <- outcome{result, err}
+	}()
+
+	var zero T
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-time.After(timeout):
+		return zero, &abandonedCallError{timeout}
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
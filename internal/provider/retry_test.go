@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// noDelay retries immediately, so the tests do not wait
+var noDelay = callOptions{Retry: retryConfig{MaxAttempts: 3}}
+
+func TestIsRetryableError(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "https://tss.example.test", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	readErr := &url.Error{Op: "Post", URL: "https://tss.example.test", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}}
+	throttled := &apiError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}
+	failed := &apiError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}
+	sdkFailed := errors.New("500 Internal Server Error")
+	abandoned := &abandonedCallError{time.Minute}
+
+	for _, test := range []struct {
+		method string
+		err    error
+		want   bool
+	}{
+		{http.MethodGet, readErr, true},
+		{http.MethodGet, failed, true},
+		{http.MethodGet, sdkFailed, true},
+		{http.MethodGet, abandoned, true},
+		{http.MethodGet, &apiError{StatusCode: http.StatusNotFound}, false},
+		{http.MethodPut, readErr, true},
+		{http.MethodPut, failed, true},
+		{http.MethodPut, abandoned, false},
+		{http.MethodDelete, failed, true},
+		{http.MethodDelete, abandoned, false},
+		{http.MethodPost, throttled, true},
+		{http.MethodPost, dialErr, true},
+		{http.MethodPost, readErr, false},
+		{http.MethodPost, failed, false},
+		{http.MethodPost, sdkFailed, false},
+		{http.MethodPost, abandoned, false},
+	} {
+		if got := isRetryableError(test.method, test.err); got != test.want {
+			t.Errorf("isRetryableError(%s, %v) = %t, want %t", test.method, test.err, got, test.want)
+		}
+	}
+}
+
+func TestWithRetryDoesNotRepeatFailedPost(t *testing.T) {
+	attempts := 0
+	_, err := withRetry(context.Background(), http.MethodPost, noDelay, func() (struct{}, error) {
+		attempts++
+		return struct{}{}, &apiError{StatusCode: http.StatusInternalServerError}
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("a POST failing with 500 was attempted %d times, error %v", attempts, err)
+	}
+
+	attempts = 0
+	_, err = withRetry(context.Background(), http.MethodPost, noDelay, func() (struct{}, error) {
+		attempts++
+		if attempts == 1 {
+			return struct{}{}, &apiError{StatusCode: http.StatusTooManyRequests}
+		}
+		return struct{}{}, nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("a throttled POST was attempted %d times, error %v", attempts, err)
+	}
+}
+
+func TestWithRetryDoesNotRepeatAbandonedWrite(t *testing.T) {
+	opts := noDelay
+	opts.Timeout = 10 * time.Millisecond
+	release := make(chan struct{})
+	defer close(release)
+
+	for method, want := range map[string]int{http.MethodGet: 3, http.MethodPut: 1, http.MethodPost: 1} {
+		attempts := 0
+		_, err := withRetry(context.Background(), method, opts, func() (struct{}, error) {
+			attempts++
+			<-release
+			return struct{}{}, nil
+		})
+		var abandoned *abandonedCallError
+		if !errors.As(err, &abandoned) {
+			t.Errorf("%s: expected the call to be abandoned, got %v", method, err)
+		}
+		if attempts != want {
+			t.Errorf("%s: attempted %d times, want %d", method, attempts, want)
+		}
+	}
+}
+
+func TestWithRetryHonorsRetryAfter(t *testing.T) {
+	opts := noDelay
+	opts.Retry.MaxAttempts = 2
+	for name, err := range map[string]error{
+		"api client": &apiError{StatusCode: http.StatusTooManyRequests, RetryAfter: 50 * time.Millisecond},
+		"sdk":        &retryAfterError{errors.New("429 Too Many Requests"), 50 * time.Millisecond},
+	} {
+		attempts := 0
+		start := time.Now()
+		_, _ = withRetry(context.Background(), http.MethodGet, opts, func() (struct{}, error) {
+			attempts++
+			if attempts == 1 {
+				return struct{}{}, err
+			}
+			return struct{}{}, nil
+		})
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("%s: retried after %s, before the Retry-After delay", name, elapsed)
+		}
+	}
+}
+
+func TestProviderTransportRecordsRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	transport := &providerTransport{base: baseTransport}
+	if after := transport.retryAfter(time.Now()); after != 0 {
+		t.Fatalf("retryAfter before any response = %s", after)
+	}
+	res, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if after := transport.retryAfter(time.Now()); after < 29*time.Second || after > 30*time.Second {
+		t.Errorf("retryAfter = %s, want about 30s", after)
+	}
+	if after := transport.retryAfter(time.Now().Add(time.Minute)); after != 0 {
+		t.Errorf("retryAfter once the delay has passed = %s", after)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for value, want := range map[string]time.Duration{
+		"":                              0,
+		"120":                           2 * time.Minute,
+		"-1":                            0,
+		"soon":                          0,
+		"Mon, 01 Jan 2024 12:00:30 GMT": 30 * time.Second,
+		"Mon, 01 Jan 2024 11:00:00 GMT": 0,
+	} {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestErrorStatusCode(t *testing.T) {
+	for err, want := range map[error]int{
+		&apiError{StatusCode: 404}:                            404,
+		fmt.Errorf("wrapped: %w", &apiError{StatusCode: 503}): 503,
+		errors.New("429 Too Many Requests"):                   429,
+		errors.New("connection refused"):                      0,
+	} {
+		if got := errorStatusCode(err); got != want {
+			t.Errorf("errorStatusCode(%v) = %d, want %d", err, got, want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stateFile is the part of a Terraform state file the scan-state command reads
+type stateFile struct {
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   interface{}            `json:"index_key"`
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+	Outputs map[string]struct {
+		Value interface{} `json:"value"`
+	} `json:"outputs"`
+}
+
+// secretValueRef identifies the secret field a value is stored in
+type secretValueRef struct {
+	SecretID   int
+	SecretName string
+	FieldName  string
+}
+
+// stateLeak is a state attribute holding the value of a secret field
+type stateLeak struct {
+	Address   string
+	Attribute string
+	Secret    secretValueRef
+}
+
+// ScanState implements the scan-state command. It reports the attributes of a
+// state file that hold the plaintext value of a secret field, comparing SHA-256
+// hashes so values are never printed. It connects as described for cliClient,
+// and fails when any value is found.
+func ScanState(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("scan-state", flag.ContinueOnError)
+	statePath := flags.String("state", "terraform.tfstate", "the state file to scan, e.g. from terraform state pull")
+	folderID := flags.Int("folder", 0, "the ID of the folder whose secrets are compared; defaults to every secret the user can view")
+	subFolders := flags.Bool("include-subfolders", false, "also compare the secrets of subfolders")
+	allFields := flags.Bool("all-fields", false, "compare every field value rather than only password fields")
+	minLength := flags.Int("min-length", 4, "ignore field values shorter than this, which match by coincidence")
+	parallelism := flags.Int("parallelism", 8, "the number of secrets read at the same time")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *parallelism < 1 {
+		return fmt.Errorf("--parallelism must be at least 1")
+	}
+
+	data, err := os.ReadFile(*statePath)
+	if err != nil {
+		return err
+	}
+	var state stateFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse state file %s (decrypt it first if it is encrypted): %w", *statePath, err)
+	}
+
+	tssClient, api, err := cliClient()
+	if err != nil {
+		return err
+	}
+
+	summaries, err := api.searchSecrets(ctx, secretSearchFilter{FolderID: *folderID, IncludeSubFolders: *subFolders})
+	if err != nil {
+		return fmt.Errorf("failed to list secrets: %w", err)
+	}
+	secrets, err := readSecretsInParallel(ctx, tssClient, api, summaries, *parallelism)
+	if err != nil {
+		return err
+	}
+
+	hashes := map[[sha256.Size]byte][]secretValueRef{}
+	for _, secret := range secrets {
+		for _, field := range secret.Fields {
+			if field.IsFile || (!*allFields && !field.IsPassword) || len(field.ItemValue) < *minLength {
+				continue
+			}
+			sum := sha256.Sum256([]byte(field.ItemValue))
+			hashes[sum] = append(hashes[sum], secretValueRef{SecretID: secret.ID, SecretName: secret.Name, FieldName: field.FieldName})
+		}
+	}
+
+	leaks := scanStateValues(&state, hashes)
+	if err := writeStateLeaks(os.Stdout, leaks); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Compared %s against %d secrets\n", *statePath, len(secrets))
+	if len(leaks) > 0 {
+		return fmt.Errorf("found %d plaintext secret values in %s", len(leaks), *statePath)
+	}
+	return nil
+}
+
+// scanStateValues returns the resource attributes and outputs of the state whose
+// string values hash to one of the secret field values
+func scanStateValues(state *stateFile, hashes map[[sha256.Size]byte][]secretValueRef) []stateLeak {
+	var leaks []stateLeak
+	visit := func(address string) func(attribute, value string) {
+		return func(attribute, value string) {
+			for _, ref := range hashes[sha256.Sum256([]byte(value))] {
+				leaks = append(leaks, stateLeak{Address: address, Attribute: attribute, Secret: ref})
+			}
+		}
+	}
+
+	for _, resource := range state.Resources {
+		for _, instance := range resource.Instances {
+			address := stateAddress(resource.Module, resource.Mode, resource.Type, resource.Name, instance.IndexKey)
+			for name, value := range instance.Attributes {
+				walkStateValue(name, value, visit(address))
+			}
+		}
+	}
+	for name, output := range state.Outputs {
+		walkStateValue("value", output.Value, visit("output."+name))
+	}
+
+	sort.Slice(leaks, func(i, j int) bool {
+		if leaks[i].Address != leaks[j].Address {
+			return leaks[i].Address < leaks[j].Address
+		}
+		return leaks[i].Attribute < leaks[j].Attribute
+	})
+	return leaks
+}
+
+// stateAddress returns the address of a resource instance of a state file, e.g.
+// module.app.dept-tss_resource_secret.db["prod"]
+func stateAddress(module, mode, resourceType, name string, indexKey interface{}) string {
+	address := resourceType + "." + name
+	if mode == "data" {
+		address = "data." + address
+	}
+	if module != "" {
+		address = module + "." + address
+	}
+	switch key := indexKey.(type) {
+	case float64:
+		address += "[" + strconv.FormatFloat(key, 'f', -1, 64) + "]"
+	case string:
+		address += "[" + strconv.Quote(key) + "]"
+	}
+	return address
+}
+
+// walkStateValue calls visit with the path and value of every string within value
+func walkStateValue(attribute string, value interface{}, visit func(attribute, value string)) {
+	switch v := value.(type) {
+	case string:
+		visit(attribute, v)
+	case []interface{}:
+		for i, element := range v {
+			walkStateValue(fmt.Sprintf("%s[%d]", attribute, i), element, visit)
+		}
+	case map[string]interface{}:
+		for key, element := range v {
+			walkStateValue(attribute+"."+key, element, visit)
+		}
+	}
+}
+
+// writeStateLeaks writes a line per leaked value, naming the secret field it matches
+func writeStateLeaks(w io.Writer, leaks []stateLeak) error {
+	var b strings.Builder
+	for _, leak := range leaks {
+		fmt.Fprintf(&b, "%s: %s holds the value of field %q of secret %d (%s)\n",
+			leak.Address, leak.Attribute, leak.Secret.FieldName, leak.Secret.SecretID, leak.Secret.SecretName)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// sdkClient applies a retry and timeout policy to calls made through the SDK server
+type sdkClient struct {
+	ctx    context.Context
+	server *server.Server
+	api    *apiClient
+	opts   callOptions
+}
+
+// newSDKClient returns an sdkClient for a single operation. The apiClient supplies
+// the access tokens from its cache; it may be nil.
+func newSDKClient(ctx context.Context, s *server.Server, api *apiClient, opts callOptions) sdkClient {
+	return sdkClient{ctx: ctx, server: s, api: api, opts: opts}
+}
+
+// sdkCall runs op under the client's retry and timeout policy. method and path
+// describe the call op makes, for the audit log.
+func sdkCall[T any](c sdkClient, method, path string, op func(s *server.Server) (T, error)) (T, error) {
+	var span trace.Span = noop.Span{}
+	if c.api != nil {
+		_, span = c.api.startSpan(c.ctx, method, path)
+	}
+	attempts := 0
+	result, err := withRetry(c.ctx, method, c.opts, func() (T, error) {
+		attempts++
+		var zero T
+		s := c.server
+		if c.api != nil {
+			// The api client authenticates for the SDK, which would otherwise
+			// request a token for every call
+			var err error
+			if s, err = c.api.sdkServer(c.ctx, c.server, method); err != nil {
+				return zero, err
+			}
+
+			if err := c.api.budget.spend(); err != nil {
+				return zero, err
+			}
+			release, err := c.api.limiter.acquire(c.ctx)
+			if err != nil {
+				return zero, err
+			}
+			defer release()
+		}
+
+		start := time.Now()
+		result, err := op(s)
+		if c.api != nil {
+			c.api.audit.record(c.api.baseURL(), method, path, nil, start, err)
+			if err != nil && c.api.transport != nil {
+				if after := c.api.transport.retryAfter(time.Now()); after > 0 {
+					err = &retryAfterError{err, after}
+				}
+			}
+		}
+		return result, err
+	})
+	endSpan(span, attempts, err)
+	return result, explainAuthError(err, c.server.Credentials.Token != "")
+}
+
+func (c sdkClient) Secret(id int) (*server.Secret, error) {
+	return sdkCall(c, "GET", fmt.Sprintf("secrets/%d", id), func(s *server.Server) (*server.Secret, error) {
+		return s.Secret(id)
+	})
+}
+
+// SecretMetadata returns the secret without the content of its file
+// attachments, which the SDK downloads whenever it reads a secret; their fields
+// keep the attachment ID and file name. Without an api client it reads the
+// secret through the SDK, attachments included.
+func (c sdkClient) SecretMetadata(id int) (*server.Secret, error) {
+	if c.api == nil {
+		return c.Secret(id)
+	}
+	secret := new(server.Secret)
+	if err := c.api.do(withCallOptions(c.ctx, c.opts), "GET", fmt.Sprintf("secrets/%d", id), nil, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// SecretFile returns the content of the file attachment in the field of secret
+// id with the given slug
+func (c sdkClient) SecretFile(id int, slug string) (string, error) {
+	if c.api == nil {
+		secret, err := c.Secret(id)
+		if err != nil {
+			return "", err
+		}
+		content, _ := secret.Field(slug)
+		return content, nil
+	}
+	var content []byte
+	if err := c.api.do(withCallOptions(c.ctx, c.opts), "GET", fmt.Sprintf("secrets/%d/fields/%s", id, url.PathEscape(slug)), nil, &content); err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// ensureHealthy applies the api client's health gate before a write
+func (c sdkClient) ensureHealthy() error {
+	if c.api == nil {
+		return nil
+	}
+	return c.api.ensureHealthy(c.ctx)
+}
+
+func (c sdkClient) CreateSecret(secret server.Secret) (*server.Secret, error) {
+	if err := c.ensureHealthy(); err != nil {
+		return nil, err
+	}
+	return sdkCall(c, "POST", "secrets", func(s *server.Server) (*server.Secret, error) {
+		return s.CreateSecret(secret)
+	})
+}
+
+func (c sdkClient) UpdateSecret(secret server.Secret) (*server.Secret, error) {
+	if err := c.ensureHealthy(); err != nil {
+		return nil, err
+	}
+	return sdkCall(c, "PUT", fmt.Sprintf("secrets/%d", secret.ID), func(s *server.Server) (*server.Secret, error) {
+		return s.UpdateSecret(secret)
+	})
+}
+
+func (c sdkClient) DeleteSecret(id int) error {
+	if err := c.ensureHealthy(); err != nil {
+		return err
+	}
+	_, err := sdkCall(c, "DELETE", fmt.Sprintf("secrets/%d", id), func(s *server.Server) (struct{}, error) {
+		return struct{}{}, s.DeleteSecret(id)
+	})
+	return err
+}
+
+func (c sdkClient) SecretTemplate(id int) (*server.SecretTemplate, error) {
+	return sdkCall(c, "GET", fmt.Sprintf("secret-templates/%d", id), func(s *server.Server) (*server.SecretTemplate, error) {
+		return s.SecretTemplate(id)
+	})
+}
+
+func (c sdkClient) GeneratePassword(slug string, template *server.SecretTemplate) (string, error) {
+	return sdkCall(c, "POST", "secret-templates/generate-password/"+slug, func(s *server.Server) (string, error) {
+		return s.GeneratePassword(slug, template)
+	})
+}
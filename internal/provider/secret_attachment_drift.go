@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// attachmentDrift compares the checksums of the attachments read back from
+// Secret Server with the content_sha256 stored in state, and reports the attachments replaced or
+// removed outside of Terraform. onDrift "error" fails the refresh; anything
+// else reports a warning.
+func attachmentDrift(ctx context.Context, secretID string, fields, stateFields []SecretField, onDrift types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, field := range fields {
+		if !field.IsFile.ValueBool() {
+			continue
+		}
+		for _, oldField := range stateFields {
+			if !strings.EqualFold(oldField.FieldName.ValueString(), field.FieldName.ValueString()) {
+				continue
+			}
+			stored := oldField.ContentSHA256.ValueString()
+			if stored == "" || stored == field.ContentSHA256.ValueString() {
+				break
+			}
+
+			change := "replaced"
+			if field.ContentSHA256.ValueString() == "" {
+				change = "removed"
+			}
+			tflog.Warn(ctx, "File attachment checksum changed outside of Terraform", map[string]interface{}{
+				"id":     secretID,
+				"field":  field.FieldName.ValueString(),
+				"change": change,
+			})
+			summary := fmt.Sprintf("The attachment in field '%s' of secret %s was %s outside of Terraform: its checksum was %s and is now %q.",
+				field.FieldName.ValueString(), secretID, change, stored, field.ContentSHA256.ValueString())
+			if onDrift.ValueString() == "error" {
+				diags.AddAttributeError(path.Root("on_attachment_drift"), "File Attachment Drift",
+					summary+" Apply the configuration to upload it again, or unset on_attachment_drift to refresh anyway.")
+			} else {
+				diags.AddWarning("File Attachment Drift", summary)
+			}
+			break
+		}
+	}
+	return diags
+}
+
+// hasAttachment reports whether a field of a secret read from Secret Server
+// holds a file attachment
+func hasAttachment(field server.SecretField) bool {
+	return field.IsFile && field.FileAttachmentID != 0 && field.Filename != ""
+}
+
+// loadChangedAttachments downloads the content of the attachments of secret,
+// read without them, that differ from those of prior. Secret Server gives a
+// replaced attachment a new attachment ID, so an attachment with the ID of the
+// field of prior is unchanged, and the state of that field is returned by name
+// instead. Fields whose value state holds only as a hash are always
+// downloaded, since their value is hashed again.
+func loadChangedAttachments(ctx context.Context, client sdkClient, secret *server.Secret, prior []SecretField) (map[string]SecretField, error) {
+	unchanged := map[string]SecretField{}
+	for i, field := range secret.Fields {
+		if !hasAttachment(field) {
+			continue
+		}
+		priorField, ok := secretFieldNamed(prior, field.FieldName)
+		if ok && priorField.FileAttachmentID.ValueInt64() == int64(field.FileAttachmentID) &&
+			priorField.ContentSHA256.ValueString() != "" && storesValueInState(priorField) {
+			unchanged[strings.ToLower(field.FieldName)] = priorField
+			continue
+		}
+
+		tflog.Debug(ctx, "Downloading file attachment", map[string]interface{}{
+			"id":    secret.ID,
+			"field": field.FieldName,
+		})
+		content, err := client.SecretFile(secret.ID, field.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download the attachment of field '%s': %w", field.FieldName, err)
+		}
+		secret.Fields[i].ItemValue = content
+	}
+	return unchanged, nil
+}
+
+// keepUnchangedAttachments gives the fields with an unchanged attachment the
+// value and checksum of their prior state, as they were not downloaded
+func keepUnchangedAttachments(fields []SecretField, unchanged map[string]SecretField) {
+	for i, field := range fields {
+		if priorField, ok := unchanged[strings.ToLower(field.FieldName.ValueString())]; ok {
+			fields[i].ItemValue = priorField.ItemValue
+			fields[i].ContentSHA256 = priorField.ContentSHA256
+		}
+	}
+}
+
+// secretFieldNamed returns the field of fields named name
+func secretFieldNamed(fields []SecretField, name string) (SecretField, bool) {
+	for _, field := range fields {
+		if strings.EqualFold(field.FieldName.ValueString(), name) {
+			return field, true
+		}
+	}
+	return SecretField{}, false
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLoadChangedAttachmentsDownloadsOnlyReplacedAttachments(t *testing.T) {
+	downloads := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/secrets/7":
+			_, _ = w.Write([]byte(`{"ID":7,"Name":"certs","Items":[
+				{"FieldName":"Certificate","Slug":"certificate","IsFile":true,"FileAttachmentID":10,"Filename":"cert.pem"},
+				{"FieldName":"Private Key","Slug":"private-key","IsFile":true,"FileAttachmentID":21,"Filename":"key.pem"},
+				{"FieldName":"Notes","Slug":"notes","ItemValue":"rotated yearly"}]}`))
+		case "/api/v1/secrets/7/fields/certificate", "/api/v1/secrets/7/fields/private-key":
+			downloads[r.URL.Path]++
+			_, _ = w.Write([]byte("downloaded " + r.URL.Path))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	client := newSDKClient(context.Background(), &server.Server{}, newTestAPIClient(ts.URL), callOptions{})
+	prior := []SecretField{
+		{
+			FieldName:        types.StringValue("certificate"),
+			ItemValue:        types.StringValue("cert content"),
+			FileAttachmentID: types.Int64Value(10),
+			ContentSHA256:    types.StringValue(attachmentChecksum("cert content")),
+		},
+		{
+			FieldName:        types.StringValue("Private Key"),
+			ItemValue:        types.StringValue("old key content"),
+			FileAttachmentID: types.Int64Value(20),
+			ContentSHA256:    types.StringValue(attachmentChecksum("old key content")),
+		},
+	}
+
+	secret, err := client.SecretMetadata(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unchanged, err := loadChangedAttachments(context.Background(), client, secret, prior)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := downloads["/api/v1/secrets/7/fields/certificate"]; n != 0 {
+		t.Errorf("the unchanged attachment was downloaded %d times", n)
+	}
+	if n := downloads["/api/v1/secrets/7/fields/private-key"]; n != 1 {
+		t.Errorf("the replaced attachment was downloaded %d times", n)
+	}
+
+	state, err := flattenSecret(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keepUnchangedAttachments(state.Fields, unchanged)
+	want := map[string]string{
+		"Certificate": attachmentChecksum("cert content"),
+		"Private Key": attachmentChecksum("downloaded /api/v1/secrets/7/fields/private-key"),
+	}
+	for _, field := range state.Fields {
+		if checksum, ok := want[field.FieldName.ValueString()]; ok && field.ContentSHA256.ValueString() != checksum {
+			t.Errorf("checksum of %s = %s, want %s", field.FieldName.ValueString(), field.ContentSHA256.ValueString(), checksum)
+		}
+	}
+
+	drift := attachmentDrift(context.Background(), "7", state.Fields, prior, types.StringValue("error"))
+	if len(drift) != 1 || drift[0].Summary() != "File Attachment Drift" {
+		t.Errorf("drift = %v, want one error for the replaced attachment", drift)
+	}
+}
+
+func TestLoadChangedAttachmentsDownloadsHashedValues(t *testing.T) {
+	downloads := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/secrets/7/fields/certificate" {
+			downloads++
+		}
+	}))
+	defer ts.Close()
+
+	client := newSDKClient(context.Background(), &server.Server{}, newTestAPIClient(ts.URL), callOptions{})
+	secret := &server.Secret{ID: 7, Fields: []server.SecretField{
+		{FieldName: "Certificate", Slug: "certificate", IsFile: true, FileAttachmentID: 10, Filename: "cert.pem"},
+	}}
+	// State holds only a hash of the value, which is hashed again on refresh
+	prior := []SecretField{{
+		FieldName:         types.StringValue("Certificate"),
+		ItemValue:         types.StringNull(),
+		FileAttachmentID:  types.Int64Value(10),
+		ContentSHA256:     types.StringValue(attachmentChecksum("cert content")),
+		StoreValueInState: types.BoolValue(false),
+	}}
+	if _, err := loadChangedAttachments(context.Background(), client, secret, prior); err != nil {
+		t.Fatal(err)
+	}
+	if downloads != 1 {
+		t.Errorf("an attachment kept in state as a hash was downloaded %d times, want 1", downloads)
+	}
+}
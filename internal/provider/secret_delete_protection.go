@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// deletionBlocked returns why prevent_destroy_unless_inactive refuses to delete
+// a secret, or "" when it may be deleted. A secret that no longer exists may be.
+func (c *apiClient) deletionBlocked(ctx context.Context, secretID int) (string, error) {
+	summary, err := c.lookupSecret(ctx, secretID)
+	if isNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case summary.Active:
+		return "it is still active", nil
+	case summary.CheckedOut:
+		return "it is checked out", nil
+	}
+	return "", nil
+}
+
+// checkDeletionAllowed adds an error when prevent_destroy_unless_inactive is set
+// and the secret may not be deleted yet
+func (r *TssSecretResource) checkDeletionAllowed(ctx context.Context, secretID string, prevent types.Bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !prevent.ValueBool() {
+		return diags
+	}
+	id, err := strconv.Atoi(secretID)
+	if err != nil {
+		return diags
+	}
+
+	reason, err := r.api.deletionBlocked(ctx, id)
+	if err != nil {
+		diags.AddError("Secret Deletion Error", fmt.Sprintf("Failed to check whether secret %d may be deleted: %s", id, err))
+		return diags
+	}
+	if reason != "" {
+		diags.AddAttributeError(path.Root("prevent_destroy_unless_inactive"), "Secret Deletion Prevented",
+			fmt.Sprintf("Secret %d cannot be destroyed because %s and prevent_destroy_unless_inactive is set. "+
+				"Deactivate it and check it in within Secret Server first, or unset prevent_destroy_unless_inactive.", id, reason))
+	}
+	return diags
+}
+
+// planDestroy fails a plan that destroys a secret prevent_destroy_unless_inactive
+// still protects, like lifecycle.prevent_destroy but aware of the secret's
+// status on the server. Delete checks again, as the status may change in between.
+func (r *TssSecretResource) planDestroy(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || r.api == nil || r.api.offline {
+		return
+	}
+	var state SecretResourceState
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || !state.PreventDestroyUnlessInactive.ValueBool() {
+		return
+	}
+	r, aliasDiags := r.withAuthAlias(state.AuthAlias)
+	resp.Diagnostics.Append(aliasDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.checkDeletionAllowed(ctx, state.ID.ValueString(), state.PreventDestroyUnlessInactive)...)
+}
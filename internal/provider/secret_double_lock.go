@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// writeDoubleLockedSecret creates (POST to "secrets") or updates (PUT to
+// "secrets/{id}") a secret protected by a DoubleLock, then reads it back. The SDK
+// secret model has no DoubleLock settings, so the request is sent by the api client
+// with them added, and the secret is never written without its lock.
+func (c *apiClient) writeDoubleLockedSecret(ctx context.Context, client sdkClient, secret server.Secret, doubleLockID int) (*server.Secret, error) {
+	// The SDK uploads file fields separately after writing the secret; that
+	// second write would not carry the lock
+	var fields []server.SecretField
+	for _, field := range secret.Fields {
+		if field.IsFile {
+			if field.ItemValue != "" {
+				return nil, fmt.Errorf("file field %q cannot be set on a secret with double_lock_id", field.FieldName)
+			}
+			continue
+		}
+		fields = append(fields, field)
+	}
+	secret.Fields = fields
+	if secret.Fields == nil {
+		secret.Fields = []server.SecretField{}
+	}
+	if secret.SshKeyArgs != nil && !secret.SshKeyArgs.GenerateSshKeys && !secret.SshKeyArgs.GeneratePassphrase {
+		secret.SshKeyArgs = nil
+	}
+
+	data, err := json.Marshal(secret)
+	if err != nil {
+		return nil, err
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	body["isDoubleLock"] = true
+	body["doubleLockId"] = doubleLockID
+
+	method, path := "POST", "secrets"
+	if secret.ID != 0 {
+		method, path = "PUT", "secrets/"+strconv.Itoa(secret.ID)
+	}
+	tflog.Debug(ctx, "Writing double locked secret", map[string]interface{}{
+		"method":         method,
+		"double_lock_id": doubleLockID,
+	})
+
+	var written struct {
+		ID int
+	}
+	if err := c.do(ctx, method, path, body, &written); err != nil {
+		return nil, err
+	}
+	return client.Secret(written.ID)
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// expireSecret expires a secret now, so Secret Server's heartbeat and Remote
+// Password Changing pick it up without waiting for its expiration schedule
+func (c *apiClient) expireSecret(ctx context.Context, secretID int) error {
+	return c.do(ctx, "POST", fmt.Sprintf("secrets/%d/expire", secretID), nil, nil)
+}
+
+// expireAfterUpdate expires an updated secret when expire_on_update is set.
+// The secret was updated either way, so failing to expire it is a warning.
+func (r *TssSecretResource) expireAfterUpdate(ctx context.Context, secretID int, expireOnUpdate types.Bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !expireOnUpdate.ValueBool() || r.api == nil {
+		return diags
+	}
+
+	tflog.Info(ctx, "Expiring updated secret", map[string]interface{}{
+		"id": secretID,
+	})
+	if err := r.api.expireSecret(ctx, secretID); err != nil {
+		tflog.Warn(ctx, "Failed to expire secret", map[string]interface{}{
+			"id":    secretID,
+			"error": err.Error(),
+		})
+		diags.AddWarning("Secret Not Expired",
+			fmt.Sprintf("Secret %d was updated, but expiring it failed, so it is only picked up by its expiration schedule: %s", secretID, err))
+	}
+	return diags
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// addedField is a field an update adds to a secret that had no value for it
+type addedField struct {
+	name  string
+	slug  string
+	value string
+}
+
+// addedFields returns the fields an update only adds, when the plan differs
+// from state by nothing but fields that were empty and are now set in config.
+// It returns nil when the update has to rewrite the secret: another attribute
+// or field changed, a value is unknown, or an added field is a file.
+func (r *TssSecretResource) addedFields(ctx context.Context, req resource.UpdateRequest, client sdkClient, plan, state *SecretResourceState) []addedField {
+	if r.api == nil || !plan.DoubleLockID.IsNull() {
+		return nil
+	}
+
+	diffs, err := req.State.Raw.Diff(req.Plan.Raw)
+	if err != nil {
+		return nil
+	}
+	for _, d := range diffs {
+		steps := d.Path.Steps()
+		if len(steps) == 0 {
+			return nil
+		}
+		if name, ok := steps[0].(tftypes.AttributeName); !ok || name != "fields" {
+			return nil
+		}
+	}
+
+	stateFields := map[string]SecretField{}
+	for _, f := range state.Fields {
+		stateFields[strings.ToLower(f.FieldName.ValueString())] = f
+	}
+	planFields := map[string]bool{}
+
+	var candidates []SecretField
+	for _, f := range plan.Fields {
+		if f.FieldName.IsUnknown() || f.ItemValue.IsUnknown() || f.Filename.IsUnknown() {
+			return nil
+		}
+		name := strings.ToLower(f.FieldName.ValueString())
+		planFields[name] = true
+
+		existing, found := stateFields[name]
+		if found && existing.ItemValue.ValueString() != "" {
+			if existing.ItemValue.ValueString() != f.ItemValue.ValueString() ||
+				existing.Filename.ValueString() != f.Filename.ValueString() {
+				return nil
+			}
+			continue
+		}
+		if f.ItemValue.ValueString() == "" {
+			continue
+		}
+		if f.Filename.ValueString() != "" || f.IsFile.ValueBool() || existing.IsFile.ValueBool() {
+			return nil
+		}
+		candidates = append(candidates, f)
+	}
+
+	// A field dropped from config is cleared by the full update
+	for name, f := range stateFields {
+		if f.ItemValue.ValueString() != "" && !planFields[name] {
+			return nil
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	templateID, err := strconv.Atoi(plan.SecretTemplateID.ValueString())
+	if err != nil {
+		return nil
+	}
+	template, err := client.SecretTemplate(templateID)
+	if err != nil {
+		tflog.Debug(ctx, "Failed to read secret template, updating the whole secret", map[string]interface{}{
+			"template_id": templateID,
+			"error":       err.Error(),
+		})
+		return nil
+	}
+
+	added := make([]addedField, 0, len(candidates))
+	for _, f := range candidates {
+		name := f.FieldName.ValueString()
+		slug := ""
+		for _, tf := range template.Fields {
+			if strings.EqualFold(tf.Name, name) || strings.EqualFold(tf.FieldSlugName, name) {
+				if tf.IsFile {
+					return nil
+				}
+				slug = tf.FieldSlugName
+				break
+			}
+		}
+		if slug == "" {
+			return nil
+		}
+		added = append(added, addedField{name: name, slug: slug, value: f.ItemValue.ValueString()})
+	}
+	return added
+}
+
+// updateSecretField sets the value of one field of a secret, leaving its other
+// fields untouched
+func (c *apiClient) updateSecretField(ctx context.Context, secretID int, slug, value string) error {
+	path := fmt.Sprintf("secrets/%d/fields/%s", secretID, url.PathEscape(slug))
+	return c.do(ctx, "PUT", path, map[string]interface{}{"value": value}, nil)
+}
+
+// addFields writes the added fields of a secret one at a time
+func (r *TssSecretResource) addFields(ctx context.Context, secretID int, wait *approvalWait, added []addedField) error {
+	for _, f := range added {
+		tflog.Info(ctx, "Adding field to secret", map[string]interface{}{
+			"id":    secretID,
+			"field": f.name,
+		})
+		_, err := writeWithApproval(ctx, r.api, wait, func() (struct{}, error) {
+			return struct{}{}, r.api.updateSecretField(ctx, secretID, f.slug, f.value)
+		})
+		if err != nil {
+			return fmt.Errorf("field %q: %w", f.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// secretLocks serializes the operations on each secret within the provider
+// process. Terraform reads data sources, renews ephemeral resources and
+// refreshes resources concurrently, and operations on the same secret race on
+// the checkouts and comments Secret Server records for them.
+var secretLocks = &secretLockTable{locks: map[int]*secretLock{}}
+
+// secretLockTable holds a lock per secret while any operation uses it
+type secretLockTable struct {
+	mu    sync.Mutex
+	locks map[int]*secretLock
+}
+
+// secretLock is held by the operation whose token is in the channel. refs
+// counts the operations holding or waiting for it, so it is dropped once unused.
+type secretLock struct {
+	held chan struct{}
+	refs int
+}
+
+// lock waits until no other operation holds the secret, or ctx is done. The
+// returned unlock must be called once the operation has finished.
+func (t *secretLockTable) lock(ctx context.Context, secretID int) (func(), error) {
+	t.mu.Lock()
+	l, ok := t.locks[secretID]
+	if !ok {
+		l = &secretLock{held: make(chan struct{}, 1)}
+		t.locks[secretID] = l
+	}
+	l.refs++
+	t.mu.Unlock()
+
+	release := func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if l.refs--; l.refs == 0 {
+			delete(t.locks, secretID)
+		}
+	}
+
+	select {
+	case l.held <- struct{}{}:
+	default:
+		tflog.Debug(ctx, "Waiting for another operation on the secret", map[string]interface{}{
+			"secret_id": secretID,
+		})
+		select {
+		case l.held <- struct{}{}:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() {
+		<-l.held
+		release()
+	}, nil
+}
+
+// lockSecret locks secretID for the duration of an operation
+func lockSecret(ctx context.Context, secretID int) (func(), error) {
+	return secretLocks.lock(ctx, secretID)
+}
+
+// lockSecretID is lockSecret for the string ID of a secret resource. An ID that
+// is not a number belongs to no secret, so there is nothing to lock.
+func lockSecretID(ctx context.Context, secretID string) (func(), error) {
+	id, err := strconv.Atoi(secretID)
+	if err != nil {
+		return func() {}, nil
+	}
+	return lockSecret(ctx, id)
+}
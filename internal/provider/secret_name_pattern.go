@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// namePatternToken matches the {field} placeholders of a template's secret name
+// pattern, which refer to a field by slug or name
+var namePatternToken = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// secretNamePattern returns the secret name pattern of a template, or "" when
+// secret names are chosen freely
+func (c *apiClient) secretNamePattern(ctx context.Context, templateID int) (string, error) {
+	var template struct {
+		SecretNamePattern string
+	}
+	if err := c.do(ctx, "GET", fmt.Sprintf("secret-templates/%d", templateID), nil, &template); err != nil {
+		return "", err
+	}
+	return template.SecretNamePattern, nil
+}
+
+// expandNamePattern derives a secret name from the pattern and the field values.
+// It reports false when a referenced field value is not known yet.
+func expandNamePattern(pattern string, fields []SecretField) (string, bool, error) {
+	known := true
+	var missing []string
+
+	name := namePatternToken.ReplaceAllStringFunc(pattern, func(token string) string {
+		ref := token[1 : len(token)-1]
+		for _, field := range fields {
+			if !strings.EqualFold(field.FieldName.ValueString(), ref) && !strings.EqualFold(field.Slug.ValueString(), ref) {
+				continue
+			}
+			if field.ItemValue.IsUnknown() {
+				known = false
+				return token
+			}
+			return field.ItemValue.ValueString()
+		}
+		missing = append(missing, ref)
+		return token
+	})
+
+	if len(missing) > 0 {
+		return "", false, fmt.Errorf("the secret name pattern %q refers to fields that are not set: %s", pattern, strings.Join(missing, ", "))
+	}
+	return name, known, nil
+}
+
+// planName derives the planned name of a secret whose template has a secret name
+// pattern, so it matches the one Secret Server assigns, and checks a configured
+// name against the pattern. The pattern is read from the server once the
+// provider is configured.
+func (r *TssSecretResource) planName(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, plan *SecretResourceState) {
+	if r.api == nil || plan.SecretTemplateID.IsUnknown() || r.api.offline {
+		return
+	}
+	r, aliasDiags := r.withAuthAlias(plan.AuthAlias)
+	resp.Diagnostics.Append(aliasDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var configName types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &configName)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pattern, err := r.templateNamePattern(ctx, plan.SecretTemplateID.ValueString())
+	if err != nil {
+		// The template is checked again on apply, so don't fail the plan over it
+		tflog.Warn(ctx, "Failed to read the secret name pattern of the template", map[string]interface{}{
+			"template_id": plan.SecretTemplateID.ValueString(),
+			"error":       err.Error(),
+		})
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name_pattern"), pattern)...)
+
+	if pattern == "" {
+		if configName.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("name"), "Missing Secret Name",
+				fmt.Sprintf("Template %s has no secret name pattern, so name must be set.", plan.SecretTemplateID.ValueString()))
+		}
+		return
+	}
+
+	fieldValues, fieldValuesDiags := configuredFieldValues(ctx, req.Config)
+	resp.Diagnostics.Append(fieldValuesDiags...)
+	if resp.Diagnostics.HasError() || fieldValues.IsUnknown() {
+		return
+	}
+	expected, known, err := expandNamePattern(pattern, withFieldValues(plan, fieldValues).Fields)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("fields"), "Invalid Secret Name Pattern", err.Error())
+		return
+	}
+	if !known || configName.IsUnknown() {
+		return
+	}
+
+	if configName.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name"), expected)...)
+		return
+	}
+	if configName.ValueString() != expected {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Secret Name Does Not Match Pattern",
+			fmt.Sprintf("Template %s names secrets %q, so Secret Server would name this secret %q rather than %q. "+
+				"Set name to %q or leave it unset to derive it from the fields.",
+				plan.SecretTemplateID.ValueString(), pattern, expected, configName.ValueString(), expected))
+	}
+}
+
+// resolveName derives the name of a secret from its template's name pattern when
+// the name or pattern was not known at plan time
+func (r *TssSecretResource) resolveName(ctx context.Context, plan *SecretResourceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	nameKnown := !plan.Name.IsUnknown() && !plan.Name.IsNull()
+	if nameKnown && !plan.NamePattern.IsUnknown() {
+		return diags
+	}
+
+	pattern, err := r.templateNamePattern(ctx, plan.SecretTemplateID.ValueString())
+	if err != nil {
+		diags.AddError("Template Read Error", fmt.Sprintf("Failed to read the secret name pattern of template %s: %s", plan.SecretTemplateID.ValueString(), err))
+		return diags
+	}
+	plan.NamePattern = types.StringValue(pattern)
+	if nameKnown {
+		return diags
+	}
+	if pattern == "" {
+		diags.AddAttributeError(path.Root("name"), "Missing Secret Name",
+			fmt.Sprintf("Template %s has no secret name pattern, so name must be set.", plan.SecretTemplateID.ValueString()))
+		return diags
+	}
+
+	name, _, err := expandNamePattern(pattern, plan.Fields)
+	if err != nil {
+		diags.AddAttributeError(path.Root("fields"), "Invalid Secret Name Pattern", err.Error())
+		return diags
+	}
+	plan.Name = types.StringValue(name)
+	return diags
+}
+
+// templateNamePattern returns the secret name pattern of the template with the given ID
+func (r *TssSecretResource) templateNamePattern(ctx context.Context, templateID string) (string, error) {
+	id, err := strconv.Atoi(templateID)
+	if err != nil {
+		return "", fmt.Errorf("invalid template ID %q", templateID)
+	}
+	return r.api.secretNamePattern(ctx, id)
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// alignPlannedFields plans the attributes the configuration leaves unset on each
+// fields block from the prior state of the field with the same name. Attribute
+// plan modifiers only see the prior state at the same position, which belongs to
+// another field once blocks are added, removed or reordered, and planning its
+// values is what fails applies with ".fields[N].fieldname was X, but now Y".
+// Only values Secret Server computes are left unknown.
+func alignPlannedFields(ctx context.Context, req resource.ModifyPlanRequest, plan *SecretResourceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if req.State.Raw.IsNull() || len(plan.Fields) == 0 {
+		return diags
+	}
+
+	var state SecretResourceState
+	var configList types.List
+	diags.Append(req.State.Get(ctx, &state)...)
+	diags.Append(req.Config.GetAttribute(ctx, path.Root("fields"), &configList)...)
+	if diags.HasError() || configList.IsUnknown() {
+		return diags
+	}
+	var configFields []SecretField
+	diags.Append(configList.ElementsAs(ctx, &configFields, false)...)
+	if diags.HasError() || len(configFields) != len(plan.Fields) {
+		return diags
+	}
+
+	priorFields := map[string]SecretField{}
+	for _, field := range state.Fields {
+		priorFields[strings.ToLower(field.FieldName.ValueString())] = field
+	}
+
+	for i := range plan.Fields {
+		planned, config := &plan.Fields[i], configFields[i]
+		if planned.FieldName.IsUnknown() {
+			continue
+		}
+		prior, found := priorFields[strings.ToLower(planned.FieldName.ValueString())]
+
+		planned.ItemID = plannedInt64(config.ItemID, prior.ItemID, found)
+		planned.FieldID = plannedInt64(config.FieldID, prior.FieldID, found)
+		planned.FileAttachmentID = plannedInt64(config.FileAttachmentID, prior.FileAttachmentID, found)
+		planned.Slug = plannedString(config.Slug, prior.Slug, found)
+		planned.FieldDescription = plannedString(config.FieldDescription, prior.FieldDescription, found)
+		planned.Filename = plannedString(config.Filename, prior.Filename, found)
+		planned.IsFile = plannedBool(config.IsFile, prior.IsFile, found)
+		planned.IsNotes = plannedBool(config.IsNotes, prior.IsNotes, found)
+		planned.IsPassword = plannedBool(config.IsPassword, prior.IsPassword, found)
+		planned.IsList = plannedBool(config.IsList, prior.IsList, found)
+		planned.ListType = plannedString(config.ListType, prior.ListType, found)
+		planned.HistoryLength = plannedInt64(types.Int64Null(), prior.HistoryLength, found)
+
+		// A value left unset keeps the field's current value; a new field is
+		// created empty, as passwords and SSH keys are only generated on create.
+		// Write-only values and values kept out of state are never in state.
+		switch {
+		case !config.ItemValueWO.IsNull() || !config.ItemValueWOVersion.IsNull() || !storesValueInState(config):
+			planned.ItemValue = types.StringNull()
+		case !config.ItemValue.IsNull():
+			planned.ItemValue = config.ItemValue
+		case found:
+			planned.ItemValue = prior.ItemValue
+		default:
+			planned.ItemValue = types.StringValue("")
+		}
+
+		// The checksum of known content is known
+		switch {
+		case planned.IsFile.IsUnknown():
+			planned.ContentSHA256 = types.StringUnknown()
+		case !planned.IsFile.ValueBool():
+			planned.ContentSHA256 = types.StringNull()
+		case planned.ItemValue.IsUnknown() || planned.ItemValue.IsNull():
+			planned.ContentSHA256 = types.StringUnknown()
+		default:
+			planned.ContentSHA256 = types.StringValue(attachmentChecksum(planned.ItemValue.ValueString()))
+		}
+
+		// History only grows when the value changes
+		if found && planned.ItemValue.Equal(prior.ItemValue) && prior.ItemValueWOVersion.Equal(planned.ItemValueWOVersion) {
+			planned.HistoryCount = prior.HistoryCount
+		} else {
+			planned.HistoryCount = types.Int64Unknown()
+		}
+	}
+	return diags
+}
+
+// plannedString returns the planned value of an optional and computed
+// attribute: the configured value, else the prior value of the same field, or
+// unknown for a new field
+func plannedString(config, prior types.String, found bool) types.String {
+	switch {
+	case !config.IsNull():
+		return config
+	case found:
+		return prior
+	}
+	return types.StringUnknown()
+}
+
+// plannedInt64 is plannedString for Int64 attributes
+func plannedInt64(config, prior types.Int64, found bool) types.Int64 {
+	switch {
+	case !config.IsNull():
+		return config
+	case found:
+		return prior
+	}
+	return types.Int64Unknown()
+}
+
+// plannedBool is plannedString for Bool attributes
+func plannedBool(config, prior types.Bool, found bool) types.Bool {
+	switch {
+	case !config.IsNull():
+		return config
+	case found:
+		return prior
+	}
+	return types.BoolUnknown()
+}
+
+// planFieldHistory plans historycount as null unless read_field_history is set,
+// and as unknown on every field when it was only just set, as the counts are
+// first read on apply
+func planFieldHistory(ctx context.Context, req resource.ModifyPlanRequest, plan *SecretResourceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if plan.ReadFieldHistory.IsUnknown() {
+		return diags
+	}
+
+	counts := types.Int64Null()
+	if plan.ReadFieldHistory.ValueBool() {
+		if req.State.Raw.IsNull() {
+			return diags
+		}
+		var prior types.Bool
+		diags.Append(req.State.GetAttribute(ctx, path.Root("read_field_history"), &prior)...)
+		if diags.HasError() || prior.ValueBool() {
+			return diags
+		}
+		counts = types.Int64Unknown()
+	}
+	for i := range plan.Fields {
+		plan.Fields[i].HistoryCount = counts
+	}
+	return diags
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// trackRename compares the name of a secret read back from Secret Server with
+// the name prior expects. A secret renamed outside Terraform keeps the expected
+// name in state when prior has sync_name_from_server set, so no rename back is
+// planned; otherwise the rename is reported, as applying undoes it.
+func trackRename(ctx context.Context, newState, prior *SecretResourceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	newState.ServerName = newState.Name
+	if prior.Name.IsNull() || prior.Name.IsUnknown() || newState.Name.Equal(prior.Name) {
+		return diags
+	}
+
+	if prior.SyncNameFromServer.ValueBool() {
+		tflog.Info(ctx, "Secret was renamed in Secret Server, keeping its name", map[string]interface{}{
+			"id":          newState.ID.ValueString(),
+			"name":        prior.Name.ValueString(),
+			"server_name": newState.ServerName.ValueString(),
+		})
+		newState.Name = prior.Name
+		return diags
+	}
+
+	// Renames that follow the template's name pattern are expected
+	if !prior.NamePattern.IsNull() && prior.NamePattern.ValueString() != "" {
+		return diags
+	}
+	diags.AddAttributeWarning(path.Root("name"), "Secret Renamed Outside Terraform",
+		fmt.Sprintf("Secret %s was renamed from %q to %q in Secret Server; applying the configuration renames it back. "+
+			"Update name to match, or set sync_name_from_server to keep the name given in Secret Server.",
+			newState.ID.ValueString(), prior.Name.ValueString(), newState.Name.ValueString()))
+	return diags
+}
+
+// withServerName returns plan named as the secret is named in Secret Server,
+// so an update does not undo a rename made outside Terraform. Only names the
+// configuration did not change are kept, so renaming in configuration still
+// renames the secret.
+func withServerName(plan, state *SecretResourceState) *SecretResourceState {
+	if !plan.SyncNameFromServer.ValueBool() || !plan.Name.Equal(state.Name) ||
+		state.ServerName.IsNull() || state.ServerName.Equal(state.Name) {
+		return plan
+	}
+	withName := *plan
+	withName.Name = state.ServerName
+	return &withName
+}
@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// rotatedSSHKeyBits is the size of the RSA keys generated when rotating the
+// SSH keys of a secret, which Secret Server only generates on create
+const rotatedSSHKeyBits = 4096
+
+// The parts of an SSH key a secret field holds, by its name or slug
+const (
+	sshKeyPartPrivate    = "private key"
+	sshKeyPartPublic     = "public key"
+	sshKeyPartPassphrase = "passphrase"
+)
+
+// sshKeyPart returns the part of an SSH key a field holds, or "" when it holds
+// none, e.g. "private key" for "Private Key" or "private-key"
+func sshKeyPart(fieldName string) string {
+	name := strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(fieldName))
+	for _, part := range []string{sshKeyPartPassphrase, sshKeyPartPrivate, sshKeyPartPublic} {
+		if strings.Contains(name, part) {
+			return part
+		}
+	}
+	return ""
+}
+
+// rotationRequested reports whether an update regenerates the generated values
+// of a secret: when rotation_triggers is set and differs from state. Removing
+// rotation_triggers rotates nothing.
+func rotationRequested(plan, state *SecretResourceState) bool {
+	return !plan.RotationTriggers.IsNull() && !plan.RotationTriggers.Equal(state.RotationTriggers)
+}
+
+// rotatesField reports whether rotation regenerates a configured field: a
+// password field, or an SSH key field of a secret with generatesshkeys, whose
+// value the provider or Secret Server generated rather than the configuration
+func rotatesField(config SecretField, planned SecretField, sshKeyArgs *SshKeyArgs) bool {
+	if (!config.ItemValue.IsNull() && config.ItemValue.ValueString() != "") || !config.ItemValueWO.IsNull() || hasSourceContent(config) {
+		return false
+	}
+	if planned.IsPassword.ValueBool() {
+		return true
+	}
+	return sshKeyArgs != nil && sshKeyArgs.GenerateSshKeys.ValueBool() && sshKeyPart(planned.FieldName.ValueString()) != ""
+}
+
+// planRotation plans the generated values of the fields blocks as unknown when
+// rotation_triggers changed, as the update regenerates them
+func planRotation(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, plan *SecretResourceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if req.State.Raw.IsNull() || len(plan.Fields) == 0 {
+		return diags
+	}
+	var state SecretResourceState
+	diags.Append(req.State.Get(ctx, &state)...)
+	if diags.HasError() || !rotationRequested(plan, &state) {
+		return diags
+	}
+
+	configFields, configDiags := configuredWriteOnlyFields(ctx, req.Config)
+	diags.Append(configDiags...)
+	if diags.HasError() || len(configFields) != len(plan.Fields) {
+		return diags
+	}
+
+	rotated := false
+	for i := range plan.Fields {
+		if !rotatesField(configFields[i], plan.Fields[i], plan.SshKeyArgs) {
+			continue
+		}
+		tflog.Debug(ctx, "Planning rotation of generated field value", map[string]interface{}{
+			"field": plan.Fields[i].FieldName.ValueString(),
+		})
+		plan.Fields[i].ItemValue = types.StringUnknown()
+		plan.Fields[i].HistoryCount = types.Int64Unknown()
+		rotated = true
+	}
+
+	// field_values holds the rotated values too when it is not configured
+	if rotated && !state.FieldValues.IsNull() {
+		var configFieldValues types.Map
+		diags.Append(req.Config.GetAttribute(ctx, path.Root("field_values"), &configFieldValues)...)
+		if !diags.HasError() && configFieldValues.IsNull() {
+			diags.Append(resp.Plan.SetAttribute(ctx, path.Root("field_values"), types.MapUnknown(types.StringType))...)
+		}
+	}
+	return diags
+}
+
+// rotateGeneratedValues regenerates the values of secret's fields planned as
+// unknown by planRotation: passwords with the resource's password generator,
+// and a new SSH key pair, encrypted with a new passphrase when
+// generatepassphrase is set
+func (r *TssSecretResource) rotateGeneratedValues(ctx context.Context, client sdkClient, plan *SecretResourceState, secret *server.Secret) error {
+	rotated := map[string]bool{}
+	for _, field := range plan.Fields {
+		if field.ItemValue.IsUnknown() {
+			rotated[strings.ToLower(field.FieldName.ValueString())] = true
+		}
+	}
+	if len(rotated) == 0 {
+		return nil
+	}
+
+	templateID, err := strconv.Atoi(plan.SecretTemplateID.ValueString())
+	if err != nil {
+		return fmt.Errorf("invalid Template ID: %w", err)
+	}
+	template, err := client.SecretTemplate(templateID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve secret template: %w", err)
+	}
+
+	generateKeys := plan.SshKeyArgs != nil && plan.SshKeyArgs.GenerateSshKeys.ValueBool()
+	var keys map[string]string
+	for i, field := range secret.Fields {
+		if !rotated[strings.ToLower(field.FieldName)] {
+			continue
+		}
+		var templateField *server.SecretTemplateField
+		for _, tf := range template.Fields {
+			if (field.FieldID > 0 && tf.SecretTemplateFieldID == field.FieldID) ||
+				strings.EqualFold(tf.Name, field.FieldName) ||
+				strings.EqualFold(tf.FieldSlugName, field.FieldName) {
+				templateField = &tf
+				break
+			}
+		}
+
+		part := ""
+		if generateKeys {
+			part = sshKeyPart(field.FieldName)
+		}
+		if templateField != nil && part == "" && templateField.IsPassword {
+			password, err := r.newPassword(ctx, plan, client, template, templateField)
+			if err != nil {
+				return fmt.Errorf("failed to generate password for field %s: %w", field.FieldName, err)
+			}
+			secret.Fields[i].ItemValue = password
+			tflog.Info(ctx, "Rotated generated password", map[string]interface{}{
+				"field": field.FieldName,
+			})
+			continue
+		}
+		if part == "" {
+			continue
+		}
+
+		if keys == nil {
+			if keys, err = generateSSHKey(plan.SshKeyArgs != nil && plan.SshKeyArgs.GeneratePassphrase.ValueBool()); err != nil {
+				return err
+			}
+		}
+		secret.Fields[i].ItemValue = keys[part]
+		tflog.Info(ctx, "Rotated generated SSH key field", map[string]interface{}{
+			"field": field.FieldName,
+		})
+	}
+	return nil
+}
+
+// generateSSHKey returns the parts of a new RSA key pair, keyed by the
+// sshKeyPart of the fields holding them. The private key is in OpenSSH format,
+// encrypted with a new passphrase when withPassphrase is set.
+func generateSSHKey(withPassphrase bool) (map[string]string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rotatedSSHKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate SSH key: %w", err)
+	}
+	publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SSH public key: %w", err)
+	}
+
+	passphrase := ""
+	var block *pem.Block
+	if withPassphrase {
+		if passphrase, err = generateLocalPassword(&passwordRequirement{}); err != nil {
+			return nil, fmt.Errorf("failed to generate SSH key passphrase: %w", err)
+		}
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(key, "")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SSH private key: %w", err)
+	}
+
+	return map[string]string{
+		sshKeyPartPrivate:    string(pem.EncodeToMemory(block)),
+		sshKeyPartPublic:     string(ssh.MarshalAuthorizedKey(publicKey)),
+		sshKeyPartPassphrase: passphrase,
+	}, nil
+}
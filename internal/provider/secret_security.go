@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// SecretSecurityModel is the security attribute of the secret resource
+type SecretSecurityModel struct {
+	RestrictSharingToOwners            types.Bool `tfsdk:"restrict_sharing_to_owners"`
+	HideLauncherPassword               types.Bool `tfsdk:"hide_launcher_password"`
+	AllowOwnersUnrestrictedSSHCommands types.Bool `tfsdk:"allow_owners_unrestricted_ssh_commands"`
+}
+
+// secretSecurityAttrTypes are the attribute types of the security attribute
+var secretSecurityAttrTypes = map[string]attr.Type{
+	"restrict_sharing_to_owners":             types.BoolType,
+	"hide_launcher_password":                 types.BoolType,
+	"allow_owners_unrestricted_ssh_commands": types.BoolType,
+}
+
+// secretSecurityDetails are the security details of a secret as returned by Secret Server
+type secretSecurityDetails struct {
+	RestrictSharingToOwners            bool
+	HideLauncherPassword               bool
+	AllowOwnersUnrestrictedSshCommands bool
+	IsKeystrokeLoggingEnabled          bool
+	HideRecordingIndicator             bool
+}
+
+// secretSecurityAttribute returns the schema of the security attribute
+func secretSecurityAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Computed: true,
+		Description: "The sharing settings of the secret, from its security details. Settings left out keep their current value on the server. " +
+			"Permission inheritance is managed with enableinheritpermissions and enableinheritsecretpolicy.",
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.UseStateForUnknown(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"restrict_sharing_to_owners": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Whether only owners of the secret may share it.",
+			},
+			"hide_launcher_password": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Whether users who may only launch the secret are prevented from viewing its password.",
+			},
+			"allow_owners_unrestricted_ssh_commands": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Whether owners are exempt from the SSH command restrictions of the secret.",
+			},
+		},
+	}
+}
+
+// secretSecurityObject converts security details into the value of the security attribute
+func secretSecurityObject(details *secretSecurityDetails) (types.Object, diag.Diagnostics) {
+	return types.ObjectValue(secretSecurityAttrTypes, map[string]attr.Value{
+		"restrict_sharing_to_owners":             types.BoolValue(details.RestrictSharingToOwners),
+		"hide_launcher_password":                 types.BoolValue(details.HideLauncherPassword),
+		"allow_owners_unrestricted_ssh_commands": types.BoolValue(details.AllowOwnersUnrestrictedSshCommands),
+	})
+}
+
+// applySecretSecurity sends the configured security settings of a secret to the server.
+// Settings that are null or unknown are left unchanged.
+func (c *apiClient) applySecretSecurity(ctx context.Context, secretID int, security types.Object) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if security.IsNull() || security.IsUnknown() {
+		return diags
+	}
+
+	var model SecretSecurityModel
+	diags.Append(security.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return diags
+	}
+
+	update := map[string]interface{}{}
+	set := func(key string, value types.Bool) {
+		if !value.IsNull() && !value.IsUnknown() {
+			update[key] = dirtyValue(value.ValueBool())
+		}
+	}
+	set("restrictSharingToOwners", model.RestrictSharingToOwners)
+	set("hideLauncherPassword", model.HideLauncherPassword)
+	set("allowOwnersUnrestrictedSshCommands", model.AllowOwnersUnrestrictedSSHCommands)
+	if len(update) == 0 {
+		return diags
+	}
+
+	tflog.Debug(ctx, "Updating secret security details", map[string]interface{}{
+		"id":       secretID,
+		"settings": len(update),
+	})
+
+	body := map[string]interface{}{"data": update}
+	if err := c.do(ctx, "PATCH", fmt.Sprintf("secrets/%d/security-details", secretID), body, nil); err != nil {
+		diags.AddError("Secret Security Error", fmt.Sprintf("Failed to update the security details of secret %d: %s", secretID, err))
+	}
+	return diags
+}
+
+// secretSecurity returns the security details of a secret
+func (c *apiClient) secretSecurity(ctx context.Context, secretID int) (*secretSecurityDetails, error) {
+	details := new(secretSecurityDetails)
+	if err := c.do(ctx, "GET", fmt.Sprintf("secrets/%d/security-details", secretID), nil, details); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// SecretSessionRecordingModel is the session_recording attribute of the secret resource
+type SecretSessionRecordingModel struct {
+	KeystrokeLogging       types.Bool `tfsdk:"keystroke_logging"`
+	HideRecordingIndicator types.Bool `tfsdk:"hide_recording_indicator"`
+}
+
+// secretSessionRecordingAttrTypes are the attribute types of the session_recording attribute
+var secretSessionRecordingAttrTypes = map[string]attr.Type{
+	"keystroke_logging":        types.BoolType,
+	"hide_recording_indicator": types.BoolType,
+}
+
+// secretSessionRecordingAttribute returns the schema of the session_recording attribute
+func secretSessionRecordingAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Computed: true,
+		Description: "The session recording options of launched sessions, from the secret's security details. " +
+			"Recording itself is enabled with sessionrecordingenabled. Options left out keep their current value on the server.",
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.UseStateForUnknown(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"keystroke_logging": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Whether the keystrokes of recorded sessions are logged.",
+			},
+			"hide_recording_indicator": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+				Description: "Whether users are not shown that their session is recorded.",
+			},
+		},
+	}
+}
+
+// secretSessionRecordingObject converts security details into the value of the session_recording attribute
+func secretSessionRecordingObject(details *secretSecurityDetails) (types.Object, diag.Diagnostics) {
+	return types.ObjectValue(secretSessionRecordingAttrTypes, map[string]attr.Value{
+		"keystroke_logging":        types.BoolValue(details.IsKeystrokeLoggingEnabled),
+		"hide_recording_indicator": types.BoolValue(details.HideRecordingIndicator),
+	})
+}
+
+// applySecretSessionRecording sends the configured session recording options of a
+// secret to the server. Options that are null or unknown are left unchanged.
+func (c *apiClient) applySecretSessionRecording(ctx context.Context, secretID int, recording types.Object) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if recording.IsNull() || recording.IsUnknown() {
+		return diags
+	}
+
+	var model SecretSessionRecordingModel
+	diags.Append(recording.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return diags
+	}
+
+	update := map[string]interface{}{}
+	set := func(key string, value types.Bool) {
+		if !value.IsNull() && !value.IsUnknown() {
+			update[key] = dirtyValue(value.ValueBool())
+		}
+	}
+	set("isKeystrokeLoggingEnabled", model.KeystrokeLogging)
+	set("hideRecordingIndicator", model.HideRecordingIndicator)
+	if len(update) == 0 {
+		return diags
+	}
+
+	tflog.Debug(ctx, "Updating secret session recording options", map[string]interface{}{
+		"id":       secretID,
+		"settings": len(update),
+	})
+
+	body := map[string]interface{}{"data": update}
+	if err := c.do(ctx, "PATCH", fmt.Sprintf("secrets/%d/security-details", secretID), body, nil); err != nil {
+		diags.AddError("Secret Session Recording Error", fmt.Sprintf("Failed to update the session recording options of secret %d: %s", secretID, err))
+	}
+	return diags
+}
@@ -0,0 +1,275 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxAttachmentSize is the largest attachment uploaded from source_path or
+// content_base64. Secret Server may be configured to accept less.
+const maxAttachmentSize = 10 << 20
+
+// validateSourceFiles checks the source_path and content_base64 arguments of
+// the fields blocks. A field is written from its source file or content alone,
+// and content_sha256 only describes a source file.
+func validateSourceFiles(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var list types.List
+	diags.Append(config.GetAttribute(ctx, path.Root("fields"), &list)...)
+	if diags.HasError() || list.IsNull() || list.IsUnknown() {
+		return
+	}
+	var fields []SecretField
+	diags.Append(list.ElementsAs(ctx, &fields, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	for i, field := range fields {
+		fieldPath := path.Root("fields").AtListIndex(i)
+		if !field.ContentBase64.IsNull() {
+			if !field.SourcePath.IsNull() || !field.ItemValue.IsNull() || !field.ItemValueWO.IsNull() {
+				diags.AddAttributeError(fieldPath.AtName("content_base64"), "Conflicting Field Value",
+					"content_base64 cannot be set with source_path, itemvalue or itemvalue_wo on a field.")
+			}
+			if !field.ContentSHA256.IsNull() {
+				diags.AddAttributeError(fieldPath.AtName("content_sha256"), "Conflicting Checksum",
+					"content_sha256 is computed from content_base64, so it cannot be set with it.")
+			}
+			if !field.ContentBase64.IsUnknown() {
+				if _, err := decodeFieldContent(field.ContentBase64.ValueString()); err != nil {
+					diags.AddAttributeError(fieldPath.AtName("content_base64"), "Invalid Attachment Content", err.Error())
+				}
+			}
+			continue
+		}
+		if field.SourcePath.IsNull() {
+			if !field.ContentSHA256.IsNull() {
+				diags.AddAttributeError(fieldPath.AtName("content_sha256"), "Missing Source Path",
+					"content_sha256 can only be set with source_path.")
+			}
+			continue
+		}
+		if !field.ItemValue.IsNull() || !field.ItemValueWO.IsNull() {
+			diags.AddAttributeError(fieldPath.AtName("source_path"), "Conflicting Field Value",
+				"source_path cannot be set with itemvalue or itemvalue_wo on a field.")
+		}
+	}
+}
+
+// decodeFieldContent decodes the content_base64 of a field, which must not be
+// empty, as Secret Server deletes the attachments of file fields written empty
+func decodeFieldContent(contentBase64 string) ([]byte, error) {
+	content, err := base64.StdEncoding.DecodeString(contentBase64)
+	if err != nil {
+		return nil, fmt.Errorf("content_base64 is not valid base64: %w", err)
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("content_base64 is empty; remove the field to delete its attachment")
+	}
+	if len(content) > maxAttachmentSize {
+		return nil, fmt.Errorf("the attachment is %d bytes, more than the %d bytes that can be uploaded", len(content), maxAttachmentSize)
+	}
+	return content, nil
+}
+
+// hasSourceContent reports whether a field is uploaded from source_path or
+// content_base64 rather than written from itemvalue
+func hasSourceContent(field SecretField) bool {
+	return !field.SourcePath.IsNull() || !field.ContentBase64.IsNull()
+}
+
+// planSourceFiles plans the fields uploaded from a source file or
+// content_base64. Their content is not kept in itemvalue, so the checksum of
+// the content is what plans an update when it changes: for a source file,
+// content_sha256 when configured, or else the checksum of the file as it is now.
+func planSourceFiles(ctx context.Context, req resource.ModifyPlanRequest, plan *SecretResourceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(plan.Fields) == 0 {
+		return diags
+	}
+
+	var configList types.List
+	diags.Append(req.Config.GetAttribute(ctx, path.Root("fields"), &configList)...)
+	if diags.HasError() || configList.IsNull() || configList.IsUnknown() {
+		return diags
+	}
+	var configFields []SecretField
+	diags.Append(configList.ElementsAs(ctx, &configFields, false)...)
+	if diags.HasError() || len(configFields) != len(plan.Fields) {
+		return diags
+	}
+
+	var state SecretResourceState
+	if !req.State.Raw.IsNull() {
+		diags.Append(req.State.Get(ctx, &state)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	for i := range plan.Fields {
+		planned, config := &plan.Fields[i], configFields[i]
+		if !hasSourceContent(config) {
+			continue
+		}
+		planned.ItemValue = types.StringNull()
+
+		if !config.ContentBase64.IsNull() {
+			planned.ContentSHA256 = types.StringUnknown()
+			if !config.ContentBase64.IsUnknown() {
+				if content, err := decodeFieldContent(config.ContentBase64.ValueString()); err == nil {
+					planned.ContentSHA256 = types.StringValue(attachmentChecksum(string(content)))
+				}
+			}
+		} else if config.SourcePath.IsUnknown() {
+			planned.ContentSHA256 = config.ContentSHA256
+			if config.ContentSHA256.IsNull() {
+				planned.ContentSHA256 = types.StringUnknown()
+			}
+			if config.Filename.IsNull() {
+				planned.Filename = types.StringUnknown()
+			}
+		} else {
+			if config.Filename.IsNull() {
+				planned.Filename = types.StringValue(filepath.Base(config.SourcePath.ValueString()))
+			}
+			planned.ContentSHA256 = config.ContentSHA256
+			if config.ContentSHA256.IsNull() {
+				planned.ContentSHA256 = sourceFileChecksum(ctx, config.SourcePath.ValueString())
+			}
+		}
+
+		// History only grows when the content changes
+		planned.HistoryCount = types.Int64Unknown()
+		for _, prior := range state.Fields {
+			if strings.EqualFold(prior.FieldName.ValueString(), planned.FieldName.ValueString()) {
+				if prior.ContentSHA256.Equal(planned.ContentSHA256) {
+					planned.HistoryCount = prior.HistoryCount
+				}
+				break
+			}
+		}
+	}
+	return diags
+}
+
+// sourceFileChecksum returns the checksum of a source file, or unknown when it
+// cannot be read yet, e.g. because another resource creates it on apply
+func sourceFileChecksum(ctx context.Context, sourcePath string) types.String {
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		tflog.Debug(ctx, "Source file is not readable while planning", map[string]interface{}{
+			"source_path": sourcePath,
+			"error":       err.Error(),
+		})
+		return types.StringUnknown()
+	}
+	return types.StringValue(attachmentChecksum(string(content)))
+}
+
+// withSourceFiles returns plan with the content of each field's source file or
+// content_base64 as its value, to be uploaded as the field's attachment. Every
+// write uploads it again, as Secret Server deletes the attachments of file
+// fields written empty.
+func withSourceFiles(ctx context.Context, plan *SecretResourceState) (*SecretResourceState, error) {
+	withContent := *plan
+	withContent.Fields = append([]SecretField(nil), plan.Fields...)
+
+	for i, field := range withContent.Fields {
+		if !field.ContentBase64.IsNull() {
+			content, err := decodeFieldContent(field.ContentBase64.ValueString())
+			if err != nil {
+				return nil, fmt.Errorf("field '%s': %w", field.FieldName.ValueString(), err)
+			}
+			tflog.Debug(ctx, "Uploading field from content_base64", map[string]interface{}{
+				"field": field.FieldName.ValueString(),
+				"size":  len(content),
+			})
+			withContent.Fields[i].ItemValue = types.StringValue(string(content))
+			continue
+		}
+		if field.SourcePath.IsNull() {
+			continue
+		}
+		sourcePath := field.SourcePath.ValueString()
+		content, err := os.ReadFile(sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the source file of field '%s': %w", field.FieldName.ValueString(), err)
+		}
+		if len(content) > maxAttachmentSize {
+			return nil, fmt.Errorf("the source file %s of field '%s' is %d bytes, more than the %d bytes that can be uploaded",
+				sourcePath, field.FieldName.ValueString(), len(content), maxAttachmentSize)
+		}
+		checksum := attachmentChecksum(string(content))
+		if !field.ContentSHA256.IsUnknown() && field.ContentSHA256.ValueString() != checksum {
+			return nil, fmt.Errorf("the source file %s of field '%s' has checksum %q, but content_sha256 is %q; "+
+				"it changed after the plan or content_sha256 does not describe it", sourcePath, field.FieldName.ValueString(), checksum, field.ContentSHA256.ValueString())
+		}
+
+		tflog.Debug(ctx, "Uploading field from source file", map[string]interface{}{
+			"field":       field.FieldName.ValueString(),
+			"source_path": sourcePath,
+			"size":        len(content),
+		})
+		withContent.Fields[i].ItemValue = types.StringValue(string(content))
+		if field.Filename.IsUnknown() || field.Filename.IsNull() || field.Filename.ValueString() == "" {
+			withContent.Fields[i].Filename = types.StringValue(filepath.Base(sourcePath))
+		}
+	}
+	return &withContent, nil
+}
+
+// sourceFilesChanged reports whether an update uploads any source file or
+// content: when its field is new to the secret, or its checksum changed
+func sourceFilesChanged(plan, state *SecretResourceState) bool {
+	for _, field := range plan.Fields {
+		if !hasSourceContent(field) {
+			continue
+		}
+		changed := true
+		for _, stateField := range state.Fields {
+			if strings.EqualFold(stateField.FieldName.ValueString(), field.FieldName.ValueString()) {
+				changed = !stateField.ContentSHA256.Equal(field.ContentSHA256)
+				break
+			}
+		}
+		if changed {
+			return true
+		}
+	}
+	return false
+}
+
+// clearSourceFileValues drops the content of fields uploaded from a source
+// file or content_base64 read back from Secret Server, keeping their
+// source_path, content_base64 and the filename they were uploaded with from
+// src. Their content_sha256 still records what was uploaded.
+func clearSourceFileValues(fields []SecretField, src []SecretField) {
+	for i, field := range fields {
+		for _, srcField := range src {
+			if !strings.EqualFold(srcField.FieldName.ValueString(), field.FieldName.ValueString()) {
+				continue
+			}
+			fields[i].SourcePath = srcField.SourcePath
+			fields[i].ContentBase64 = srcField.ContentBase64
+			if hasSourceContent(srcField) {
+				fields[i].ItemValue = types.StringNull()
+				if !srcField.Filename.IsUnknown() && srcField.Filename.ValueString() != "" {
+					fields[i].Filename = srcField.Filename
+				}
+			}
+			break
+		}
+	}
+}
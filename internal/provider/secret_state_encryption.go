@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/fips140"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// encryptedValuePrefix marks the field values encrypted in state
+const encryptedValuePrefix = "tss:encrypted:v1:"
+
+// stateCipher decrypts the field values earlier versions of the provider
+// encrypted in state with state_encryption_passphrase. An encrypted value has
+// the layout of a file encrypted by EncryptFile, the salt followed by the nonce
+// and ciphertext, base64 encoded after encryptedValuePrefix.
+type stateCipher struct {
+	passphrase string
+	fips       bool
+
+	mu      sync.Mutex
+	ciphers map[string]cipher.AEAD
+}
+
+// newStateCipher returns the stateCipher of passphrase. It uses the Go FIPS
+// 140-3 module when the provider runs in FIPS mode.
+func newStateCipher(passphrase string) (*stateCipher, error) {
+	c := &stateCipher{
+		passphrase: passphrase,
+		fips:       fips140.Enabled(),
+		ciphers:    map[string]cipher.AEAD{},
+	}
+	// A passphrase FIPS mode rejects fails here rather than on the first value
+	if _, err := passphraseCipher(passphrase, make([]byte, saltLength), c.fips); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// cipher returns the cipher of salt, deriving its key on first use
+func (c *stateCipher) cipher(salt []byte) (cipher.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gcm, ok := c.ciphers[string(salt)]; ok {
+		return gcm, nil
+	}
+	gcm, err := passphraseCipher(c.passphrase, salt, c.fips)
+	if err != nil {
+		return nil, err
+	}
+	c.ciphers[string(salt)] = gcm
+	return gcm, nil
+}
+
+// decrypt returns the value an encrypted value was encrypted from
+func (c *stateCipher) decrypt(value string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedValuePrefix))
+	if err != nil || len(data) < saltLength {
+		return "", fmt.Errorf("the encrypted value is malformed")
+	}
+	gcm, err := c.cipher(data[:saltLength])
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := gcm.Open(nil, nil, data[saltLength:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the value, which was likely encrypted with another passphrase: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// isEncryptedValue reports whether value is encrypted
+func isEncryptedValue(value types.String) bool {
+	return !value.IsNull() && !value.IsUnknown() && strings.HasPrefix(value.ValueString(), encryptedValuePrefix)
+}
+
+// keepEncryptedValues keeps the values src, the plan or prior state, holds
+// encrypted by an earlier version of the provider while they still decrypt to
+// the value read back from Secret Server, so refresh plans no change and the
+// value does not appear in state. New values are never encrypted; a changed
+// value is stored as read, like any other value.
+func (r *TssSecretResource) keepEncryptedValues(ctx context.Context, fields []SecretField, src []SecretField) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, field := range fields {
+		if field.ItemValue.IsNull() || field.ItemValue.IsUnknown() {
+			continue
+		}
+		for _, srcField := range src {
+			if !strings.EqualFold(srcField.FieldName.ValueString(), field.FieldName.ValueString()) {
+				continue
+			}
+			if !isEncryptedValue(srcField.ItemValue) {
+				break
+			}
+			if r.stateCipher == nil {
+				diags.AddWarning("Field Value Not Encrypted",
+					fmt.Sprintf("The value of field '%s' was encrypted in state, but state_encryption_passphrase is not set, so it is now stored unencrypted. "+
+						"Set store_value_in_state = false to keep it out of state.", field.FieldName.ValueString()))
+				break
+			}
+
+			prior, err := r.stateCipher.decrypt(srcField.ItemValue.ValueString())
+			if err != nil {
+				diags.AddError("State Decryption Error", fmt.Sprintf("Failed to decrypt the value of field '%s': %s", field.FieldName.ValueString(), err))
+				return diags
+			}
+			if prior == field.ItemValue.ValueString() {
+				tflog.Trace(ctx, "Kept encrypted field value in state", map[string]interface{}{
+					"field": field.FieldName.ValueString(),
+				})
+				fields[i].ItemValue = srcField.ItemValue
+			}
+			break
+		}
+	}
+	return diags
+}
+
+// decryptedFields returns fields with the values encrypted in state decrypted,
+// as they are written to Secret Server
+func (r *TssSecretResource) decryptedFields(fields []SecretField) ([]SecretField, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	decrypted := append([]SecretField(nil), fields...)
+	for i, field := range decrypted {
+		if !isEncryptedValue(field.ItemValue) {
+			continue
+		}
+		if r.stateCipher == nil {
+			diags.AddError("State Decryption Error",
+				fmt.Sprintf("The value of field '%s' is encrypted in state. Set state_encryption_passphrase to the passphrase it was encrypted with.",
+					field.FieldName.ValueString()))
+			return nil, diags
+		}
+		value, err := r.stateCipher.decrypt(field.ItemValue.ValueString())
+		if err != nil {
+			diags.AddError("State Decryption Error", fmt.Sprintf("Failed to decrypt the value of field '%s': %s", field.FieldName.ValueString(), err))
+			return nil, diags
+		}
+		decrypted[i].ItemValue = types.StringValue(value)
+	}
+	return decrypted, diags
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// encryptedStateValue returns value encrypted as earlier versions of the
+// provider stored it in state
+func encryptedStateValue(t *testing.T, passphrase, value string) string {
+	t.Helper()
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := passphraseCipher(passphrase, salt, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encryptedValuePrefix + base64.StdEncoding.EncodeToString(gcm.Seal(salt, nil, []byte(value), nil))
+}
+
+func TestStateCipherDecrypts(t *testing.T) {
+	encrypted := encryptedStateValue(t, "correct horse battery staple", "hunter2")
+
+	c, err := newStateCipher("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := c.decrypt(encrypted); err != nil || value != "hunter2" {
+		t.Errorf("decrypted %q, %v", value, err)
+	}
+
+	other, err := newStateCipher("another passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.decrypt(encrypted); err == nil {
+		t.Error("a value was decrypted with another passphrase")
+	}
+}
+
+func TestKeepEncryptedValues(t *testing.T) {
+	c, err := newStateCipher("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &TssSecretResource{stateCipher: c}
+
+	encrypted := types.StringValue(encryptedStateValue(t, "correct horse battery staple", "generated"))
+	src := []SecretField{
+		{FieldName: types.StringValue("password"), ItemValue: encrypted},
+		{FieldName: types.StringValue("pin"), ItemValue: types.StringValue(encryptedStateValue(t, "correct horse battery staple", "1234"))},
+		{FieldName: types.StringValue("username"), ItemValue: types.StringUnknown()},
+	}
+	fields := []SecretField{
+		{FieldName: types.StringValue("Password"), ItemValue: types.StringValue("generated")},
+		{FieldName: types.StringValue("PIN"), ItemValue: types.StringValue("rotated")},
+		{FieldName: types.StringValue("Username"), ItemValue: types.StringValue("admin")},
+	}
+	if diags := r.keepEncryptedValues(context.Background(), fields, src); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// An unchanged value keeps its ciphertext, so refresh plans no change
+	if !fields[0].ItemValue.Equal(encrypted) {
+		t.Errorf("the unchanged password is stored as %s", fields[0].ItemValue)
+	}
+	// Changed and new values are stored as read, never encrypted anew
+	if fields[1].ItemValue.ValueString() != "rotated" || fields[2].ItemValue.ValueString() != "admin" {
+		t.Errorf("values = %s, %s", fields[1].ItemValue, fields[2].ItemValue)
+	}
+
+	decrypted, diags := r.decryptedFields(fields)
+	if diags.HasError() || decrypted[0].ItemValue.ValueString() != "generated" {
+		t.Errorf("decrypted %s, %v", decrypted[0].ItemValue, diags)
+	}
+}
+
+func TestKeepEncryptedValuesWithoutPassphrase(t *testing.T) {
+	src := []SecretField{{FieldName: types.StringValue("password"), ItemValue: types.StringValue(encryptedStateValue(t, "passphrase", "generated"))}}
+	fields := []SecretField{{FieldName: types.StringValue("password"), ItemValue: types.StringValue("generated")}}
+
+	diags := (&TssSecretResource{}).keepEncryptedValues(context.Background(), fields, src)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("diagnostics = %v, want a warning", diags)
+	}
+	if _, diags := (&TssSecretResource{}).decryptedFields(src); !diags.HasError() {
+		t.Error("an encrypted value was written back without the passphrase")
+	}
+}
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLastSuccessfulChange(t *testing.T) {
+	changed, err := (&secretStatus{LastPasswordChangeAttempt: "2024-03-01T10:00:00"}).lastSuccessfulChange()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC); !changed.Equal(want) {
+		t.Errorf("last change = %s, want %s", changed, want)
+	}
+
+	if _, err := (&secretStatus{}).lastSuccessfulChange(); !errors.Is(err, errNeverChanged) {
+		t.Errorf("a secret that was never rotated gave %v", err)
+	}
+
+	// A failed attempt does not count as a change
+	failed := &secretStatus{LastPasswordChangeAttempt: "2024-03-01T10:00:00", OutOfSync: true, OutOfSyncReason: "access denied"}
+	if _, err := failed.lastSuccessfulChange(); err == nil || errors.Is(err, errNeverChanged) {
+		t.Errorf("a failed change attempt gave %v", err)
+	}
+}
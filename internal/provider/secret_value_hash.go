@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// valueHashSaltSize is the size of the random salt of a value_hash
+const valueHashSaltSize = 16
+
+// storesValueInState reports whether a field keeps its value in state, which
+// it does unless store_value_in_state is false
+func storesValueInState(field SecretField) bool {
+	return field.StoreValueInState.IsNull() || field.StoreValueInState.IsUnknown() || field.StoreValueInState.ValueBool()
+}
+
+// validateValueHashes checks the store_value_in_state arguments of the fields
+// blocks. A configured itemvalue is in state whatever the provider does, so a
+// field kept out of state gets its value from itemvalue_wo or generates it.
+func validateValueHashes(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var list types.List
+	diags.Append(config.GetAttribute(ctx, path.Root("fields"), &list)...)
+	if diags.HasError() || list.IsNull() || list.IsUnknown() {
+		return
+	}
+	var fields []SecretField
+	diags.Append(list.ElementsAs(ctx, &fields, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	for i, field := range fields {
+		if storesValueInState(field) || field.ItemValue.IsNull() {
+			continue
+		}
+		diags.AddAttributeError(path.Root("fields").AtListIndex(i).AtName("store_value_in_state"), "Conflicting Field Value",
+			"itemvalue is stored in state as configured, so it cannot be set with store_value_in_state = false. "+
+				"Set the value with itemvalue_wo, or leave it unset to keep a generated password.")
+	}
+}
+
+// valueHash returns the value_hash of value: a new random salt and the SHA-256
+// of the salt followed by the value, both hex encoded and separated by a colon
+func valueHash(value string) (string, error) {
+	salt := make([]byte, valueHashSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return saltedHash(salt, value), nil
+}
+
+// saltedHash returns the value_hash of value with salt
+func saltedHash(salt []byte, value string) string {
+	sum := sha256.Sum256(append(append([]byte(nil), salt...), value...))
+	return hex.EncodeToString(salt) + ":" + hex.EncodeToString(sum[:])
+}
+
+// valueHashMatches reports whether hash is the value_hash of value
+func valueHashMatches(hash, value string) bool {
+	encodedSalt, _, ok := strings.Cut(hash, ":")
+	if !ok {
+		return false
+	}
+	salt, err := hex.DecodeString(encodedSalt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(saltedHash(salt, value)), []byte(hash)) == 1
+}
+
+// planValueHashes plans the value_hash of each field: null for fields storing
+// their value in state, the prior hash while the value it was taken from is
+// kept, and unknown when the value is written anew
+func planValueHashes(ctx context.Context, req resource.ModifyPlanRequest, plan *SecretResourceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var state SecretResourceState
+	if !req.State.Raw.IsNull() {
+		diags.Append(req.State.Get(ctx, &state)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	for i := range plan.Fields {
+		planned := &plan.Fields[i]
+		if storesValueInState(*planned) {
+			planned.ValueHash = types.StringNull()
+			continue
+		}
+		planned.ValueHash = types.StringUnknown()
+		if planned.ItemValue.IsUnknown() {
+			continue
+		}
+		for _, prior := range state.Fields {
+			if strings.EqualFold(prior.FieldName.ValueString(), planned.FieldName.ValueString()) {
+				if !prior.ValueHash.IsNull() && prior.ItemValueWOVersion.Equal(planned.ItemValueWOVersion) {
+					planned.ValueHash = prior.ValueHash
+				}
+				break
+			}
+		}
+	}
+	return diags
+}
+
+// withHashedValues returns plan with the current value of the fields kept out
+// of state, which an update writes back as they are. Write-only fields already
+// hold their value, and rotated ones are generated anew.
+func (r *TssSecretResource) withHashedValues(ctx context.Context, client sdkClient, plan *SecretResourceState, state *SecretResourceState) (*SecretResourceState, error) {
+	var current map[string]string
+	withValues := *plan
+	withValues.Fields = append([]SecretField(nil), plan.Fields...)
+
+	for i, field := range withValues.Fields {
+		if storesValueInState(field) || !field.ItemValue.IsNull() {
+			continue
+		}
+		if current == nil {
+			var err error
+			if current, err = currentFieldValues(client, state.ID.ValueString()); err != nil {
+				return nil, err
+			}
+		}
+		tflog.Trace(ctx, "Keeping the current value of field kept out of state", map[string]interface{}{
+			"field": field.FieldName.ValueString(),
+		})
+		withValues.Fields[i].ItemValue = types.StringValue(current[strings.ToLower(field.FieldName.ValueString())])
+	}
+	return &withValues, nil
+}
+
+// isHashedField reports whether the field of fields named name keeps its value
+// out of state
+func isHashedField(fields []SecretField, name string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(field.FieldName.ValueString(), name) {
+			return !storesValueInState(field)
+		}
+	}
+	return false
+}
+
+// hashFieldValues replaces the values of fields read back from Secret Server
+// with their value_hash where src, the configuration or prior state, keeps them
+// out of state. A prior hash that still matches the value is kept, so refresh
+// plans no change. One that does not means the value was changed outside of
+// Terraform: with detectDrift set the prior hash is kept, and a field written
+// from itemvalue_wo loses its itemvalue_wo_version, so the next plan writes the
+// configured value again. Other fields have no configured value to restore and
+// are reported on every refresh until the value matches again.
+func hashFieldValues(ctx context.Context, secretID string, fields []SecretField, src []SecretField, detectDrift bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, field := range fields {
+		for _, srcField := range src {
+			if !strings.EqualFold(srcField.FieldName.ValueString(), field.FieldName.ValueString()) {
+				continue
+			}
+			fields[i].StoreValueInState = srcField.StoreValueInState
+			if storesValueInState(srcField) {
+				fields[i].ValueHash = types.StringNull()
+				break
+			}
+
+			value := field.ItemValue.ValueString()
+			fields[i].ItemValue = types.StringNull()
+			prior := srcField.ValueHash
+			if !prior.IsNull() && !prior.IsUnknown() && valueHashMatches(prior.ValueString(), value) {
+				fields[i].ValueHash = prior
+				break
+			}
+
+			if detectDrift && !prior.IsNull() && !prior.IsUnknown() {
+				tflog.Warn(ctx, "Field value no longer matches its hash", map[string]interface{}{
+					"secret_id": secretID,
+					"field":     field.FieldName.ValueString(),
+				})
+				fields[i].ValueHash = prior
+				if !fields[i].ItemValueWOVersion.IsNull() {
+					fields[i].ItemValueWOVersion = types.StringNull()
+					diags.AddWarning("Field Value Drift",
+						fmt.Sprintf("The value of field '%s' of secret %s no longer matches the value_hash in state, so it was changed outside of Terraform. "+
+							"The next apply writes itemvalue_wo again.", field.FieldName.ValueString(), secretID))
+				} else {
+					diags.AddWarning("Field Value Drift",
+						fmt.Sprintf("The value of field '%s' of secret %s no longer matches the value_hash in state, so it was changed outside of Terraform. "+
+							"Restore the value, or replace the secret to generate a new one.", field.FieldName.ValueString(), secretID))
+				}
+				break
+			}
+
+			hash, err := valueHash(value)
+			if err != nil {
+				diags.AddError("Field Value Hash Error", fmt.Sprintf("Failed to hash the value of field '%s': %s", field.FieldName.ValueString(), err))
+				return diags
+			}
+			fields[i].ValueHash = types.StringValue(hash)
+			break
+		}
+	}
+	return diags
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValueHash(t *testing.T) {
+	hash, err := valueHash("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valueHashMatches(hash, "hunter2") {
+		t.Error("a hash does not match its value")
+	}
+	if valueHashMatches(hash, "hunter3") {
+		t.Error("a hash matches another value")
+	}
+	if other, _ := valueHash("hunter2"); other == hash {
+		t.Error("two hashes of the same value share their salt")
+	}
+	if valueHashMatches("not a hash", "hunter2") {
+		t.Error("a malformed hash matches")
+	}
+}
+
+func TestHashFieldValuesKeepsPriorHashOnDrift(t *testing.T) {
+	prior, _ := valueHash("written by terraform")
+	src := []SecretField{
+		{FieldName: types.StringValue("password"), StoreValueInState: types.BoolValue(false), ValueHash: types.StringValue(prior), ItemValueWOVersion: types.StringValue("1")},
+		{FieldName: types.StringValue("pin"), StoreValueInState: types.BoolValue(false), ValueHash: types.StringValue(prior), ItemValueWOVersion: types.StringNull()},
+		{FieldName: types.StringValue("notes"), StoreValueInState: types.BoolValue(false), ValueHash: types.StringValue(prior), ItemValueWOVersion: types.StringNull()},
+	}
+	fields := []SecretField{
+		{FieldName: types.StringValue("Password"), ItemValue: types.StringValue("changed by hand"), ItemValueWOVersion: types.StringValue("1")},
+		{FieldName: types.StringValue("PIN"), ItemValue: types.StringValue("changed by hand"), ItemValueWOVersion: types.StringNull()},
+		{FieldName: types.StringValue("Notes"), ItemValue: types.StringValue("written by terraform"), ItemValueWOVersion: types.StringNull()},
+	}
+
+	diags := hashFieldValues(context.Background(), "7", fields, src, true)
+	if diags.HasError() || diags.WarningsCount() != 2 {
+		t.Fatalf("diagnostics = %v, want a warning for each changed field", diags)
+	}
+	for _, field := range fields {
+		if !field.ItemValue.IsNull() {
+			t.Errorf("the value of %s was kept in state", field.FieldName.ValueString())
+		}
+		if field.ValueHash.ValueString() != prior {
+			t.Errorf("the hash of %s was replaced", field.FieldName.ValueString())
+		}
+	}
+	// Clearing the version plans writing itemvalue_wo again
+	if !fields[0].ItemValueWOVersion.IsNull() {
+		t.Errorf("itemvalue_wo_version of a changed field = %s, want null", fields[0].ItemValueWOVersion)
+	}
+}
+
+func TestHashFieldValuesHashesNewValues(t *testing.T) {
+	src := []SecretField{{FieldName: types.StringValue("password"), StoreValueInState: types.BoolValue(false), ValueHash: types.StringUnknown()}}
+	fields := []SecretField{{FieldName: types.StringValue("password"), ItemValue: types.StringValue("generated")}}
+
+	if diags := hashFieldValues(context.Background(), "7", fields, src, false); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !fields[0].ItemValue.IsNull() || !valueHashMatches(fields[0].ValueHash.ValueString(), "generated") {
+		t.Errorf("field = %s / %s, want a null value and its hash", fields[0].ItemValue, fields[0].ValueHash)
+	}
+}
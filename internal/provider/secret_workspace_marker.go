@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// workspaceMarkerField is the metadata field marking the workspace that
+// manages a secret
+const workspaceMarkerField = "managed-by"
+
+// workspaceMarker returns the marker value of workspace
+func workspaceMarker(workspace string) string {
+	return "terraform/" + workspace
+}
+
+// secretMetadataItem is a metadata value of a secret as returned by Secret Server
+type secretMetadataItem struct {
+	MetadataItemDataID int
+	FieldName          string
+	ValueString        string
+}
+
+// secretMetadata returns the metadata of a secret
+func (c *apiClient) secretMetadata(ctx context.Context, secretID int) ([]secretMetadataItem, error) {
+	var metadata struct {
+		Records []secretMetadataItem
+	}
+	if err := c.do(ctx, "GET", fmt.Sprintf("metadata/Secret/%d", secretID), nil, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata.Records, nil
+}
+
+// secretWorkspaceMarker returns the workspace marker of a secret and its
+// metadata item ID, or "" and 0 when it has none
+func (c *apiClient) secretWorkspaceMarker(ctx context.Context, secretID int) (string, int, error) {
+	metadata, err := c.secretMetadata(ctx, secretID)
+	if err != nil {
+		return "", 0, err
+	}
+	for _, item := range metadata {
+		if strings.EqualFold(item.FieldName, workspaceMarkerField) {
+			return item.ValueString, item.MetadataItemDataID, nil
+		}
+	}
+	return "", 0, nil
+}
+
+// setSecretWorkspaceMarker marks a secret as managed by workspace, replacing
+// the metadata item itemID when it is not 0
+func (c *apiClient) setSecretWorkspaceMarker(ctx context.Context, secretID, itemID int, workspace string) error {
+	if itemID != 0 {
+		return c.do(ctx, "PUT", fmt.Sprintf("metadata/Secret/%d", secretID), map[string]interface{}{
+			"data": map[string]interface{}{
+				"metadataItemDataId": itemID,
+				"valueString":        workspaceMarker(workspace),
+			},
+		}, nil)
+	}
+	return c.do(ctx, "POST", fmt.Sprintf("metadata/Secret/%d", secretID), map[string]interface{}{
+		"data": map[string]interface{}{
+			"fieldName":     workspaceMarkerField,
+			"fieldDataType": "String",
+			"valueString":   workspaceMarker(workspace),
+		},
+	}, nil)
+}
+
+// markWorkspace marks a newly created secret as managed by the provider's
+// workspace. The secret exists either way, so failing to mark it is a warning.
+func (r *TssSecretResource) markWorkspace(ctx context.Context, secretID int) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.workspace == "" || r.api == nil {
+		return diags
+	}
+	if err := r.api.setSecretWorkspaceMarker(ctx, secretID, 0, r.workspace); err != nil {
+		tflog.Warn(ctx, "Failed to mark secret with workspace", map[string]interface{}{
+			"id":    secretID,
+			"error": err.Error(),
+		})
+		diags.AddWarning("Workspace Marker Not Written",
+			fmt.Sprintf("Secret %d was created, but marking it as managed by workspace %q failed, so other workspaces are not stopped from updating it: %s",
+				secretID, r.workspace, err))
+	}
+	return diags
+}
+
+// claimWorkspace checks, before a secret is updated, that no other workspace
+// manages it, and marks it as managed by the provider's workspace. A secret
+// marked by another workspace is only taken over when allowTakeover is set.
+func (r *TssSecretResource) claimWorkspace(ctx context.Context, secretID int, allowTakeover bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.workspace == "" || r.api == nil {
+		return diags
+	}
+
+	marker, itemID, err := r.api.secretWorkspaceMarker(ctx, secretID)
+	if err != nil {
+		diags.AddError("Workspace Marker Error",
+			fmt.Sprintf("Failed to read the workspace marker of secret %d: %s", secretID, err))
+		return diags
+	}
+	if marker == workspaceMarker(r.workspace) {
+		return diags
+	}
+	if marker != "" && !allowTakeover {
+		diags.AddError("Secret Managed By Another Workspace",
+			fmt.Sprintf("Secret %d is marked %q, so another workspace manages it and this workspace (%q) will not update it. "+
+				"Remove it from one of the workspaces, or set allow_takeover to make this workspace manage it.",
+				secretID, marker, r.workspace))
+		return diags
+	}
+
+	tflog.Info(ctx, "Marking secret with workspace", map[string]interface{}{
+		"id":              secretID,
+		"previous_marker": marker,
+		"workspace":       r.workspace,
+	})
+	if err := r.api.setSecretWorkspaceMarker(ctx, secretID, itemID, r.workspace); err != nil {
+		diags.AddError("Workspace Marker Error",
+			fmt.Sprintf("Failed to mark secret %d as managed by workspace %q: %s", secretID, r.workspace, err))
+	}
+	return diags
+}
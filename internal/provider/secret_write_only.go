@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// validateWriteOnlyFields checks the itemvalue_wo arguments of the fields
+// blocks. itemvalue_wo_version is required with itemvalue_wo, as it is what
+// marks the field's value as kept out of state.
+func validateWriteOnlyFields(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) {
+	var list types.List
+	diags.Append(config.GetAttribute(ctx, path.Root("fields"), &list)...)
+	if diags.HasError() || list.IsNull() || list.IsUnknown() {
+		return
+	}
+	var fields []SecretField
+	diags.Append(list.ElementsAs(ctx, &fields, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	for i, field := range fields {
+		if field.ItemValueWO.IsNull() {
+			continue
+		}
+		fieldPath := path.Root("fields").AtListIndex(i)
+		if !field.ItemValue.IsNull() {
+			diags.AddAttributeError(fieldPath.AtName("itemvalue_wo"), "Conflicting Field Value",
+				"itemvalue and itemvalue_wo cannot both be set on a field.")
+		}
+		if field.ItemValueWOVersion.IsNull() {
+			diags.AddAttributeError(fieldPath.AtName("itemvalue_wo_version"), "Missing Write-Only Version",
+				"itemvalue_wo_version must be set with itemvalue_wo; change it to write the current itemvalue_wo again.")
+		}
+	}
+}
+
+// configuredWriteOnlyFields returns the fields blocks of the configuration,
+// which alone hold the itemvalue_wo values
+func configuredWriteOnlyFields(ctx context.Context, config tfsdk.Config) ([]SecretField, diag.Diagnostics) {
+	var fields []SecretField
+	diags := config.GetAttribute(ctx, path.Root("fields"), &fields)
+	return fields, diags
+}
+
+// writeOnlyChanged reports whether a field's itemvalue_wo is written by an
+// update: when it is new to the secret, or its itemvalue_wo_version changed
+func writeOnlyChanged(field SecretField, state *SecretResourceState) bool {
+	if state == nil {
+		return true
+	}
+	for _, stateField := range state.Fields {
+		if strings.EqualFold(stateField.FieldName.ValueString(), field.FieldName.ValueString()) {
+			return !stateField.ItemValueWOVersion.Equal(field.ItemValueWOVersion)
+		}
+	}
+	return true
+}
+
+// withWriteOnlyValues returns plan with the itemvalue_wo of the configured
+// fields as their values. On update, state is the prior state, and fields whose
+// itemvalue_wo_version did not change keep the value Secret Server holds, as
+// state has none to preserve.
+func (r *TssSecretResource) withWriteOnlyValues(ctx context.Context, client sdkClient, plan *SecretResourceState, configFields []SecretField, state *SecretResourceState) (*SecretResourceState, error) {
+	var current map[string]string
+	withValues := *plan
+	withValues.Fields = append([]SecretField(nil), plan.Fields...)
+
+	for i, field := range withValues.Fields {
+		if i >= len(configFields) || configFields[i].ItemValueWO.IsNull() {
+			continue
+		}
+		fieldName := field.FieldName.ValueString()
+
+		if writeOnlyChanged(field, state) {
+			tflog.Debug(ctx, "Writing write-only field value", map[string]interface{}{
+				"field": fieldName,
+			})
+			withValues.Fields[i].ItemValue = configFields[i].ItemValueWO
+			continue
+		}
+
+		if current == nil {
+			var err error
+			if current, err = currentFieldValues(client, state.ID.ValueString()); err != nil {
+				return nil, err
+			}
+		}
+		withValues.Fields[i].ItemValue = types.StringValue(current[strings.ToLower(fieldName)])
+	}
+	return &withValues, nil
+}
+
+// currentFieldValues returns the values of a secret's fields as Secret Server
+// holds them, keyed by lower-cased field name and slug
+func currentFieldValues(client sdkClient, secretID string) (map[string]string, error) {
+	id, err := strconv.Atoi(secretID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret ID: %w", err)
+	}
+	secret, err := client.Secret(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the current values of secret %d: %w", id, err)
+	}
+	current := map[string]string{}
+	for _, f := range secret.Fields {
+		current[strings.ToLower(f.FieldName)] = f.ItemValue
+		current[strings.ToLower(f.Slug)] = f.ItemValue
+	}
+	return current, nil
+}
+
+// writeOnlyVersionChanged reports whether an update writes any itemvalue_wo
+func writeOnlyVersionChanged(plan, state *SecretResourceState) bool {
+	for _, field := range plan.Fields {
+		if !field.ItemValueWOVersion.IsNull() && writeOnlyChanged(field, state) {
+			return true
+		}
+	}
+	return false
+}
+
+// clearWriteOnlyValues drops the values of write-only fields read back from
+// Secret Server, keeping their itemvalue_wo_version from src
+func clearWriteOnlyValues(fields []SecretField, src []SecretField) {
+	for i, field := range fields {
+		for _, srcField := range src {
+			if !strings.EqualFold(srcField.FieldName.ValueString(), field.FieldName.ValueString()) {
+				continue
+			}
+			fields[i].ItemValueWOVersion = srcField.ItemValueWOVersion
+			if !srcField.ItemValueWOVersion.IsNull() {
+				fields[i].ItemValue = types.StringNull()
+			}
+			break
+		}
+	}
+}
+
+// isWriteOnlyField reports whether the field named name takes its value from
+// itemvalue_wo
+func isWriteOnlyField(fields []SecretField, name string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(field.FieldName.ValueString(), name) {
+			return !field.ItemValueWOVersion.IsNull()
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/DelineaXPM/tss-sdk-go/v2/server"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// templateDefaultFields returns the required fields of template that fields does
+// not include, with empty values. Empty password fields are generated on create
+// like declared ones; file fields are left out, as they have no default.
+func templateDefaultFields(ctx context.Context, template *server.SecretTemplate, fields []server.SecretField) []server.SecretField {
+	declared := map[int]bool{}
+	for _, f := range fields {
+		declared[f.FieldID] = true
+	}
+
+	var defaults []server.SecretField
+	for _, tf := range template.Fields {
+		if !tf.IsRequired || declared[tf.SecretTemplateFieldID] {
+			continue
+		}
+		if tf.IsFile {
+			tflog.Warn(ctx, "Required file field has no default, declare it in a fields block", map[string]interface{}{
+				"field": tf.Name,
+			})
+			continue
+		}
+		tflog.Debug(ctx, "Adding required template field", map[string]interface{}{
+			"field": tf.Name,
+		})
+		defaults = append(defaults, server.SecretField{
+			FieldDescription: tf.Description,
+			FieldID:          tf.SecretTemplateFieldID,
+			FieldName:        tf.Name,
+			IsNotes:          tf.IsNotes,
+			IsPassword:       tf.IsPassword,
+			Slug:             tf.FieldSlugName,
+		})
+	}
+	return defaults
+}
+
+// keepUndeclaredValues sets the fields of secret that no fields block declares
+// to their value in state, so a template default never overwrites the value
+// the secret was created with or has been given since
+func keepUndeclaredValues(secret *server.Secret, planFields, stateFields []SecretField) {
+	for i, field := range secret.Fields {
+		if hasSecretField(planFields, field.FieldName) || hasSecretField(planFields, field.Slug) {
+			continue
+		}
+		for _, stateField := range stateFields {
+			if strings.EqualFold(stateField.FieldName.ValueString(), field.FieldName) ||
+				strings.EqualFold(stateField.FieldName.ValueString(), field.Slug) {
+				secret.Fields[i].ItemValue = stateField.ItemValue.ValueString()
+				break
+			}
+		}
+	}
+}
+
+// hasSecretField reports whether fields includes the field named name
+func hasSecretField(fields []SecretField, name string) bool {
+	for _, f := range fields {
+		if strings.EqualFold(f.FieldName.ValueString(), name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// tlsSettings are the provider settings that shape the TLS configuration
+type tlsSettings struct {
+	CACertFile string
+	CACertPEM  string
+	SkipVerify bool
+	// MinVersion is "1.2" or "1.3"; empty keeps the Go default
+	MinVersion string
+	// CipherSuites are the names of the TLS 1.2 cipher suites to offer; empty
+	// keeps the Go defaults
+	CipherSuites []string
+}
+
+// tlsVersions maps the tls_min_version values to TLS versions
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// isSet reports whether any setting differs from the Go defaults
+func (s tlsSettings) isSet() bool {
+	return s.CACertFile != "" || s.CACertPEM != "" || s.SkipVerify || s.MinVersion != "" || len(s.CipherSuites) > 0
+}
+
+// config returns the TLS configuration for connections to Secret Server. Custom CA
+// certificates are trusted in addition to the system trust store.
+func (s tlsSettings) config() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: s.SkipVerify,
+	}
+
+	if s.MinVersion != "" {
+		version, ok := tlsVersions[s.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("tls_min_version %q is not one of 1.2 or 1.3", s.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	// Only suites Go considers secure may be chosen. TLS 1.3 suites are not
+	// configurable, so the list only restricts TLS 1.2 connections.
+	for _, name := range s.CipherSuites {
+		id, ok := secureCipherSuite(name)
+		if !ok {
+			return nil, fmt.Errorf("tls_cipher_suites: %q is not a supported cipher suite", name)
+		}
+		config.CipherSuites = append(config.CipherSuites, id)
+	}
+
+	if s.CACertFile != "" || s.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if s.CACertFile != "" {
+			data, err := os.ReadFile(s.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("%s does not contain any PEM encoded certificates", s.CACertFile)
+			}
+		}
+		if s.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(s.CACertPEM)) {
+			return nil, fmt.Errorf("ca_cert_pem does not contain any PEM encoded certificates")
+		}
+
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// secureCipherSuite returns the ID of the secure TLS 1.2 cipher suite with the given name
+func secureCipherSuite(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name != name {
+			continue
+		}
+		for _, version := range suite.SupportedVersions {
+			if version == tls.VersionTLS12 {
+				return suite.ID, true
+			}
+		}
+	}
+	return 0, false
+}
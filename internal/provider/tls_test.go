@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTLSSettingsApplyToTheirTransportOnly(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	router := &transportRouter{byKey: map[string]*providerTransport{}, byHost: map[string]*providerTransport{}}
+	for name, settings := range map[string]transportSettings{
+		"ca_cert":         {TLS: tlsSettings{CACertPEM: caCert}},
+		"tls_skip_verify": {TLS: tlsSettings{SkipVerify: true}},
+	} {
+		transport, err := router.transport(settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		res.Body.Close()
+	}
+
+	// Neither the base transport nor a client built from it, such as the OIDC
+	// token request's, trusts the server
+	if res, err := (&http.Client{Transport: router}).Get(server.URL); err == nil {
+		res.Body.Close()
+		t.Error("a request to an unrouted host used another configuration's TLS settings")
+	}
+	api := &apiClient{}
+	transport, err := router.transport(transportSettings{TLS: tlsSettings{SkipVerify: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	api.useTransport(transport)
+	api.useOIDC("", "")
+	if res, err := api.oidc.httpClient.Get(server.URL); err == nil {
+		res.Body.Close()
+		t.Error("the OIDC token request used the configuration's TLS settings")
+	}
+}
+
+func TestTLSSettingsConflictOnOneHost(t *testing.T) {
+	router := &transportRouter{byKey: map[string]*providerTransport{}, byHost: map[string]*providerTransport{}}
+	verify, err := router.transport(transportSettings{TLS: tlsSettings{MinVersion: "1.3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipVerify, err := router.transport(transportSettings{TLS: tlsSettings{MinVersion: "1.3", SkipVerify: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := router.route("https://tss.example.test", verify); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.route("https://tss.example.test", skipVerify); err == nil {
+		t.Error("an alias of the same server with tls_skip_verify was not rejected")
+	}
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the instrumentation scope of the provider's spans
+const tracerName = "github.com/just_shrubs/terraform-provider-tss"
+
+// tracingServiceName is the service.name of the provider's spans
+const tracingServiceName = "terraform-provider-tss"
+
+// spanIDSegment matches the numeric segments of an API path, which are replaced
+// in span names to keep their number small
+var spanIDSegment = regexp.MustCompile(`/\d+(/|$)`)
+
+// tracerProviders holds the tracer providers of this process by OTLP endpoint, so
+// provider configurations exporting to the same collector share one batch of
+// spans, and ShutdownTracing can flush them all
+var tracerProviders = struct {
+	sync.Mutex
+	providers map[string]*sdktrace.TracerProvider
+}{providers: map[string]*sdktrace.TracerProvider{}}
+
+// noopTracer is used when otel_endpoint is not set
+var noopTracer = noop.NewTracerProvider().Tracer(tracerName)
+
+// newTracer returns a tracer exporting spans to the OTLP/HTTP endpoint, e.g.
+// http://localhost:4318. Spans are exported in batches each second, and the
+// last batch when ShutdownTracing is called on exit.
+func newTracer(ctx context.Context, endpoint string) (trace.Tracer, error) {
+	endpoint = strings.TrimRight(endpoint, "/")
+
+	tracerProviders.Lock()
+	defer tracerProviders.Unlock()
+	if provider, ok := tracerProviders.providers[endpoint]; ok {
+		return provider.Tracer(tracerName), nil
+	}
+
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %w", endpoint, err)
+	}
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(endpoint+"/v1/traces"),
+		otlptracehttp.WithTimeout(10*time.Second),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(time.Second)),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", tracingServiceName))),
+	)
+	tracerProviders.providers[endpoint] = provider
+	return provider.Tracer(tracerName), nil
+}
+
+// ShutdownTracing exports the spans not exported yet and stops every tracer
+// provider. It is called when the provider exits.
+func ShutdownTracing(ctx context.Context) error {
+	tracerProviders.Lock()
+	defer tracerProviders.Unlock()
+
+	var errs []error
+	for endpoint, provider := range tracerProviders.providers {
+		if err := provider.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to export the last spans to %s: %w", endpoint, err))
+		}
+		delete(tracerProviders.providers, endpoint)
+	}
+	return errors.Join(errs...)
+}
+
+// startSpan starts the span of a Secret Server call. method and path describe
+// the call; the secret ID is taken from the path when it acts on one secret.
+func (c *apiClient) startSpan(ctx context.Context, method, path string) (context.Context, trace.Span) {
+	tracer := c.tracer
+	if tracer == nil {
+		tracer = noopTracer
+	}
+
+	route := path
+	if i := strings.IndexByte(route, '?'); i >= 0 {
+		route = route[:i]
+	}
+	route = "/" + strings.TrimLeft(route, "/")
+
+	attributes := []attribute.KeyValue{
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", route),
+	}
+	if u, err := url.Parse(c.baseURL()); err == nil {
+		attributes = append(attributes, attribute.String("server.address", u.Hostname()))
+		if port, err := strconv.Atoi(u.Port()); err == nil {
+			attributes = append(attributes, attribute.Int("server.port", port))
+		}
+	}
+	if m := auditSecretPath.FindStringSubmatch(path); m != nil {
+		id, _ := strconv.Atoi(m[1])
+		attributes = append(attributes, attribute.Int("tss.secret_id", id))
+	}
+
+	name := method + " " + spanIDSegment.ReplaceAllString(route, "/{id}$1")
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+// endSpan records the outcome of a call made in attempts tries and ends its span
+func endSpan(span trace.Span, attempts int, err error) {
+	span.SetAttributes(
+		attribute.Int("tss.attempts", attempts),
+		attribute.Int("tss.retries", max(attempts-1, 0)),
+	)
+	if status := auditStatus(err); status != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
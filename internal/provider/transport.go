@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// baseTransport is a copy of the default transport as it was when the process
+// started. Every provider configuration's transport is cloned from it, so the
+// settings of one never carry over to another.
+var baseTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// transportSettings are the settings of a provider configuration that shape its
+// connections to Secret Server
+type transportSettings struct {
+	TLS   tlsSettings
+	Proxy proxySettings
+	// ConnectTimeout bounds establishing a connection; zero keeps the Go default
+	ConnectTimeout time.Duration
+}
+
+// key identifies the settings. It is a hash, so the proxy credentials are not
+// kept in it.
+func (s transportSettings) key() string {
+	data, _ := json.Marshal(s)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// newTransport returns a new transport with the settings applied
+func (s transportSettings) newTransport() (*http.Transport, error) {
+	transport := baseTransport.Clone()
+	if s.ConnectTimeout > 0 {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   s.ConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+	if s.TLS.isSet() {
+		config, err := s.TLS.config()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = config
+	}
+	if s.Proxy.isSet() {
+		if err := s.Proxy.apply(transport); err != nil {
+			return nil, err
+		}
+	}
+	return transport, nil
+}
+
+// providerTransport is the transport of the provider configurations with the
+// same transportSettings
+type providerTransport struct {
+	key  string
+	base http.RoundTripper
+
+	mu sync.Mutex
+	// throttledUntil is when the last throttled response through the transport
+	// asked to be retried. The SDK's errors carry no response headers, so its
+	// calls learn the Retry-After delay from here.
+	throttledUntil time.Time
+}
+
+// RoundTrip sends req, noting the deprecation notice of the response and the
+// Retry-After header of a throttled one
+func (t *providerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	deprecations.record(req.Method, req.URL.Path, res.Header)
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+		now := time.Now()
+		if after := parseRetryAfter(res.Header.Get("Retry-After"), now); after > 0 {
+			t.mu.Lock()
+			if until := now.Add(after); until.After(t.throttledUntil) {
+				t.throttledUntil = until
+			}
+			t.mu.Unlock()
+		}
+	}
+	return res, nil
+}
+
+// retryAfter returns how much longer the last throttled response through the
+// transport asked to wait, or 0
+func (t *providerTransport) retryAfter(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.throttledUntil.After(now) {
+		return t.throttledUntil.Sub(now)
+	}
+	return 0
+}
+
+// transports routes the requests of the SDK to the transport of the provider
+// configuration of their host. The SDK sends every request with a zero
+// http.Client, so through http.DefaultTransport, and takes no transport of its
+// own; the router is installed in its place once, and sends requests to hosts
+// no configuration connects to through baseTransport.
+var transports = &transportRouter{
+	byKey:  map[string]*providerTransport{},
+	byHost: map[string]*providerTransport{},
+}
+
+// transportRouter holds the transports of the provider configurations in the
+// process
+type transportRouter struct {
+	install sync.Once
+
+	mu     sync.Mutex
+	byKey  map[string]*providerTransport
+	byHost map[string]*providerTransport
+}
+
+// transport returns the transport of settings. Configurations with the same
+// settings share one, and with it their idle connections.
+func (r *transportRouter) transport(settings transportSettings) (*providerTransport, error) {
+	r.install.Do(func() {
+		http.DefaultTransport = r
+	})
+
+	key := settings.key()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if transport, ok := r.byKey[key]; ok {
+		return transport, nil
+	}
+	transport, err := settings.newTransport()
+	if err != nil {
+		return nil, err
+	}
+	r.byKey[key] = &providerTransport{key: key, base: transport}
+	return r.byKey[key], nil
+}
+
+// route sends the SDK's requests to the host of serverURL through transport.
+// A host that another configuration connects to with other settings is an
+// error, since the SDK's requests to it can only take one route.
+func (r *transportRouter) route(serverURL string, transport *providerTransport) error {
+	if transport == nil {
+		return nil
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return fmt.Errorf("invalid server URL: %w", err)
+	}
+	host := strings.ToLower(u.Host)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if routed, ok := r.byHost[host]; ok && routed.key != transport.key {
+		return fmt.Errorf("another configuration of the provider connects to %s with different TLS, proxy or connect_timeout settings; "+
+			"the Secret Server SDK sends every request to a host the same way, so configurations of one server must share these settings", host)
+	}
+	r.byHost[host] = transport
+	return nil
+}
+
+// RoundTrip sends req through the transport of its host
+func (r *transportRouter) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	transport, ok := r.byHost[strings.ToLower(req.URL.Host)]
+	r.mu.Unlock()
+	if !ok {
+		return baseTransport.RoundTrip(req)
+	}
+	return transport.RoundTrip(req)
+}
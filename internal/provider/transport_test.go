@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTransportRouterRoutesByHost(t *testing.T) {
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "direct")
+	}))
+	defer direct.Close()
+	// The proxy answers every request itself, so the proxied host need not exist
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "proxied "+r.Host)
+	}))
+	defer proxy.Close()
+
+	router := &transportRouter{byKey: map[string]*providerTransport{}, byHost: map[string]*providerTransport{}}
+	proxied, err := router.transport(transportSettings{Proxy: proxySettings{HTTPProxy: proxy.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := router.transport(transportSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := router.route("http://proxied.example.test", proxied); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.route(direct.URL, plain); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Transport: router}
+	for url, want := range map[string]string{
+		"http://proxied.example.test/api": "proxied proxied.example.test",
+		direct.URL + "/api":               "direct",
+	} {
+		res, err := client.Get(url)
+		if err != nil {
+			t.Fatalf("GET %s: %s", url, err)
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if string(body) != want {
+			t.Errorf("GET %s = %q, want %q", url, body, want)
+		}
+	}
+}
+
+func TestTransportRouterSharesIdenticalSettings(t *testing.T) {
+	router := &transportRouter{byKey: map[string]*providerTransport{}, byHost: map[string]*providerTransport{}}
+	settings := transportSettings{Proxy: proxySettings{SOCKS5Proxy: "127.0.0.1:1080"}}
+
+	first, err := router.transport(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := router.transport(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("configurations with the same settings do not share a transport")
+	}
+	if err := router.route("https://tss.example.test", first); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.route("https://TSS.example.test", second); err != nil {
+		t.Errorf("routing a host again with the same settings failed: %s", err)
+	}
+}
+
+func TestTransportRouterRejectsConflictingSettings(t *testing.T) {
+	router := &transportRouter{byKey: map[string]*providerTransport{}, byHost: map[string]*providerTransport{}}
+	socks, err := router.transport(transportSettings{Proxy: proxySettings{SOCKS5Proxy: "127.0.0.1:1080"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := router.transport(transportSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipVerify, err := router.transport(transportSettings{TLS: tlsSettings{SkipVerify: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := router.route("https://tss.example.test", socks); err != nil {
+		t.Fatal(err)
+	}
+	for name, transport := range map[string]*providerTransport{"no proxy": plain, "tls_skip_verify": skipVerify} {
+		err := router.route("https://tss.example.test/SecretServer", transport)
+		if err == nil || !strings.Contains(err.Error(), "different TLS, proxy or connect_timeout settings") {
+			t.Errorf("%s: routing a host with other settings returned %v", name, err)
+		}
+	}
+	if err := router.route("https://other.example.test", plain); err != nil {
+		t.Errorf("routing another host failed: %s", err)
+	}
+}
+
+func TestNewTransportLeavesBaseTransportUnchanged(t *testing.T) {
+	for _, settings := range []transportSettings{
+		{Proxy: proxySettings{SOCKS5Proxy: "127.0.0.1:1080"}},
+		{Proxy: proxySettings{SOCKS5Proxy: "127.0.0.1:1081"}},
+		{TLS: tlsSettings{SkipVerify: true, MinVersion: "1.3"}},
+	} {
+		transport, err := settings.newTransport()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if transport == baseTransport {
+			t.Fatal("newTransport returned the base transport")
+		}
+	}
+
+	if baseTransport.Proxy == nil {
+		t.Error("a SOCKS5 proxy removed the HTTP proxy of the base transport")
+	}
+	if baseTransport.TLSClientConfig != nil && baseTransport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("tls_skip_verify disabled verification on the base transport")
+	}
+}
+
+func TestNewTransportRejectsInvalidSettings(t *testing.T) {
+	for name, settings := range map[string]transportSettings{
+		"socks5 scheme":   {Proxy: proxySettings{SOCKS5Proxy: "http://127.0.0.1:1080"}},
+		"socks5 address":  {Proxy: proxySettings{SOCKS5Proxy: "127.0.0.1"}},
+		"tls min version": {TLS: tlsSettings{MinVersion: "1.1"}},
+		"cipher suite":    {TLS: tlsSettings{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}},
+	} {
+		if _, err := settings.newTransport(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
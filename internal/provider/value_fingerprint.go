@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// valueFingerprint returns a stable fingerprint of a field value, so plan output
+// shows whether a sensitive value changes without showing the value. It is
+// keyed with the provider's value_fingerprint_key, so a value cannot be guessed
+// from it without the key, and covers the field name, so equal values of
+// different fields differ.
+func valueFingerprint(key []byte, fieldName, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToLower(fieldName)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// fieldFingerprint returns the value_fingerprint of a field: null when fingerprints
+// are disabled or the field has no value, and unknown while the value is
+func fieldFingerprint(field SecretField, key []byte) types.String {
+	switch {
+	case key == nil || field.ItemValue.IsNull():
+		return types.StringNull()
+	case field.ItemValue.IsUnknown():
+		return types.StringUnknown()
+	}
+	return types.StringValue(valueFingerprint(key, field.FieldName.ValueString(), field.ItemValue.ValueString()))
+}
+
+// setValueFingerprints sets the value_fingerprint of every field
+func setValueFingerprints(fields []SecretField, key []byte) {
+	for i := range fields {
+		fields[i].ValueFingerprint = fieldFingerprint(fields[i], key)
+	}
+}
+
+// planValueFingerprints sets the planned value_fingerprint of every field, so the
+// plan shows a fingerprint rather than "(known after apply)" for known values
+func planValueFingerprints(ctx context.Context, plan *tfsdk.Plan, fields []SecretField, key []byte) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, field := range fields {
+		diags.Append(plan.SetAttribute(ctx, path.Root("fields").AtListIndex(i).AtName("value_fingerprint"), fieldFingerprint(field, key))...)
+	}
+	return diags
+}
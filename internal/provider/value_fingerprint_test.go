@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValueFingerprint(t *testing.T) {
+	key := []byte("fingerprint-key")
+	fingerprint := valueFingerprint(key, "Password", "hunter2")
+
+	if !strings.HasPrefix(fingerprint, "hmac:") || len(fingerprint) != len("hmac:")+32 {
+		t.Errorf("fingerprint %q is not a 128-bit hmac", fingerprint)
+	}
+	if valueFingerprint(key, "password", "hunter2") != fingerprint {
+		t.Error("the fingerprint depends on the case of the field name")
+	}
+	for name, other := range map[string]string{
+		"value":      valueFingerprint(key, "Password", "hunter3"),
+		"field name": valueFingerprint(key, "Notes", "hunter2"),
+		"key":        valueFingerprint([]byte("another-key"), "Password", "hunter2"),
+		// The field name and value are separated, so they cannot run together
+		"boundary": valueFingerprint(key, "Passwordh", "unter2"),
+	} {
+		if other == fingerprint {
+			t.Errorf("a different %s gives the same fingerprint", name)
+		}
+	}
+}
+
+func TestFieldFingerprint(t *testing.T) {
+	field := SecretField{FieldName: types.StringValue("password"), ItemValue: types.StringValue("hunter2")}
+	if fp := fieldFingerprint(field, nil); !fp.IsNull() {
+		t.Errorf("fingerprint without a key = %s, want null", fp)
+	}
+	if fp := fieldFingerprint(field, []byte("key")); fp.IsNull() || strings.Contains(fp.ValueString(), "hunter2") {
+		t.Errorf("fingerprint = %s", fp)
+	}
+	field.ItemValue = types.StringUnknown()
+	if fp := fieldFingerprint(field, []byte("key")); !fp.IsUnknown() {
+		t.Errorf("fingerprint of an unknown value = %s, want unknown", fp)
+	}
+}
@@ -5,8 +5,9 @@ import (
 	"flag"
 	"log"
 	"os"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 	"github.com/just_shrubs/terraform-provider-tss/v2/internal/provider"
 )
 
@@ -21,13 +22,36 @@ var (
 
 func main() {
 	var debug bool
+	var fips bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.BoolVar(&fips, "fips", false, "set to true to encrypt and decrypt state files with FIPS 140-3 approved algorithms only; requires GODEBUG=fips140=on")
 	flag.Parse()
 
-	if len(os.Args) >= 2 {
-		action := os.Args[1]
-		stateFile := os.Args[2]
+	if flag.Arg(0) == "generate-imports" {
+		if err := provider.GenerateImports(context.Background(), flag.Args()[1:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
+	if flag.Arg(0) == "scan-state" {
+		if err := provider.ScanState(context.Background(), flag.Args()[1:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
+	if flag.Arg(0) == "find-orphans" {
+		if err := provider.FindOrphans(context.Background(), flag.Args()[1:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
+	if args := flag.Args(); len(args) >= 2 {
+		action := args[0]
+		stateFile := args[1]
 
 		passphrase := os.Getenv("TFSTATE_PASSPHRASE")
 		if passphrase == "" {
@@ -37,12 +61,12 @@ func main() {
 
 		switch action {
 		case "encrypt":
-			err := provider.EncryptFile(passphrase, stateFile)
+			err := provider.EncryptFile(passphrase, stateFile, fips)
 			if err != nil {
 				log.Printf("[DEBUG] Error encrypting file: %v\n", err)
 			}
 		case "decrypt":
-			err := provider.DecryptFile(passphrase, stateFile)
+			err := provider.DecryptFile(passphrase, stateFile, fips)
 			if err != nil {
 				log.Printf("[DEBUG] Error decrypting file: %v\n", err)
 			}
@@ -52,13 +76,20 @@ func main() {
 		return
 	}
 
-	opts := providerserver.ServeOpts{
-		// TODO: Update this string with the published name of your provider.
-		Address: "github.com/just_shrubs/terraform-provider-tss",
-		Debug:   debug,
+	var opts []tf6server.ServeOpt
+	if debug {
+		opts = append(opts, tf6server.WithManagedDebug())
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	// TODO: Update this string with the published name of your provider.
+	err := tf6server.Serve("github.com/just_shrubs/terraform-provider-tss", provider.NewServer(version), opts...)
+
+	// Export the spans of the last second of the run
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if shutdownErr := provider.ShutdownTracing(ctx); shutdownErr != nil {
+		log.Printf("[WARN] %s", shutdownErr)
+	}
 
 	if err != nil {
 		log.Fatal(err.Error())